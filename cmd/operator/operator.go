@@ -13,6 +13,8 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
 	v1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/rest"
@@ -191,6 +193,14 @@ func run(c *cli.Context) {
 		log.Fatalf("Failed to add cluster API resources to the scheme: %v", err)
 	}
 
+	// CRDs are created through the client when components are reconciled in dry-run mode
+	if err := apiextensionsv1.AddToScheme(mgr.GetScheme()); err != nil {
+		log.Fatalf("Failed to add apiextensions v1 resources to the scheme: %v", err)
+	}
+	if err := apiextensionsv1beta1.AddToScheme(mgr.GetScheme()); err != nil {
+		log.Fatalf("Failed to add apiextensions v1beta1 resources to the scheme: %v", err)
+	}
+
 	// Create Service and ServiceMonitor objects to expose the metrics to Prometheus
 	metricsPort := c.Int(flagMetricsPort)
 	metricsServicePorts := []v1.ServicePort{
@@ -101,7 +101,7 @@ func TestRemoveAlertManager(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -160,7 +160,7 @@ func TestDisableAlertManager(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
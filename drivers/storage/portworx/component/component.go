@@ -61,14 +61,29 @@ func Register(name string, c PortworxComponent) {
 	components[name] = c
 }
 
+// Deregister removes the PortworxComponent with the given name from the global map of components.
+// This is used only for testing.
+func Deregister(name string) {
+	logrus.Debugf("Deregistering component %v from Portworx driver", name)
+	registerLock.Lock()
+	defer registerLock.Unlock()
+	delete(components, name)
+}
+
 // Get returns a PortworxComponent if present else returns (nil, false)
 func Get(name string) (PortworxComponent, bool) {
+	registerLock.Lock()
+	defer registerLock.Unlock()
 	c, exists := components[name]
 	return c, exists
 }
 
-// GetAll returns all the Portworx components that are registered
+// GetAll returns all the Portworx components that are registered, sorted in the order
+// they should be reconciled. Components are sorted by priority, with the smaller number
+// first, and components with the same priority are sorted by name.
 func GetAll() []PortworxComponent {
+	registerLock.Lock()
+	defer registerLock.Unlock()
 	componentsCopy := make([]PortworxComponent, 0)
 	for _, comp := range components {
 		componentsCopy = append(componentsCopy, comp)
@@ -85,11 +100,46 @@ func DeregisterAllComponents() {
 	components = make(map[string]PortworxComponent)
 }
 
+// DryRunComponent is implemented by components that can be reconciled in dry-run mode.
+// When dry-run is enabled only these components are initialized with a dry-run client,
+// and they are expected to log the objects they would create or update.
+type DryRunComponent interface {
+	// SupportsDryRun returns true if the component can be reconciled in dry-run mode
+	SupportsDryRun() bool
+}
+
+// IsDryRunSupported returns true if the given component opted in to dry-run mode
+func IsDryRunSupported(comp PortworxComponent) bool {
+	dryRunComp, ok := comp.(DryRunComponent)
+	return ok && dryRunComp.SupportsDryRun()
+}
+
+// dryRunClient is a client that submits all writes to the api server in dry-run mode
+type dryRunClient struct {
+	client.Client
+}
+
+// NewDryRunClient returns a client that submits all writes to the api server in
+// dry-run mode. Components initialized with it compute and validate the objects
+// they would create or update, without persisting them.
+func NewDryRunClient(k8sClient client.Client) client.Client {
+	return &dryRunClient{Client: client.NewDryRunClient(k8sClient)}
+}
+
+// IsDryRun returns true if the given client was created using NewDryRunClient
+func IsDryRun(k8sClient client.Client) bool {
+	_, ok := k8sClient.(*dryRunClient)
+	return ok
+}
+
 // byPriority data interface to sort Portworx components by their priority
 type byPriority []PortworxComponent
 
 func (e byPriority) Len() int      { return len(e) }
 func (e byPriority) Swap(i, j int) { e[i], e[j] = e[j], e[i] }
 func (e byPriority) Less(i, j int) bool {
-	return e[i].Priority() < e[j].Priority()
+	if e[i].Priority() != e[j].Priority() {
+		return e[i].Priority() < e[j].Priority()
+	}
+	return e[i].Name() < e[j].Name()
 }
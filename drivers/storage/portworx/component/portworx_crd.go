@@ -1,19 +1,25 @@
 package component
 
 import (
+	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/hashicorp/go-version"
 	pxutil "github.com/libopenstorage/operator/drivers/storage/portworx/util"
 	corev1 "github.com/libopenstorage/operator/pkg/apis/core/v1"
+	"github.com/libopenstorage/operator/pkg/util"
+	k8sutil "github.com/libopenstorage/operator/pkg/util/k8s"
 	apiextensionsops "github.com/portworx/sched-ops/k8s/apiextensions"
 	"github.com/sirupsen/logrus"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -21,10 +27,23 @@ import (
 const (
 	// PortworxCRDComponentName name of the Portworx CRDs component
 	PortworxCRDComponentName = "Portworx CRDs"
+
+	storageClusterCRDFile = "core_v1_storagecluster_crd.yaml"
+	storageNodeCRDFile    = "core_v1_storagenode_crd.yaml"
+
+	volumePlacementStrategyPlural = "volumeplacementstrategies"
+
+	registerCRDRetryInterval = 2 * time.Second
+	registerCRDTimeout       = 1 * time.Minute
 )
 
 type portworxCRD struct {
+	k8sClient                           client.Client
+	dryRun                              bool
+	recorder                            record.EventRecorder
 	isVolumePlacementStrategyCRDCreated bool
+	isStorageClusterCRDCreated          bool
+	isStorageNodeCRDCreated             bool
 	k8sVersion                          version.Version
 }
 
@@ -37,17 +56,24 @@ func (c *portworxCRD) Priority() int32 {
 }
 
 func (c *portworxCRD) Initialize(
-	_ client.Client,
+	k8sClient client.Client,
 	k8sVersion version.Version,
 	_ *runtime.Scheme,
-	_ record.EventRecorder,
+	recorder record.EventRecorder,
 ) {
-	// k8sClient is not needed as we use k8s.Instance for CRDs
+	// k8sClient is only used in dry-run mode, otherwise we use k8s.Instance for CRDs
+	c.k8sClient = k8sClient
+	c.dryRun = IsDryRun(k8sClient)
+	c.recorder = recorder
 	c.k8sVersion = k8sVersion
 }
 
+func (c *portworxCRD) SupportsDryRun() bool {
+	return true
+}
+
 func (c *portworxCRD) IsPausedForMigration(cluster *corev1.StorageCluster) bool {
-	return false
+	return util.ComponentsPausedForMigration(cluster)
 }
 
 func (c *portworxCRD) IsEnabled(cluster *corev1.StorageCluster) bool {
@@ -55,12 +81,43 @@ func (c *portworxCRD) IsEnabled(cluster *corev1.StorageCluster) bool {
 }
 
 func (c *portworxCRD) Reconcile(cluster *corev1.StorageCluster) error {
+	if c.dryRun {
+		if err := c.dryRunCRDs(cluster); err != nil {
+			return NewError(ErrCritical, err)
+		}
+		return nil
+	}
 	if !c.isVolumePlacementStrategyCRDCreated {
-		if err := c.createVolumePlacementStrategyCRD(); err != nil {
+		created, err := c.createVolumePlacementStrategyCRD()
+		if err != nil {
+			c.warningEvent(cluster, volumePlacementStrategyPlural, err)
 			return NewError(ErrCritical, err)
 		}
+		c.createdEvent(cluster, volumePlacementStrategyPlural, created)
 		c.isVolumePlacementStrategyCRDCreated = true
 	}
+
+	if !reconcileCoreCRDs(cluster) {
+		return nil
+	}
+	if !c.isStorageClusterCRDCreated {
+		created, err := c.createCoreCRD(storageClusterCRDFile, corev1.StorageClusterResourcePlural)
+		if err != nil {
+			c.warningEvent(cluster, corev1.StorageClusterResourcePlural, err)
+			return NewError(ErrCritical, err)
+		}
+		c.createdEvent(cluster, corev1.StorageClusterResourcePlural, created)
+		c.isStorageClusterCRDCreated = true
+	}
+	if !c.isStorageNodeCRDCreated {
+		created, err := c.createCoreCRD(storageNodeCRDFile, corev1.StorageNodeResourcePlural)
+		if err != nil {
+			c.warningEvent(cluster, corev1.StorageNodeResourcePlural, err)
+			return NewError(ErrCritical, err)
+		}
+		c.createdEvent(cluster, corev1.StorageNodeResourcePlural, created)
+		c.isStorageNodeCRDCreated = true
+	}
 	return nil
 }
 
@@ -71,27 +128,207 @@ func (c *portworxCRD) Delete(cluster *corev1.StorageCluster) error {
 
 func (c *portworxCRD) MarkDeleted() {
 	c.isVolumePlacementStrategyCRDCreated = false
+	c.isStorageClusterCRDCreated = false
+	c.isStorageNodeCRDCreated = false
 }
 
-func (c *portworxCRD) createVolumePlacementStrategyCRD() error {
-	logrus.Debugf("Creating VolumePlacementStrategy CRD")
+// createCoreCRD registers the given operator CRD if it is not present and validates
+// that it is established. Updates to the CRD are left to the respective controller.
+// Returns true if the CRD was created.
+func (c *portworxCRD) createCoreCRD(filename, plural string) (bool, error) {
+	logrus.Debugf("Creating %s CRD", plural)
 
+	k8sVer1_16, err := version.NewVersion("1.16")
+	if err != nil {
+		return false, err
+	}
+
+	created := false
+	if c.k8sVersion.GreaterThanOrEqual(k8sVer1_16) {
+		crd, err := k8sutil.GetCRDFromFile(filename, pxutil.CRDBaseDir())
+		if err != nil {
+			return false, err
+		}
+		_, err = apiextensionsops.Instance().GetCRD(crd.Name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			err = apiextensionsops.Instance().RegisterCRD(crd)
+			created = err == nil
+		}
+		if err != nil && !errors.IsAlreadyExists(err) {
+			return false, err
+		}
+		return created, apiextensionsops.Instance().ValidateCRD(crd.Name, 1*time.Minute, 5*time.Second)
+	}
+
+	crd, err := k8sutil.GetV1beta1CRDFromFile(filename, pxutil.DeprecatedCRDBaseDir())
+	if err != nil {
+		return false, err
+	}
+	_, err = apiextensionsops.Instance().GetCRDV1beta1(crd.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		err = apiextensionsops.Instance().RegisterCRDV1beta1(crd)
+		created = err == nil
+	}
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return false, err
+	}
+	resource := apiextensionsops.CustomResource{
+		Plural: plural,
+		Group:  corev1.SchemeGroupVersion.Group,
+	}
+	return created, apiextensionsops.Instance().ValidateCRDV1beta1(resource, 1*time.Minute, 5*time.Second)
+}
+
+// warningEvent records a warning event on the cluster for a CRD that could not be
+// created or validated
+func (c *portworxCRD) warningEvent(
+	cluster *corev1.StorageCluster,
+	plural string,
+	err error,
+) {
+	msg := fmt.Sprintf("Failed to create %s CRD. %v", plural, err)
+	k8sutil.WarningEvent(c.recorder, cluster, util.FailedComponentReason, msg)
+}
+
+// createdEvent records an event on the cluster if the CRD was created by the operator
+func (c *portworxCRD) createdEvent(
+	cluster *corev1.StorageCluster,
+	plural string,
+	created bool,
+) {
+	if !created {
+		return
+	}
+	msg := fmt.Sprintf("Created %s CRD", plural)
+	k8sutil.InfoEvent(c.recorder, cluster, util.CRDCreatedReason, msg)
+}
+
+// dryRunCRDs computes the CRDs the component would register and submits them to the
+// api server in dry-run mode. Nothing is persisted and the component state is unchanged.
+func (c *portworxCRD) dryRunCRDs(cluster *corev1.StorageCluster) error {
 	k8sVer1_16, err := version.NewVersion("1.16")
 	if err != nil {
 		return err
 	}
 
+	var crds []client.Object
+	if c.k8sVersion.GreaterThanOrEqual(k8sVer1_16) {
+		crds = append(crds, volumePlacementStrategyCRD())
+		if reconcileCoreCRDs(cluster) {
+			for _, filename := range []string{storageClusterCRDFile, storageNodeCRDFile} {
+				crd, err := k8sutil.GetCRDFromFile(filename, pxutil.CRDBaseDir())
+				if err != nil {
+					return err
+				}
+				crds = append(crds, crd)
+			}
+		}
+	} else {
+		crds = append(crds, volumePlacementStrategyDeprecatedCRD())
+		if reconcileCoreCRDs(cluster) {
+			for _, filename := range []string{storageClusterCRDFile, storageNodeCRDFile} {
+				crd, err := k8sutil.GetV1beta1CRDFromFile(filename, pxutil.DeprecatedCRDBaseDir())
+				if err != nil {
+					return err
+				}
+				crds = append(crds, crd)
+			}
+		}
+	}
+
+	for _, crd := range crds {
+		err := c.k8sClient.Create(context.TODO(), crd)
+		if errors.IsAlreadyExists(err) {
+			logrus.Infof("[dry-run] CRD %s already exists, skipping", crd.GetName())
+			continue
+		} else if err != nil {
+			return fmt.Errorf("dry-run failed for CRD %s: %v", crd.GetName(), err)
+		}
+		logrus.Infof("[dry-run] Would create CRD %s", crd.GetName())
+	}
+	return nil
+}
+
+func reconcileCoreCRDs(cluster *corev1.StorageCluster) bool {
+	enabled, err := strconv.ParseBool(cluster.Annotations[pxutil.AnnotationReconcileCoreCRDs])
+	return err == nil && enabled
+}
+
+// createVolumePlacementStrategyCRD registers the VolumePlacementStrategy CRD and
+// validates that it is established. Returns true if the CRD was created.
+func (c *portworxCRD) createVolumePlacementStrategyCRD() (bool, error) {
+	logrus.Debugf("Creating VolumePlacementStrategy CRD")
+
+	k8sVer1_16, err := version.NewVersion("1.16")
+	if err != nil {
+		return false, err
+	}
+
 	if c.k8sVersion.GreaterThanOrEqual(k8sVer1_16) {
 		return createAndValidateVPSCRD()
 	}
 	return createAndValidateVPSDeprecatedCRD()
 }
 
-func createAndValidateVPSCRD() error {
-	plural := "volumeplacementstrategies"
+func createAndValidateVPSCRD() (bool, error) {
+	crd := volumePlacementStrategyCRD()
+	created := false
+	existingCRD, err := apiextensionsops.Instance().GetCRD(crd.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		created, err = registerCRDWithRetry(crd)
+	} else if err == nil {
+		err = updateVPSCRD(existingCRD, crd)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return created, apiextensionsops.Instance().ValidateCRD(crd.Name, 1*time.Minute, 5*time.Second)
+}
+
+// updateVPSCRD updates the existing VolumePlacementStrategy CRD if the group, scope,
+// names or versions differ from the desired CRD. Only those fields are overwritten,
+// everything else on the existing CRD, like labels or conversion settings, is retained.
+func updateVPSCRD(
+	existingCRD *apiextensionsv1.CustomResourceDefinition,
+	desiredCRD *apiextensionsv1.CustomResourceDefinition,
+) error {
+	existingSpec := &existingCRD.Spec
+	desiredSpec := &desiredCRD.Spec
+	if existingSpec.Group == desiredSpec.Group &&
+		existingSpec.Scope == desiredSpec.Scope &&
+		crdNamesEqual(existingSpec.Names, desiredSpec.Names) &&
+		equality.Semantic.DeepEqual(existingSpec.Versions, desiredSpec.Versions) {
+		return nil
+	}
+
+	logrus.Infof("Updating CRD %s", existingCRD.Name)
+	updatedCRD := existingCRD.DeepCopy()
+	updatedCRD.Spec.Group = desiredSpec.Group
+	updatedCRD.Spec.Scope = desiredSpec.Scope
+	updatedCRD.Spec.Names.Singular = desiredSpec.Names.Singular
+	updatedCRD.Spec.Names.Plural = desiredSpec.Names.Plural
+	updatedCRD.Spec.Names.Kind = desiredSpec.Names.Kind
+	updatedCRD.Spec.Names.ShortNames = desiredSpec.Names.ShortNames
+	updatedCRD.Spec.Versions = desiredSpec.Versions
+	_, err := apiextensionsops.Instance().UpdateCRD(updatedCRD)
+	return err
+}
+
+// crdNamesEqual compares the names set by the operator. The list kind is
+// defaulted by the api server, so it is not compared.
+func crdNamesEqual(existing, desired apiextensionsv1.CustomResourceDefinitionNames) bool {
+	return existing.Singular == desired.Singular &&
+		existing.Plural == desired.Plural &&
+		existing.Kind == desired.Kind &&
+		equality.Semantic.DeepEqual(existing.ShortNames, desired.ShortNames)
+}
+
+func volumePlacementStrategyCRD() *apiextensionsv1.CustomResourceDefinition {
+	plural := volumePlacementStrategyPlural
 	group := "portworx.io"
 	crdName := fmt.Sprintf("%s.%s", plural, group)
-	crd := &apiextensionsv1.CustomResourceDefinition{
+	return &apiextensionsv1.CustomResourceDefinition{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: crdName,
 		},
@@ -128,21 +365,60 @@ func createAndValidateVPSCRD() error {
 			},
 		},
 	}
+}
+
+// registerCRDWithRetry registers the given CRD, retrying on transient api server errors
+// until it times out. It is a no-op if the CRD already exists. Returns true if the CRD
+// was created.
+func registerCRDWithRetry(crd *apiextensionsv1.CustomResourceDefinition) (bool, error) {
+	var lastErr error
+	err := wait.PollImmediate(registerCRDRetryInterval, registerCRDTimeout, func() (bool, error) {
+		lastErr = apiextensionsops.Instance().RegisterCRD(crd)
+		if lastErr == nil || errors.IsAlreadyExists(lastErr) {
+			return true, nil
+		} else if isTransientError(lastErr) {
+			logrus.Warnf("Failed to register CRD %s, will retry in %v. %v", crd.Name, registerCRDRetryInterval, lastErr)
+			return false, nil
+		}
+		return false, lastErr
+	})
+	if err == wait.ErrWaitTimeout {
+		return false, fmt.Errorf("timed out registering CRD %s: %v", crd.Name, lastErr)
+	} else if err != nil {
+		return false, err
+	}
+	return lastErr == nil, nil
+}
+
+func isTransientError(err error) bool {
+	return errors.IsInternalError(err) ||
+		errors.IsServerTimeout(err) ||
+		errors.IsTimeout(err) ||
+		errors.IsTooManyRequests(err) ||
+		errors.IsServiceUnavailable(err) ||
+		errors.IsConflict(err)
+}
 
-	err := apiextensionsops.Instance().RegisterCRD(crd)
+func createAndValidateVPSDeprecatedCRD() (bool, error) {
+	crd := volumePlacementStrategyDeprecatedCRD()
+	err := apiextensionsops.Instance().RegisterCRDV1beta1(crd)
 	if err != nil && !errors.IsAlreadyExists(err) {
-		return err
+		return false, err
 	}
 
-	return apiextensionsops.Instance().ValidateCRD(crdName, 1*time.Minute, 5*time.Second)
+	resource := apiextensionsops.CustomResource{
+		Plural: crd.Spec.Names.Plural,
+		Group:  crd.Spec.Group,
+	}
+	return err == nil, apiextensionsops.Instance().ValidateCRDV1beta1(resource, 1*time.Minute, 5*time.Second)
 }
 
-func createAndValidateVPSDeprecatedCRD() error {
+func volumePlacementStrategyDeprecatedCRD() *apiextensionsv1beta1.CustomResourceDefinition {
 	resource := apiextensionsops.CustomResource{
-		Plural: "volumeplacementstrategies",
+		Plural: volumePlacementStrategyPlural,
 		Group:  "portworx.io",
 	}
-	crd := &apiextensionsv1beta1.CustomResourceDefinition{
+	return &apiextensionsv1beta1.CustomResourceDefinition{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: fmt.Sprintf("%s.%s", resource.Plural, resource.Group),
 		},
@@ -169,13 +445,6 @@ func createAndValidateVPSDeprecatedCRD() error {
 			},
 		},
 	}
-
-	err := apiextensionsops.Instance().RegisterCRDV1beta1(crd)
-	if err != nil && !errors.IsAlreadyExists(err) {
-		return err
-	}
-
-	return apiextensionsops.Instance().ValidateCRDV1beta1(resource, 1*time.Minute, 5*time.Second)
 }
 
 // RegisterPortworxCRDComponent registers the Portworx CRD component
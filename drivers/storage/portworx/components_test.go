@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/rand"
+	"os"
 	"strconv"
 	"strings"
 	"testing"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/golang/mock/gomock"
+	goversion "github.com/hashicorp/go-version"
 	osdapi "github.com/libopenstorage/openstorage/api"
 	"github.com/libopenstorage/operator/drivers/storage/portworx/component"
 	"github.com/libopenstorage/operator/drivers/storage/portworx/manifest"
@@ -35,16 +37,19 @@ import (
 	rbacv1 "k8s.io/api/rbac/v1"
 	storagev1 "k8s.io/api/storage/v1"
 	storagev1beta1 "k8s.io/api/storage/v1beta1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	fakeextclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/version"
 	fakediscovery "k8s.io/client-go/discovery/fake"
 	fakek8sclient "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 	"k8s.io/client-go/tools/record"
 	api "k8s.io/kubernetes/pkg/apis/core"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -62,24 +67,26 @@ func TestOrderOfComponents(t *testing.T) {
 		componentNames[i] = comp.Name()
 	}
 	require.Len(t, components, 19)
-	// Higher priority components come first
-	require.ElementsMatch(t,
+	// Higher priority components come first, and components with
+	// the same priority are sorted by name
+	require.Equal(t,
 		[]string{
-			component.PSPComponentName,
 			component.AuthComponentName,
+			component.PSPComponentName,
 			component.TLSComponentName,
 			component.SCCComponentName,
 		},
 		componentNames[:4],
 	)
-	require.ElementsMatch(t,
+	require.Equal(t,
 		[]string{
-			component.PxRepoComponentName,
+			component.AlertManagerComponentName,
 			component.AutopilotComponentName,
 			component.CSIComponentName,
 			component.DisruptionBudgetComponentName,
 			component.LighthouseComponentName,
 			component.MonitoringComponentName,
+			component.PVCControllerComponentName,
 			component.PortworxAPIComponentName,
 			component.PortworxBasicComponentName,
 			component.PortworxCRDComponentName,
@@ -87,8 +94,7 @@ func TestOrderOfComponents(t *testing.T) {
 			component.PortworxStorageClassComponentName,
 			component.TelemetryComponentName,
 			component.PrometheusComponentName,
-			component.PVCControllerComponentName,
-			component.AlertManagerComponentName,
+			component.PxRepoComponentName,
 		},
 		componentNames[4:],
 	)
@@ -102,12 +108,91 @@ func TestOrderOfComponents(t *testing.T) {
 	}
 }
 
+// orderedComponent is a test component that records the order in which it is reconciled
+type orderedComponent struct {
+	name       string
+	priority   int32
+	reconciled *[]string
+	k8sClient  client.Client
+}
+
+func (c *orderedComponent) Initialize(k8sClient client.Client, _ goversion.Version, _ *runtime.Scheme, _ record.EventRecorder) {
+	c.k8sClient = k8sClient
+}
+
+func (c *orderedComponent) Name() string { return c.name }
+
+func (c *orderedComponent) Priority() int32 { return c.priority }
+
+func (c *orderedComponent) IsPausedForMigration(*corev1.StorageCluster) bool { return false }
+
+func (c *orderedComponent) IsEnabled(*corev1.StorageCluster) bool { return true }
+
+func (c *orderedComponent) Reconcile(*corev1.StorageCluster) error {
+	*c.reconciled = append(*c.reconciled, c.name)
+	return nil
+}
+
+func (c *orderedComponent) Delete(*corev1.StorageCluster) error { return nil }
+
+func (c *orderedComponent) MarkDeleted() {}
+
+func TestComponentsReconciledInPriorityOrder(t *testing.T) {
+	var reconciled []string
+	component.DeregisterAllComponents()
+	component.Register("dependent-b", &orderedComponent{
+		name: "dependent-b", priority: component.DefaultComponentPriority, reconciled: &reconciled,
+	})
+	component.Register("dependent-a", &orderedComponent{
+		name: "dependent-a", priority: component.DefaultComponentPriority, reconciled: &reconciled,
+	})
+	component.Register("crd", &orderedComponent{
+		name: "crd", priority: component.DefaultComponentPriority - 1, reconciled: &reconciled,
+	})
+
+	driver := portworx{}
+	err := driver.PreInstall(&corev1.StorageCluster{})
+	require.NoError(t, err)
+
+	// CRD component with a smaller priority number is reconciled before the components
+	// that depend on it, and components with the same priority are reconciled by name
+	require.Equal(t, []string{"crd", "dependent-a", "dependent-b"}, reconciled)
+
+	reregisterComponents()
+}
+
+func TestDeregisterComponents(t *testing.T) {
+	reregisterComponents()
+	component.RegisterPortworxCRDComponent()
+
+	_, exists := component.Get(component.PortworxCRDComponentName)
+	require.True(t, exists)
+
+	// TestCase: Deregister a single component
+	component.Deregister(component.PortworxCRDComponentName)
+	_, exists = component.Get(component.PortworxCRDComponentName)
+	require.False(t, exists)
+	_, exists = component.Get(component.PortworxBasicComponentName)
+	require.True(t, exists)
+	require.Len(t, component.GetAll(), 17)
+
+	// TestCase: Deregistering a component that is not registered is a no-op
+	component.Deregister("non-existent")
+	require.Len(t, component.GetAll(), 17)
+
+	// TestCase: Deregister all components
+	component.DeregisterAllComponents()
+	require.Empty(t, component.GetAll())
+
+	reregisterComponents()
+}
+
 func TestBasicComponentsInstall(t *testing.T) {
 	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset()))
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 	startPort := uint32(10001)
 
 	cluster := &corev1.StorageCluster{
@@ -293,7 +378,7 @@ func TestPxRepoInstallUninstall(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -357,7 +442,7 @@ func TestBasicInstallWithPortworxDisabled(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -416,7 +501,7 @@ func TestBasicInstallWithInternalKVDBDisabled(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -447,7 +532,7 @@ func TestPortworxWithCustomSecretsNamespace(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	secretsNamespace := "secrets-namespace"
 	cluster := &corev1.StorageCluster{
@@ -522,7 +607,7 @@ func TestPortworxAPIDaemonSetAlwaysDeploys(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -580,7 +665,7 @@ func TestPortworxProxyIsNotDeployedWhenClusterInKubeSystem(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 	startPort := uint32(10001)
 
 	cluster := &corev1.StorageCluster{
@@ -619,7 +704,7 @@ func TestPortworxProxyIsNotDeployedWhenUsingDefaultPort(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -653,7 +738,7 @@ func TestPortworxProxyIsNotDeployedWhenDisabledExplicitly(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 	startPort := uint32(10001)
 
 	cluster := &corev1.StorageCluster{
@@ -739,7 +824,7 @@ func TestPortworxWithCustomServiceAccount(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -841,7 +926,7 @@ func TestDisablePortworx(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 	startPort := uint32(10001)
 
 	cluster := &corev1.StorageCluster{
@@ -970,7 +1055,7 @@ func TestDefaultStorageClassesWithStork(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1159,7 +1244,7 @@ func TestDefaultStorageClassesWithoutStork(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1218,7 +1303,7 @@ func TestDefaultStorageClassesWithPortworxDisabled(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1255,7 +1340,7 @@ func TestDefaultStorageClassesWhenDisabled(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1303,7 +1388,7 @@ func TestPortworxServiceTypeWithOverride(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1405,7 +1490,7 @@ func TestPVCControllerInstall(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1424,6 +1509,456 @@ func TestPVCControllerInstall(t *testing.T) {
 	verifyPVCControllerDeployment(t, cluster, k8sClient, "pvcControllerDeployment.yaml")
 }
 
+func TestPortworxCRDReconcilesCoreCRDs(t *testing.T) {
+	fakeExtClient := fakeextclient.NewSimpleClientset()
+	apiextensionsops.SetInstance(apiextensionsops.New(fakeExtClient))
+	component.DeregisterAllComponents()
+	component.RegisterPortworxCRDComponent()
+	pxutil.CRDBaseDir = func() string {
+		return "../../../deploy/crds"
+	}
+	defer func() {
+		reregisterComponents()
+		pxutil.CRDBaseDir = func() string {
+			return pxutil.OperatorCRDsDir
+		}
+	}()
+
+	k8sVersion, _ := goversion.NewVersion("1.22.0")
+	crdComponent, _ := component.Get(component.PortworxCRDComponentName)
+	crdComponent.Initialize(testutil.FakeK8sClient(), *k8sVersion, runtime.NewScheme(), record.NewFakeRecorder(10))
+
+	vpsCRDName := "volumeplacementstrategies.portworx.io"
+	clusterCRDName := fmt.Sprintf("%s.%s", corev1.StorageClusterResourcePlural, corev1.SchemeGroupVersion.Group)
+	nodeCRDName := fmt.Sprintf("%s.%s", corev1.StorageNodeResourcePlural, corev1.SchemeGroupVersion.Group)
+	activateCRDs := func(crdNames ...string) {
+		for _, crdName := range crdNames {
+			go func(crdName string) {
+				err := testutil.ActivateCRDWhenCreated(fakeExtClient, crdName)
+				require.NoError(t, err)
+			}(crdName)
+		}
+	}
+
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+	}
+
+	// TestCase: Core CRDs are not reconciled by default
+	activateCRDs(vpsCRDName)
+	err := crdComponent.Reconcile(cluster)
+	require.NoError(t, err)
+
+	crds, err := fakeExtClient.ApiextensionsV1().CustomResourceDefinitions().List(context.TODO(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, crds.Items, 1)
+	require.Equal(t, vpsCRDName, crds.Items[0].Name)
+
+	// TestCase: Core CRDs are registered and validated when enabled
+	cluster.Annotations = map[string]string{
+		pxutil.AnnotationReconcileCoreCRDs: "true",
+	}
+	activateCRDs(clusterCRDName, nodeCRDName)
+	err = crdComponent.Reconcile(cluster)
+	require.NoError(t, err)
+
+	crds, err = fakeExtClient.ApiextensionsV1().CustomResourceDefinitions().List(context.TODO(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, crds.Items, 3)
+	for _, crdName := range []string{clusterCRDName, nodeCRDName} {
+		crd, err := fakeExtClient.ApiextensionsV1().CustomResourceDefinitions().Get(context.TODO(), crdName, metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Equal(t, corev1.SchemeGroupVersion.Group, crd.Spec.Group)
+		require.Equal(t, apiextensionsv1.Established, crd.Status.Conditions[0].Type)
+	}
+
+	// TestCase: Deleted CRDs are not recreated until the component is marked deleted
+	err = fakeExtClient.ApiextensionsV1().CustomResourceDefinitions().Delete(context.TODO(), nodeCRDName, metav1.DeleteOptions{})
+	require.NoError(t, err)
+	err = crdComponent.Reconcile(cluster)
+	require.NoError(t, err)
+
+	_, err = fakeExtClient.ApiextensionsV1().CustomResourceDefinitions().Get(context.TODO(), nodeCRDName, metav1.GetOptions{})
+	require.True(t, errors.IsNotFound(err))
+
+	crdComponent.MarkDeleted()
+	activateCRDs(nodeCRDName)
+	err = crdComponent.Reconcile(cluster)
+	require.NoError(t, err)
+
+	_, err = fakeExtClient.ApiextensionsV1().CustomResourceDefinitions().Get(context.TODO(), nodeCRDName, metav1.GetOptions{})
+	require.NoError(t, err)
+}
+
+func TestPortworxCRDRetriesTransientErrors(t *testing.T) {
+	fakeExtClient := fakeextclient.NewSimpleClientset()
+	apiextensionsops.SetInstance(apiextensionsops.New(fakeExtClient))
+	component.DeregisterAllComponents()
+	component.RegisterPortworxCRDComponent()
+	defer reregisterComponents()
+
+	k8sVersion, _ := goversion.NewVersion("1.22.0")
+	crdComponent, _ := component.Get(component.PortworxCRDComponentName)
+	crdComponent.Initialize(testutil.FakeK8sClient(), *k8sVersion, runtime.NewScheme(), record.NewFakeRecorder(10))
+
+	vpsCRDName := "volumeplacementstrategies.portworx.io"
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+	}
+
+	// TestCase: CRD registration fails twice with a transient error, then succeeds
+	createAttempts := 0
+	fakeExtClient.PrependReactor("create", "customresourcedefinitions",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			createAttempts++
+			if createAttempts <= 2 {
+				return true, nil, errors.NewInternalError(fmt.Errorf("etcdserver: leader changed"))
+			}
+			return false, nil, nil
+		})
+	go func() {
+		err := testutil.ActivateCRDWhenCreated(fakeExtClient, vpsCRDName)
+		require.NoError(t, err)
+	}()
+
+	err := crdComponent.Reconcile(cluster)
+	require.NoError(t, err)
+	require.Equal(t, 3, createAttempts)
+
+	_, err = fakeExtClient.ApiextensionsV1().CustomResourceDefinitions().Get(context.TODO(), vpsCRDName, metav1.GetOptions{})
+	require.NoError(t, err)
+
+	// TestCase: CRD that already exists is not registered again
+	crdComponent.MarkDeleted()
+	err = crdComponent.Reconcile(cluster)
+	require.NoError(t, err)
+	require.Equal(t, 3, createAttempts)
+
+	// TestCase: Non-transient errors are not retried
+	fakeExtClient = fakeextclient.NewSimpleClientset()
+	apiextensionsops.SetInstance(apiextensionsops.New(fakeExtClient))
+	createAttempts = 0
+	fakeExtClient.PrependReactor("create", "customresourcedefinitions",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			createAttempts++
+			return true, nil, errors.NewForbidden(
+				schema.GroupResource{Resource: "customresourcedefinitions"}, vpsCRDName, fmt.Errorf("denied"))
+		})
+	crdComponent.MarkDeleted()
+	err = crdComponent.Reconcile(cluster)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "denied")
+	require.Equal(t, 1, createAttempts)
+}
+
+func TestPortworxCRDUpdatesVolumePlacementStrategyCRD(t *testing.T) {
+	fakeExtClient := fakeextclient.NewSimpleClientset()
+	apiextensionsops.SetInstance(apiextensionsops.New(fakeExtClient))
+	component.DeregisterAllComponents()
+	component.RegisterPortworxCRDComponent()
+	defer reregisterComponents()
+
+	k8sVersion, _ := goversion.NewVersion("1.22.0")
+	crdComponent, _ := component.Get(component.PortworxCRDComponentName)
+	crdComponent.Initialize(testutil.FakeK8sClient(), *k8sVersion, runtime.NewScheme(), record.NewFakeRecorder(10))
+
+	vpsCRDName := "volumeplacementstrategies.portworx.io"
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+	}
+
+	// TestCase: CRD is created if not present
+	go func() {
+		err := testutil.ActivateCRDWhenCreated(fakeExtClient, vpsCRDName)
+		require.NoError(t, err)
+	}()
+	err := crdComponent.Reconcile(cluster)
+	require.NoError(t, err)
+
+	expectedCRD, err := fakeExtClient.ApiextensionsV1().CustomResourceDefinitions().Get(context.TODO(), vpsCRDName, metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Len(t, expectedCRD.Spec.Versions, 2)
+
+	updateCount := 0
+	fakeExtClient.PrependReactor("update", "customresourcedefinitions",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			updateCount++
+			return false, nil, nil
+		})
+
+	// TestCase: CRD is not updated if it already matches the desired spec
+	crdComponent.MarkDeleted()
+	err = crdComponent.Reconcile(cluster)
+	require.NoError(t, err)
+	require.Equal(t, 0, updateCount)
+
+	// TestCase: CRD with an outdated spec is updated, retaining the other fields
+	outdatedCRD := expectedCRD.DeepCopy()
+	outdatedCRD.ResourceVersion = "100"
+	outdatedCRD.Labels = map[string]string{"foo": "bar"}
+	outdatedCRD.Spec.Names.ListKind = "VolumePlacementStrategyList"
+	outdatedCRD.Spec.Names.ShortNames = []string{"vps"}
+	outdatedCRD.Spec.Versions = outdatedCRD.Spec.Versions[1:]
+	outdatedCRD.Spec.Versions[0].Served = true
+	outdatedCRD.Spec.Versions[0].Storage = true
+	_, err = fakeExtClient.ApiextensionsV1().CustomResourceDefinitions().Update(context.TODO(), outdatedCRD, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	updateCount = 0
+
+	crdComponent.MarkDeleted()
+	err = crdComponent.Reconcile(cluster)
+	require.NoError(t, err)
+	require.Equal(t, 1, updateCount)
+
+	actualCRD, err := fakeExtClient.ApiextensionsV1().CustomResourceDefinitions().Get(context.TODO(), vpsCRDName, metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, expectedCRD.Spec.Versions, actualCRD.Spec.Versions)
+	require.Equal(t, expectedCRD.Spec.Names.ShortNames, actualCRD.Spec.Names.ShortNames)
+	require.Equal(t, "VolumePlacementStrategyList", actualCRD.Spec.Names.ListKind)
+	require.Equal(t, map[string]string{"foo": "bar"}, actualCRD.Labels)
+	require.Equal(t, "100", actualCRD.ResourceVersion)
+	require.Equal(t, expectedCRD.Status, actualCRD.Status)
+
+	// TestCase: Only the list kind defaulted by the api server differs, so no update
+	updateCount = 0
+	crdComponent.MarkDeleted()
+	err = crdComponent.Reconcile(cluster)
+	require.NoError(t, err)
+	require.Equal(t, 0, updateCount)
+}
+
+func TestPortworxCRDPausedForMigration(t *testing.T) {
+	fakeExtClient := fakeextclient.NewSimpleClientset()
+	apiextensionsops.SetInstance(apiextensionsops.New(fakeExtClient))
+	component.DeregisterAllComponents()
+	component.RegisterPortworxCRDComponent()
+	defer reregisterComponents()
+
+	k8sVersion, _ := goversion.NewVersion("1.22.0")
+	driver := portworx{
+		k8sClient:  testutil.FakeK8sClient(),
+		k8sVersion: k8sVersion,
+		scheme:     runtime.NewScheme(),
+		recorder:   record.NewFakeRecorder(10),
+	}
+	driver.initializeComponents()
+
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+			Annotations: map[string]string{
+				constants.AnnotationMigrationApproved:       "true",
+				constants.AnnotationPauseComponentMigration: "true",
+			},
+		},
+	}
+
+	// TestCase: CRDs are not created while components are paused for migration
+	crdComponent, _ := component.Get(component.PortworxCRDComponentName)
+	require.True(t, crdComponent.IsPausedForMigration(cluster))
+	err := driver.PreInstall(cluster)
+	require.NoError(t, err)
+
+	crds, err := fakeExtClient.ApiextensionsV1().CustomResourceDefinitions().List(context.TODO(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Empty(t, crds.Items)
+
+	// TestCase: CRDs are created once the migration resumes the components
+	cluster.Annotations[constants.AnnotationPauseComponentMigration] = "false"
+	require.False(t, crdComponent.IsPausedForMigration(cluster))
+	activateErr := make(chan error, 1)
+	go func() {
+		activateErr <- testutil.ActivateCRDWhenCreated(fakeExtClient, "volumeplacementstrategies.portworx.io")
+	}()
+	err = driver.PreInstall(cluster)
+	require.NoError(t, err)
+	require.NoError(t, <-activateErr)
+
+	crds, err = fakeExtClient.ApiextensionsV1().CustomResourceDefinitions().List(context.TODO(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, crds.Items, 1)
+	require.Equal(t, "volumeplacementstrategies.portworx.io", crds.Items[0].Name)
+}
+
+func TestPortworxCRDEvents(t *testing.T) {
+	fakeExtClient := fakeextclient.NewSimpleClientset()
+	apiextensionsops.SetInstance(apiextensionsops.New(fakeExtClient))
+	component.DeregisterAllComponents()
+	component.RegisterPortworxCRDComponent()
+	defer reregisterComponents()
+
+	k8sVersion, _ := goversion.NewVersion("1.22.0")
+	recorder := record.NewFakeRecorder(10)
+	crdComponent, _ := component.Get(component.PortworxCRDComponentName)
+	crdComponent.Initialize(testutil.FakeK8sClient(), *k8sVersion, runtime.NewScheme(), recorder)
+
+	vpsCRDName := "volumeplacementstrategies.portworx.io"
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+	}
+
+	// TestCase: Warning event is raised when the CRD cannot be created
+	fakeExtClient.PrependReactor("create", "customresourcedefinitions",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.NewForbidden(
+				schema.GroupResource{Resource: "customresourcedefinitions"}, vpsCRDName, fmt.Errorf("denied"))
+		})
+	err := crdComponent.Reconcile(cluster)
+	require.Error(t, err)
+	require.Len(t, recorder.Events, 1)
+	require.Contains(t, <-recorder.Events,
+		fmt.Sprintf("%v %v Failed to create volumeplacementstrategies CRD",
+			v1.EventTypeWarning, util.FailedComponentReason))
+
+	// TestCase: Normal event is raised when the CRD is created
+	fakeExtClient = fakeextclient.NewSimpleClientset()
+	apiextensionsops.SetInstance(apiextensionsops.New(fakeExtClient))
+	go func() {
+		err := testutil.ActivateCRDWhenCreated(fakeExtClient, vpsCRDName)
+		require.NoError(t, err)
+	}()
+	err = crdComponent.Reconcile(cluster)
+	require.NoError(t, err)
+	require.Len(t, recorder.Events, 1)
+	require.Contains(t, <-recorder.Events,
+		fmt.Sprintf("%v %v Created volumeplacementstrategies CRD",
+			v1.EventTypeNormal, util.CRDCreatedReason))
+
+	// TestCase: No event is raised if the CRD already exists
+	crdComponent.MarkDeleted()
+	err = crdComponent.Reconcile(cluster)
+	require.NoError(t, err)
+	require.Empty(t, recorder.Events)
+}
+
+func TestPortworxCRDDryRun(t *testing.T) {
+	fakeExtClient := fakeextclient.NewSimpleClientset()
+	apiextensionsops.SetInstance(apiextensionsops.New(fakeExtClient))
+	component.DeregisterAllComponents()
+	component.RegisterPortworxCRDComponent()
+	pxutil.CRDBaseDir = func() string {
+		return "../../../deploy/crds"
+	}
+	pxutil.DeprecatedCRDBaseDir = func() string {
+		return "../../../deploy/crds/deprecated"
+	}
+	defer func() {
+		reregisterComponents()
+		pxutil.CRDBaseDir = func() string {
+			return pxutil.OperatorCRDsDir
+		}
+		pxutil.DeprecatedCRDBaseDir = func() string {
+			return pxutil.OperatorDeprecatedCRDsDir
+		}
+	}()
+
+	k8sClient := testutil.FakeK8sClient()
+	require.False(t, component.IsDryRun(k8sClient))
+	dryRunClient := component.NewDryRunClient(k8sClient)
+	require.True(t, component.IsDryRun(dryRunClient))
+
+	k8sVersion, _ := goversion.NewVersion("1.22.0")
+	crdComponent, _ := component.Get(component.PortworxCRDComponentName)
+	crdComponent.Initialize(dryRunClient, *k8sVersion, runtime.NewScheme(), record.NewFakeRecorder(10))
+
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+			Annotations: map[string]string{
+				pxutil.AnnotationReconcileCoreCRDs: "true",
+			},
+		},
+	}
+
+	// TestCase: No CRDs are created in dry-run mode
+	err := crdComponent.Reconcile(cluster)
+	require.NoError(t, err)
+
+	crds, err := fakeExtClient.ApiextensionsV1().CustomResourceDefinitions().List(context.TODO(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Empty(t, crds.Items)
+
+	crdList := &apiextensionsv1.CustomResourceDefinitionList{}
+	err = testutil.List(k8sClient, crdList)
+	require.NoError(t, err)
+	require.Empty(t, crdList.Items)
+
+	// TestCase: No CRDs are created in dry-run mode on older k8s versions
+	k8sVersion, _ = goversion.NewVersion("1.15.0")
+	crdComponent.Initialize(dryRunClient, *k8sVersion, runtime.NewScheme(), record.NewFakeRecorder(10))
+	err = crdComponent.Reconcile(cluster)
+	require.NoError(t, err)
+
+	deprecatedCRDs, err := fakeExtClient.ApiextensionsV1beta1().CustomResourceDefinitions().List(context.TODO(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Empty(t, deprecatedCRDs.Items)
+
+	deprecatedCRDList := &apiextensionsv1beta1.CustomResourceDefinitionList{}
+	err = testutil.List(k8sClient, deprecatedCRDList)
+	require.NoError(t, err)
+	require.Empty(t, deprecatedCRDList.Items)
+}
+
+func TestComponentsDryRunOnlyForOptedInComponents(t *testing.T) {
+	fakeExtClient := fakeextclient.NewSimpleClientset()
+	apiextensionsops.SetInstance(apiextensionsops.New(fakeExtClient))
+	os.Setenv(pxutil.EnvKeyComponentsDryRun, "true")
+	defer func() {
+		os.Unsetenv(pxutil.EnvKeyComponentsDryRun)
+		reregisterComponents()
+	}()
+
+	var reconciled []string
+	otherComponent := &orderedComponent{
+		name: "other", priority: component.DefaultComponentPriority, reconciled: &reconciled,
+	}
+	component.DeregisterAllComponents()
+	component.RegisterPortworxCRDComponent()
+	component.Register("other", otherComponent)
+
+	k8sClient := testutil.FakeK8sClient()
+	k8sVersion, _ := goversion.NewVersion("1.22.0")
+	driver := portworx{
+		k8sClient:  k8sClient,
+		k8sVersion: k8sVersion,
+		scheme:     runtime.NewScheme(),
+		recorder:   record.NewFakeRecorder(10),
+	}
+	driver.initializeComponents()
+
+	// TestCase: Component that does not opt in gets the regular client
+	require.False(t, component.IsDryRunSupported(otherComponent))
+	require.Equal(t, k8sClient, otherComponent.k8sClient)
+	require.False(t, component.IsDryRun(otherComponent.k8sClient))
+
+	// TestCase: portworxCRD opted in and does not register any CRDs
+	crdComponent, _ := component.Get(component.PortworxCRDComponentName)
+	require.True(t, component.IsDryRunSupported(crdComponent))
+	err := driver.PreInstall(&corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "px-cluster", Namespace: "kube-test"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"other"}, reconciled)
+
+	crds, err := fakeExtClient.ApiextensionsV1().CustomResourceDefinitions().List(context.TODO(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Empty(t, crds.Items)
+}
+
 func TestPVCControllerInstallWithK8s1_22(t *testing.T) {
 	versionClient := fakek8sclient.NewSimpleClientset()
 	coreops.SetInstance(coreops.New(versionClient))
@@ -1435,7 +1970,7 @@ func TestPVCControllerInstallWithK8s1_22(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1490,7 +2025,7 @@ func TestPVCControllerWithInvalidValue(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1527,7 +2062,7 @@ func TestPVCControllerInstallInKubeSystemNamespace(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1566,7 +2101,7 @@ func TestPVCControllerInstallInNonKubeSystemNamespace(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1624,7 +2159,7 @@ func TestPVCControllerInstallForOpenshift(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1649,7 +2184,7 @@ func TestPVCControllerInstallForPKS(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1682,7 +2217,7 @@ func TestPVCControllerInstallForEKS(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1715,7 +2250,7 @@ func TestPVCControllerInstallForGKE(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1748,7 +2283,7 @@ func TestPVCControllerInstallForAKS(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1790,7 +2325,7 @@ func TestPVCControllerWhenPVCControllerDisabledExplicitly(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1832,7 +2367,7 @@ func TestPVCControllerInstallWithPortworxDisabled(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1959,7 +2494,7 @@ func TestPVCControllerCustomCPU(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -2026,7 +2561,7 @@ func TestPVCControllerCustomPorts(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -2099,7 +2634,7 @@ func TestPVCControllerRollbackImageChanges(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -2147,7 +2682,7 @@ func TestPVCControllerImageWithNewerK8sVersion(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -2267,7 +2802,7 @@ func TestPVCControllerRollbackCommandChanges(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -2308,7 +2843,7 @@ func TestLighthouseInstall(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -2414,7 +2949,7 @@ func TestLighthouseServiceTypeForAKS(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -2449,7 +2984,7 @@ func TestLighthouseServiceTypeForGKE(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -2484,7 +3019,7 @@ func TestLighthouseServiceTypeForEKS(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -2519,7 +3054,7 @@ func TestLighthouseServiceTypeWithOverride(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -2623,7 +3158,7 @@ func TestLighthouseWithDesiredImage(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -2668,7 +3203,7 @@ func TestLighthouseImageChange(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -2709,7 +3244,7 @@ func TestLighthouseConfigInitImageChange(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -2755,7 +3290,7 @@ func TestLighthouseStorkConnectorImageChange(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -2801,7 +3336,7 @@ func TestLighthouseWithoutImageTag(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -2839,7 +3374,7 @@ func TestLighthouseSidecarsOverrideWithEnv(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -2887,7 +3422,7 @@ func TestAutopilotInstall(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -3034,7 +3569,7 @@ func TestAutopilotWithEnvironmentVariables(t *testing.T) {
 	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset()))
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
-	recorder := record.NewFakeRecorder(0)
+	recorder := record.NewFakeRecorder(10)
 	driver := portworx{}
 	driver.Init(k8sClient, runtime.NewScheme(), recorder)
 
@@ -3294,7 +3829,7 @@ func TestAutopilotWithDesiredImage(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -3339,7 +3874,7 @@ func TestAutopilotImageChange(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -3380,7 +3915,7 @@ func TestAutopilotArgumentsChange(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -3435,7 +3970,7 @@ func TestAutopilotConfigArgumentsChange(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -3487,7 +4022,7 @@ func TestAutopilotEnvVarsChange(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -3553,7 +4088,7 @@ func TestAutopilotCPUChange(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -3635,7 +4170,7 @@ func TestAutopilotVolumesChange(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	volumeSpecs := []corev1.VolumeSpec{
 		{
@@ -4555,7 +5090,7 @@ func TestDisableSecurity(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -4611,7 +5146,7 @@ func TestCSIInstallDisable(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -4668,7 +5203,7 @@ func TestCSIInstall(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -4804,7 +5339,7 @@ func TestCSIInstallWithk8s1_13(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -4917,7 +5452,7 @@ func TestCSIInstallWithk8s1_20(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -5014,7 +5549,7 @@ func TestCSIInstallEphemeralWithK8s1_17VersionAndPX2_5(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -5064,7 +5599,7 @@ func TestCSIInstallEphemeralWithK8s1_20VersionAndPX2_5(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -5134,7 +5669,7 @@ func TestCSIInstallWithk8s1_21_px210(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -5186,7 +5721,7 @@ func TestCSIInstallWithPKS(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -5252,7 +5787,7 @@ func TestCSIInstallShouldCreateNodeInfoCRD(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -5360,7 +5895,7 @@ func TestCSIInstallWithDeprecatedCSIDriverName(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	// Install with Portworx version 2.2+ and deprecated CSI driver name
 	// We should use add the resizer sidecar and use the old driver name.
@@ -5425,7 +5960,7 @@ func TestCSIInstallWithAlphaFeaturesDisabled(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -5580,7 +6115,7 @@ func TestCSIInstallWithTopology(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -5650,7 +6185,7 @@ func TestCSIClusterRoleK8sVersionGreaterThan_1_14(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -5689,7 +6224,7 @@ func TestCSI_1_0_ChangeImageVersions(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -5798,7 +6333,7 @@ func TestCSI_0_3_ChangeImageVersions(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -5865,7 +6400,7 @@ func TestCSIChangeKubernetesVersions(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -5971,7 +6506,7 @@ func TestCSI_0_3_ImagePullSecretChange(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	imagePullSecret := "pull-secret"
 	cluster := &corev1.StorageCluster{
@@ -6063,7 +6598,7 @@ func TestCSI_0_3_TolerationsChange(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	tolerations := []v1.Toleration{
 		{
@@ -6191,7 +6726,7 @@ func TestCSI_0_3_NodeAffinityChange(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	nodeAffinity := &v1.NodeAffinity{
 		RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{
@@ -6302,7 +6837,7 @@ func TestPrometheusUpgradeDefaultDesiredImages(t *testing.T) {
 
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -6434,7 +6969,7 @@ func TestPrometheusInstall(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -6603,7 +7138,7 @@ func TestCompleteInstallDuringMigration(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -6709,7 +7244,7 @@ func TestCompleteInstallWithImagePullPolicy(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -6861,7 +7396,7 @@ func TestCompleteInstallWithCustomRegistryChange(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 	startPort := uint32(10001)
 
 	customRegistry := "test-registry:1111"
@@ -7444,7 +7979,7 @@ func TestCompleteInstallWithCustomRegistryChangeForK8s_1_14(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	customRegistry := "test-registry:1111"
 	cluster := &corev1.StorageCluster{
@@ -7586,7 +8121,7 @@ func TestCompleteInstallWithCustomRegistryChangeForK8s_1_12(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	customRegistry := "test-registry:1111"
 	cluster := &corev1.StorageCluster{
@@ -7727,7 +8262,7 @@ func TestCompleteInstallWithCustomRepoRegistryChange(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 	startPort := uint32(10001)
 
 	customRepo := "test-registry:1111/test-repo"
@@ -8417,7 +8952,7 @@ func TestCompleteInstallWithCustomRepoRegistryChangeForK8s_1_14(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	customRepo := "test-registry:1111/test-repo"
 	cluster := &corev1.StorageCluster{
@@ -8587,7 +9122,7 @@ func TestCompleteInstallWithCustomRepoRegistryChangeForK8s_1_12(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	customRepo := "test-registry:1111/test-repo"
 	cluster := &corev1.StorageCluster{
@@ -8829,7 +9364,7 @@ func TestCompleteInstallWithImagePullSecretChange(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 	startPort := uint32(10001)
 
 	imagePullSecret := "pull-secret"
@@ -9241,7 +9776,7 @@ func TestCompleteInstallWithTolerationsChange(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 	startPort := uint32(10001)
 
 	tolerations := []v1.Toleration{
@@ -9755,7 +10290,7 @@ func TestCompleteInstallWithNodeAffinityChange(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 	startPort := uint32(10001)
 
 	nodeAffinity := &v1.NodeAffinity{
@@ -10144,7 +10679,7 @@ func TestRemovePVCController(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -10204,7 +10739,7 @@ func TestDisablePVCController(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -10263,7 +10798,7 @@ func TestRemoveLighthouse(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -10333,7 +10868,7 @@ func TestDisableLighthouse(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -10402,7 +10937,7 @@ func TestRemoveAutopilot(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -10470,7 +11005,7 @@ func TestDisableAutopilot(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -10544,7 +11079,7 @@ func TestDisableCSI_0_3(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -10642,7 +11177,7 @@ func TestDisableCSI_1_0(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -10749,7 +11284,7 @@ func TestMonitoringMetricsEnabled(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	pxutil.SpecsBaseDir = func() string {
 		return "../../../bin/configs"
@@ -10847,7 +11382,7 @@ func TestDisableMonitoring(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	pxutil.SpecsBaseDir = func() string {
 		return "../../../bin/configs"
@@ -10914,7 +11449,7 @@ func TestRemovePrometheus(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -11016,7 +11551,7 @@ func TestDisablePrometheus(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -11175,7 +11710,7 @@ func TestPodDisruptionBudgetEnabled(t *testing.T) {
 	component.RegisterDisruptionBudgetComponent()
 
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	// TestCase: Do not create KVDB PDB if not using internal KVDB
 	cluster.Spec.Kvdb = &corev1.KvdbSpec{
@@ -11207,6 +11742,13 @@ func TestPodDisruptionBudgetEnabled(t *testing.T) {
 	require.Equal(t, cluster.Name, pdbList.Items[0].Spec.Selector.MatchLabels[constants.LabelKeyClusterName])
 	require.Equal(t, constants.LabelValueTrue, pdbList.Items[0].Spec.Selector.MatchLabels[constants.LabelKeyKVDBPod])
 
+	expectedPDB := testutil.GetExpectedPDB(t, "kvdbPodDisruptionBudget.yaml")
+	require.Equal(t, expectedPDB.Name, pdbList.Items[0].Name)
+	require.Equal(t, expectedPDB.Namespace, pdbList.Items[0].Namespace)
+	require.Equal(t, expectedPDB.Spec, pdbList.Items[0].Spec)
+	require.Len(t, pdbList.Items[0].OwnerReferences, 1)
+	require.Equal(t, cluster.Name, pdbList.Items[0].OwnerReferences[0].Name)
+
 	// TestCase: Do not create storage PDB if total nodes with storage is less than 3
 	expectedNodeEnumerateResp.Nodes = []*osdapi.StorageNode{
 		{Pools: []*osdapi.StoragePool{{ID: 1}}, SchedulerNodeName: "node1"},
@@ -11243,6 +11785,13 @@ func TestPodDisruptionBudgetEnabled(t *testing.T) {
 	require.Equal(t, cluster.Name, storagePDB.Spec.Selector.MatchLabels[constants.LabelKeyClusterName])
 	require.Equal(t, constants.LabelValueTrue, storagePDB.Spec.Selector.MatchLabels[constants.LabelKeyStoragePod])
 
+	expectedPDB = testutil.GetExpectedPDB(t, "storagePodDisruptionBudget.yaml")
+	require.Equal(t, expectedPDB.Name, storagePDB.Name)
+	require.Equal(t, expectedPDB.Namespace, storagePDB.Namespace)
+	require.Equal(t, expectedPDB.Spec, storagePDB.Spec)
+	require.Len(t, storagePDB.OwnerReferences, 1)
+	require.Equal(t, cluster.Name, storagePDB.OwnerReferences[0].Name)
+
 	// TestCase: Update storage PDB if count of nodes with storage changes
 	expectedNodeEnumerateResp.Nodes = []*osdapi.StorageNode{
 		{Pools: []*osdapi.StoragePool{{ID: 1}}, SchedulerNodeName: "node1"},
@@ -11373,7 +11922,7 @@ func TestPodDisruptionBudgetWithMetroDR(t *testing.T) {
 	component.RegisterDisruptionBudgetComponent()
 
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 	err := driver.PreInstall(cluster)
 	require.NoError(t, err)
 
@@ -11433,7 +11982,7 @@ func TestPodDisruptionBudgetWithDifferentKvdbClusterSize(t *testing.T) {
 	component.RegisterDisruptionBudgetComponent()
 
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	// TestCase: Create KVDB PDB without any misc args
 	err := driver.PreInstall(cluster)
@@ -11569,7 +12118,7 @@ func TestPodDisruptionBudgetDuringInitialization(t *testing.T) {
 	component.RegisterDisruptionBudgetComponent()
 
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	// TestCase: Do not create PDBs if the cluster status is empty
 	err := driver.PreInstall(cluster)
@@ -11774,7 +12323,7 @@ func TestDisablePodDisruptionBudgets(t *testing.T) {
 	component.RegisterDisruptionBudgetComponent()
 
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	err := driver.PreInstall(cluster)
 	require.NoError(t, err)
@@ -11828,7 +12377,7 @@ func TestSCC(t *testing.T) {
 	}
 
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 	driver.SetDefaultsOnStorageCluster(cluster)
 
 	// Install with no SCC
@@ -11884,7 +12433,7 @@ func TestPodSecurityPoliciesEnabled(t *testing.T) {
 	}
 
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 	driver.SetDefaultsOnStorageCluster(cluster)
 
 	err := driver.PreInstall(cluster)
@@ -11995,7 +12544,7 @@ func TestRemovePodSecurityPolicies(t *testing.T) {
 	}
 
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 	driver.SetDefaultsOnStorageCluster(cluster)
 
 	// check that podsecuritpolicies have been created
@@ -12054,7 +12603,7 @@ func TestDisablePodSecurityPolicies(t *testing.T) {
 	}
 
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 	driver.SetDefaultsOnStorageCluster(cluster)
 
 	// check that podsecuritpolicies have been created
@@ -12084,12 +12633,45 @@ func TestDisablePodSecurityPolicies(t *testing.T) {
 	require.Empty(t, len(policies.Items))
 }
 
+func TestPodSecurityPoliciesNotSupported(t *testing.T) {
+	versionClient := fakek8sclient.NewSimpleClientset()
+	coreops.SetInstance(coreops.New(versionClient))
+	versionClient.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{
+		GitVersion: "v1.25.0",
+	}
+	reregisterComponents()
+	k8sClient := testutil.FakeK8sClient()
+
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+			Annotations: map[string]string{
+				pxutil.AnnotationPodSecurityPolicy: "true",
+			},
+		},
+	}
+
+	driver := portworx{}
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
+	driver.SetDefaultsOnStorageCluster(cluster)
+
+	// PodSecurityPolicies should not be created as they are removed in k8s 1.25
+	err := driver.PreInstall(cluster)
+	require.NoError(t, err)
+
+	policies := &policyv1beta1.PodSecurityPolicyList{}
+	err = testutil.List(k8sClient, policies)
+	require.NoError(t, err)
+	require.Empty(t, policies.Items)
+}
+
 func TestTelemetryEnableAndDisable(t *testing.T) {
 	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset()))
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -12273,7 +12855,7 @@ func TestMetricsCollectorIsDisabledForOldPxVersions(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -12332,7 +12914,7 @@ func TestTelemetryCCMProxy(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -12408,7 +12990,7 @@ func TestPortworxAPIServiceCustomLabels(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 	startPort := uint32(10001)
 
 	cluster := &corev1.StorageCluster{
@@ -12509,7 +13091,7 @@ func TestCSIAndPVCControllerDeploymentWithPodTopologySpreadConstraints(t *testin
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient(fakeNode)
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -12576,7 +13158,7 @@ func TestCSIAndPVCControllerDeploymentWithoutPodTopologySpreadConstraints(t *tes
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -12614,7 +13196,7 @@ func TestServiceCustomAnnotations(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 	startPort := uint32(10001)
 
 	cluster := &corev1.StorageCluster{
@@ -12788,7 +13370,7 @@ func TestServiceTypeAnnotation(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 	startPort := uint32(10001)
 
 	cluster := &corev1.StorageCluster{
@@ -13013,7 +13595,7 @@ func TestCSISnapController(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -13047,7 +13629,7 @@ func TestCSISnapController(t *testing.T) {
 	reregisterComponents()
 	k8sClient = testutil.FakeK8sClient()
 	driver = portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster = &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -13079,7 +13661,7 @@ func TestCSISnapController(t *testing.T) {
 	reregisterComponents()
 	k8sClient = testutil.FakeK8sClient()
 	driver = portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster = &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -13112,7 +13694,7 @@ func TestCSISnapController(t *testing.T) {
 	reregisterComponents()
 	k8sClient = testutil.FakeK8sClient()
 	driver = portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster = &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -13159,7 +13741,7 @@ func TestCSISnapController(t *testing.T) {
 	}
 	k8sClient = testutil.FakeK8sClient(snapshotControllerPod)
 	driver = portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster = &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -13203,7 +13785,7 @@ func TestCSISnapController(t *testing.T) {
 	}
 	k8sClient = testutil.FakeK8sClient(snapshotControllerPod)
 	driver = portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster = &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -13234,7 +13816,7 @@ func TestCSISnapController(t *testing.T) {
 	require.NoError(t, err)
 	reregisterComponents()
 	driver = portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	driver.SetDefaultsOnStorageCluster(cluster)
 	err = driver.PreInstall(cluster)
@@ -1075,7 +1075,7 @@ func TestPodSpecWithCloudStorageSpec(t *testing.T) {
 	zoneToInstancesMap := map[string]uint64{"a": 3, "b": 3, "c": 2}
 	driver := portworx{
 		k8sClient:          k8sClient,
-		recorder:           record.NewFakeRecorder(0),
+		recorder:           record.NewFakeRecorder(10),
 		zoneToInstancesMap: zoneToInstancesMap,
 		cloudProvider:      "mock",
 	}
@@ -1508,7 +1508,7 @@ func TestPodSpecWithCapacitySpecsAndDeviceSpecs(t *testing.T) {
 	zoneToInstancesMap := map[string]uint64{"a": 3, "b": 3, "c": 2}
 	driver := portworx{
 		k8sClient:          k8sClient,
-		recorder:           record.NewFakeRecorder(0),
+		recorder:           record.NewFakeRecorder(10),
 		zoneToInstancesMap: zoneToInstancesMap,
 		cloudProvider:      "mock",
 	}
@@ -3182,7 +3182,7 @@ func TestStorageNodeConfig(t *testing.T) {
 	zoneToInstancesMap := map[string]uint64{"a": 3, "b": 3, "c": 2}
 	driver := portworx{
 		k8sClient:          k8sClient,
-		recorder:           record.NewFakeRecorder(0),
+		recorder:           record.NewFakeRecorder(10),
 		zoneToInstancesMap: zoneToInstancesMap,
 		cloudProvider:      "mock",
 	}
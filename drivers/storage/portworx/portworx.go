@@ -87,8 +87,15 @@ func (p *portworx) Validate() error {
 }
 
 func (p *portworx) initializeComponents() {
+	dryRun := pxutil.IsComponentsDryRunEnabled()
 	for _, comp := range component.GetAll() {
-		comp.Initialize(p.k8sClient, *p.k8sVersion, p.scheme, p.recorder)
+		// Only components that opted in are reconciled in dry-run mode, the rest are unaffected
+		k8sClient := p.k8sClient
+		if dryRun && component.IsDryRunSupported(comp) {
+			logrus.Infof("Reconciling component %s in dry-run mode", comp.Name())
+			k8sClient = component.NewDryRunClient(k8sClient)
+		}
+		comp.Initialize(k8sClient, *p.k8sVersion, p.scheme, p.recorder)
 	}
 }
 
@@ -59,7 +59,7 @@ func TestInit(t *testing.T) {
 	driver := portworx{}
 	k8sClient := testutil.FakeK8sClient()
 	scheme := runtime.NewScheme()
-	recorder := record.NewFakeRecorder(0)
+	recorder := record.NewFakeRecorder(10)
 
 	// Nil k8s client
 	err := driver.Init(nil, scheme, recorder)
@@ -1931,7 +1931,7 @@ func TestValidationsForEssentials(t *testing.T) {
 	component.DeregisterAllComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 	cluster := &corev1.StorageCluster{}
 	os.Setenv(pxutil.EnvKeyPortworxEssentials, "True")
 
@@ -3721,7 +3721,7 @@ func TestUpdateClusterStatusWithoutSchedulerNodeName(t *testing.T) {
 	// Create driver object with the fake k8s client
 	driver := portworx{
 		k8sClient: k8sClient,
-		recorder:  record.NewFakeRecorder(0),
+		recorder:  record.NewFakeRecorder(10),
 	}
 
 	cluster := &corev1.StorageCluster{
@@ -4714,7 +4714,7 @@ func TestDeleteClusterWithoutDeleteStrategy(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 	startPort := uint32(10001)
 
 	pxutil.SpecsBaseDir = func() string {
@@ -4960,7 +4960,7 @@ func TestDeleteClusterShouldResetSDKConnection(t *testing.T) {
 
 	// Create driver object with the fake k8s client
 	driver := &portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -5000,7 +5000,7 @@ func TestDeleteClusterWithUninstallStrategy(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 	startPort := uint32(10001)
 
 	pxutil.SpecsBaseDir = func() string {
@@ -5253,7 +5253,7 @@ func TestDeleteClusterWithCustomRepoRegistry(t *testing.T) {
 	coreops.SetInstance(coreops.New(versionClient))
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 	customRepo := "test-registry:1111/test-repo"
 
 	cluster := &corev1.StorageCluster{
@@ -5301,7 +5301,7 @@ func TestDeleteClusterWithCustomRegistry(t *testing.T) {
 	coreops.SetInstance(coreops.New(versionClient))
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 	customRegistry := "test-registry:1111"
 
 	cluster := &corev1.StorageCluster{
@@ -5334,7 +5334,7 @@ func TestDeleteClusterWithImagePullPolicy(t *testing.T) {
 	coreops.SetInstance(coreops.New(versionClient))
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -5366,7 +5366,7 @@ func TestDeleteClusterWithImagePullSecret(t *testing.T) {
 	coreops.SetInstance(coreops.New(versionClient))
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 	imagePullSecret := "registry-secret"
 
 	cluster := &corev1.StorageCluster{
@@ -5399,7 +5399,7 @@ func TestDeleteClusterWithTolerations(t *testing.T) {
 	coreops.SetInstance(coreops.New(versionClient))
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 	tolerations := []v1.Toleration{
 		{
 			Key:      "must-exist",
@@ -5446,7 +5446,7 @@ func TestDeleteClusterWithNodeAffinity(t *testing.T) {
 	coreops.SetInstance(coreops.New(versionClient))
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -5501,7 +5501,7 @@ func TestDeleteClusterWithCustomNodeWiperImage(t *testing.T) {
 	coreops.SetInstance(coreops.New(versionClient))
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 	customRegistry := "test-registry:1111"
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -5541,7 +5541,7 @@ func TestDeleteClusterWithUninstallStrategyForPKS(t *testing.T) {
 	coreops.SetInstance(coreops.New(versionClient))
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	cluster := &corev1.StorageCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -5616,7 +5616,7 @@ func TestDeleteClusterWithUninstallAndWipeStrategy(t *testing.T) {
 	reregisterComponents()
 	k8sClient := testutil.FakeK8sClient()
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 	startPort := uint32(10001)
 
 	pxutil.SpecsBaseDir = func() string {
@@ -5892,7 +5892,7 @@ func TestDeleteClusterWithUninstallWhenNodeWiperCreated(t *testing.T) {
 	}
 	k8sClient := testutil.FakeK8sClient(wiperDS)
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	condition, err := driver.DeleteStorage(cluster)
 	require.NoError(t, err)
@@ -6020,7 +6020,7 @@ func TestDeleteClusterWithUninstallWipeStrategyWhenNodeWiperCreated(t *testing.T
 	}
 	k8sClient := testutil.FakeK8sClient(wiperDS)
 	driver := portworx{}
-	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+	driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 
 	condition, err := driver.DeleteStorage(cluster)
 	require.NoError(t, err)
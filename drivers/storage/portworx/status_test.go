@@ -116,7 +116,7 @@ func TestSetupContextWithToken(t *testing.T) {
 			coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset()))
 			reregisterComponents()
 			driver := portworx{}
-			driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(0))
+			driver.Init(k8sClient, runtime.NewScheme(), record.NewFakeRecorder(10))
 			setSecuritySpecDefaults(cluster)
 			cluster.Spec.Security.Auth.GuestAccess = guestAccessTypePtr(corev1.GuestRoleManaged)
 
@@ -41,6 +41,10 @@ const (
 	DefaultOpenshiftStartPort = 17001
 	// PortworxSpecsDir is the directory where all the Portworx specs are stored
 	PortworxSpecsDir = "/configs"
+	// OperatorCRDsDir is the directory where the operator CRD specs are stored
+	OperatorCRDsDir = "/crds"
+	// OperatorDeprecatedCRDsDir is the directory where the deprecated operator CRD specs are stored
+	OperatorDeprecatedCRDsDir = "/crds/deprecated"
 
 	// DefaultPortworxServiceAccountName default name of the Portworx service account
 	DefaultPortworxServiceAccountName = "portworx"
@@ -119,6 +123,9 @@ const (
 	// AnnotationTelemetryArcusLocation annotation indicates the location (internal/external) of Arcus
 	// that CCM should use
 	AnnotationTelemetryArcusLocation = pxAnnotationPrefix + "/arcus-location"
+	// AnnotationReconcileCoreCRDs annotation to make the operator ensure the StorageCluster
+	// and StorageNode CRDs are registered and established, along with the Portworx CRDs
+	AnnotationReconcileCoreCRDs = pxAnnotationPrefix + "/reconcile-core-crds"
 	// AnnotationHostPid configures hostPid flag for portworx pod.
 	AnnotationHostPid = pxAnnotationPrefix + "/host-pid"
 	// AnnotationDNSPolicy configures dns policy for portworx pod.
@@ -224,12 +231,23 @@ const (
 	// EnvKeyPortworxEnableTLS is a flag for enabling operator TLS with PX
 	EnvKeyPortworxEnableTLS  = "PX_ENABLE_TLS"
 	EnvKeyPortworxEnforceTLS = "PX_ENFORCE_TLS"
+	// EnvKeyComponentsDryRun env var to reconcile the Portworx components in dry-run
+	// mode, where the objects are validated by the api server but not persisted. Only
+	// components implementing component.DryRunComponent, currently portworxCRD, are
+	// affected. Other components and the storage pods are reconciled as usual.
+	EnvKeyComponentsDryRun = "PX_COMPONENTS_DRY_RUN"
 )
 
 var (
 	// SpecsBaseDir functions returns the base directory for specs. This is extracted as
 	// variable for testing. DO NOT change the value of the function unless for testing.
 	SpecsBaseDir = getSpecsBaseDir
+	// CRDBaseDir functions returns the base directory for operator CRD specs. This is extracted
+	// as variable for testing. DO NOT change the value of the function unless for testing.
+	CRDBaseDir = getCRDBaseDir
+	// DeprecatedCRDBaseDir functions returns the base directory for deprecated operator CRD specs.
+	// This is extracted as variable for testing. DO NOT change the value of the function unless for testing.
+	DeprecatedCRDBaseDir = getDeprecatedCRDBaseDir
 )
 
 // IsPortworxEnabled returns true if portworx is not explicitly disabled using the annotation
@@ -603,6 +621,14 @@ func getSpecsBaseDir() string {
 	return PortworxSpecsDir
 }
 
+func getCRDBaseDir() string {
+	return OperatorCRDsDir
+}
+
+func getDeprecatedCRDBaseDir() string {
+	return OperatorDeprecatedCRDsDir
+}
+
 // GetPortworxConn returns a new Portworx SDK client
 func GetPortworxConn(sdkConn *grpc.ClientConn, k8sClient client.Client, namespace string) (*grpc.ClientConn, error) {
 	if sdkConn != nil {
@@ -671,6 +697,13 @@ func IsTLSEnabled() bool {
 	return err == nil && enabled
 }
 
+// IsComponentsDryRunEnabled checks if the Portworx components that support it should
+// be reconciled in dry-run mode
+func IsComponentsDryRunEnabled() bool {
+	enabled, err := strconv.ParseBool(os.Getenv(EnvKeyComponentsDryRun))
+	return err == nil && enabled
+}
+
 // IsTLSEnabledOnCluster checks if TLS is enabled on the StorageCluster spec
 func IsTLSEnabledOnCluster(spec *corev1.StorageClusterSpec) bool {
 	// tls is disabled by default, so we don't break existing customers who
@@ -13,6 +13,7 @@ import (
 	"github.com/hashicorp/go-version"
 	storageapi "github.com/libopenstorage/openstorage/api"
 	"github.com/libopenstorage/operator/drivers/storage"
+	"github.com/libopenstorage/operator/drivers/storage/portworx/component"
 	pxutil "github.com/libopenstorage/operator/drivers/storage/portworx/util"
 	corev1 "github.com/libopenstorage/operator/pkg/apis/core/v1"
 	"github.com/libopenstorage/operator/pkg/client/clientset/versioned/fake"
@@ -1143,6 +1144,55 @@ func TestFailureDuringDriverPreInstall(t *testing.T) {
 	require.Contains(t, actualEvent, "preinstall error")
 }
 
+func TestCriticalComponentErrorDuringDriverPreInstall(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	driverName := "mock-driver"
+	cluster := createStorageCluster()
+	k8sNode := createK8sNode("k8s-node-1", 10)
+	k8sVersion, _ := version.NewVersion(minSupportedK8sVersion)
+	driver := testutil.MockDriver(mockCtrl)
+	k8sClient := testutil.FakeK8sClient(cluster, k8sNode)
+	podControl := &k8scontroller.FakePodControl{}
+	recorder := record.NewFakeRecorder(10)
+	controller := Controller{
+		client:            k8sClient,
+		Driver:            driver,
+		podControl:        podControl,
+		recorder:          recorder,
+		kubernetesVersion: k8sVersion,
+		nodeInfoMap:       make(map[string]*k8s.NodeInfo),
+	}
+
+	criticalErr := component.NewError(component.ErrCritical, fmt.Errorf("critical component error"))
+	driver.EXPECT().Validate().Return(nil).AnyTimes()
+	driver.EXPECT().SetDefaultsOnStorageCluster(gomock.Any())
+	driver.EXPECT().GetSelectorLabels().Return(nil).AnyTimes()
+	driver.EXPECT().String().Return(driverName).AnyTimes()
+	testutil.MockDriverPreInstallError(driver, criticalErr)
+	// No expectations are set on GetStoragePodSpec or UpdateStorageClusterStatus,
+	// so the test fails if reconciliation continues after the critical error
+
+	request := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      cluster.Name,
+			Namespace: cluster.Namespace,
+		},
+	}
+	result, err := controller.Reconcile(context.TODO(), request)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "critical component error")
+	require.Empty(t, result)
+	require.Empty(t, podControl.Templates)
+
+	require.Len(t, recorder.Events, 1)
+	actualEvent := <-recorder.Events
+	require.Contains(t, actualEvent,
+		fmt.Sprintf("%v %v", v1.EventTypeWarning, util.FailedSyncReason))
+	require.Contains(t, actualEvent, "critical component error")
+}
+
 func TestStoragePodsShouldNotBeScheduledIfDisabled(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
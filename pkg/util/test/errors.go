@@ -0,0 +1,65 @@
+package test
+
+import (
+	"fmt"
+
+	corev1 "github.com/libopenstorage/operator/pkg/apis/core/v1"
+)
+
+// ErrClusterNotInPhase error type for a StorageCluster that is not in any of the expected phases
+type ErrClusterNotInPhase struct {
+	// Namespace of the StorageCluster
+	Namespace string
+	// Name of the StorageCluster
+	Name string
+	// Phase is the current phase of the StorageCluster
+	Phase string
+	// ExpectedPhases are the phases the StorageCluster was expected to be in
+	ExpectedPhases []corev1.ClusterConditionStatus
+}
+
+func (e *ErrClusterNotInPhase) Error() string {
+	if e.Phase == "" {
+		return "failed to get cluster status"
+	}
+	return fmt.Sprintf("cluster state: %s", e.Phase)
+}
+
+// ErrPodsNotReady error type for pods that are not ready
+type ErrPodsNotReady struct {
+	// Namespace of the pods
+	Namespace string
+	// NotReady are the names of the pods that are not ready
+	NotReady []string
+	// Ready is the number of ready pods
+	Ready int
+	// Expected is the number of Portworx pods expected in the cluster, one per expected
+	// Portworx node. It is not lowered when only a percentage of the pods must be ready.
+	Expected int
+}
+
+func (e *ErrPodsNotReady) Error() string {
+	return fmt.Sprintf("waiting for Portworx pods to be ready: %s", e.NotReady)
+}
+
+// ErrImageMismatch error type for a pod that does not run the expected image
+type ErrImageMismatch struct {
+	// Pod is the name of the pod
+	Pod string
+	// Container is the name of the container expected to run the image, empty if
+	// any of the containers of the pod was expected to run it
+	Container string
+	// Expected is the expected image
+	Expected string
+	// Actual is the image of the container, empty if no container was expected
+	Actual string
+}
+
+func (e *ErrImageMismatch) Error() string {
+	if e.Container == "" {
+		return fmt.Sprintf("failed to validate image %s on pod %s, none of the containers or init containers has the image",
+			e.Expected, e.Pod)
+	}
+	return fmt.Sprintf("failed to validate image on pod %s, expected container %s to have image %s, actual image: %s",
+		e.Pod, e.Container, e.Expected, e.Actual)
+}
@@ -0,0 +1,154 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	coreops "github.com/portworx/sched-ops/k8s/core"
+	operatorops "github.com/portworx/sched-ops/k8s/operator"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakek8sclient "k8s.io/client-go/kubernetes/fake"
+
+	corev1 "github.com/libopenstorage/operator/pkg/apis/core/v1"
+	fakeoperatorclient "github.com/libopenstorage/operator/pkg/client/clientset/versioned/fake"
+)
+
+func TestErrClusterNotInPhase(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+		Status: corev1.StorageClusterStatus{
+			Phase: string(corev1.ClusterInit),
+		},
+	}
+	operatorops.SetInstance(operatorops.New(fakeoperatorclient.NewSimpleClientset(cluster)))
+
+	// TestCase: Cluster that does not come online
+	_, err := ValidateStorageClusterIsOnline(cluster, time.Second, 100*time.Millisecond)
+	require.Error(t, err)
+	require.Equal(t, "failed to wait for StorageCluster to be ready, Err: cluster state: Initializing", err.Error())
+	phaseErr := &ErrClusterNotInPhase{}
+	require.True(t, errors.As(err, &phaseErr))
+	require.Equal(t, "kube-test", phaseErr.Namespace)
+	require.Equal(t, "px-cluster", phaseErr.Name)
+	require.Equal(t, string(corev1.ClusterInit), phaseErr.Phase)
+	require.Equal(t, []corev1.ClusterConditionStatus{corev1.ClusterOnline}, phaseErr.ExpectedPhases)
+
+	// TestCase: Cluster that does not reach any of the given phases
+	_, err = WaitForStorageClusterPhase(cluster, time.Second, 100*time.Millisecond, corev1.ClusterOnline, corev1.ClusterOffline)
+	require.Error(t, err)
+	phaseErr = &ErrClusterNotInPhase{}
+	require.True(t, errors.As(err, &phaseErr))
+	require.Equal(t, []corev1.ClusterConditionStatus{corev1.ClusterOnline, corev1.ClusterOffline}, phaseErr.ExpectedPhases)
+
+	// TestCase: Cluster without a phase
+	require.Equal(t, "failed to get cluster status", (&ErrClusterNotInPhase{}).Error())
+}
+
+func TestErrPodsNotReady(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+			UID:       "px-cluster-uid",
+		},
+	}
+	newPod := func(nodeName string, ready bool) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "px-pod-" + nodeName,
+				Namespace:       cluster.Namespace,
+				OwnerReferences: []metav1.OwnerReference{{UID: cluster.UID}},
+			},
+			Spec: v1.PodSpec{NodeName: nodeName},
+			Status: v1.PodStatus{
+				Phase: v1.PodRunning,
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name:  "portworx",
+						Ready: ready,
+						State: v1.ContainerState{Running: &v1.ContainerStateRunning{}},
+					},
+				},
+			},
+		}
+	}
+	operatorops.SetInstance(operatorops.New(fakeoperatorclient.NewSimpleClientset(cluster)))
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(
+		newPod("node1", true),
+		newPod("node2", false),
+		newPod("node3", false),
+	)))
+	podTestFn := func(pod v1.Pod) bool {
+		return coreops.Instance().IsPodReady(pod)
+	}
+	expectedNodes := []string{"node1", "node2", "node3"}
+
+	// TestCase: All pods are expected to be ready
+	err := validateStorageClusterPods(cluster, expectedNodes, time.Second, 100*time.Millisecond, podTestFn)
+	require.Error(t, err)
+	require.Equal(t, "failed to validate Portworx pods, Err: waiting for Portworx pods to be ready: [px-pod-node2 px-pod-node3]", err.Error())
+	podsErr := &ErrPodsNotReady{}
+	require.True(t, errors.As(err, &podsErr))
+	require.Equal(t, "kube-test", podsErr.Namespace)
+	require.ElementsMatch(t, []string{"px-pod-node2", "px-pod-node3"}, podsErr.NotReady)
+	require.Equal(t, 1, podsErr.Ready)
+	require.Equal(t, 3, podsErr.Expected)
+
+	// TestCase: Threshold of ready pods is not met
+	err = validateStorageClusterPodsWithThreshold(context.TODO(), cluster, expectedNodes, time.Second, 100*time.Millisecond, podTestFn, 50)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "waiting for at least 2/3 (50%) Portworx pods to be ready, ready: 1")
+	podsErr = &ErrPodsNotReady{}
+	require.True(t, errors.As(err, &podsErr))
+	require.Equal(t, 1, podsErr.Ready)
+	require.Equal(t, 3, podsErr.Expected)
+}
+
+func TestErrImageMismatch(t *testing.T) {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "stork-123-abc",
+			Namespace: "kube-test",
+			Labels:    map[string]string{"name": "stork"},
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{Name: "stork", Image: "openstorage/stork:2.9.0"},
+			},
+		},
+	}
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(pod)))
+
+	// TestCase: Container runs a different image
+	err := validateImageOnPods("openstorage/stork:2.10.0", "kube-test", map[string]string{"name": "stork"}, "stork")
+	require.Error(t, err)
+	imageErr := &ErrImageMismatch{}
+	require.True(t, errors.As(err, &imageErr))
+	require.Equal(t, &ErrImageMismatch{
+		Pod:       "stork-123-abc",
+		Container: "stork",
+		Expected:  "openstorage/stork:2.10.0",
+		Actual:    "openstorage/stork:2.9.0",
+	}, imageErr)
+
+	// TestCase: None of the containers runs the image
+	err = validateImageOnPods("openstorage/stork:2.10.0", "kube-test", map[string]string{"name": "stork"})
+	require.Error(t, err)
+	imageErr = &ErrImageMismatch{}
+	require.True(t, errors.As(err, &imageErr))
+	require.Empty(t, imageErr.Container)
+	require.Empty(t, imageErr.Actual)
+	require.Equal(t, "openstorage/stork:2.10.0", imageErr.Expected)
+
+	// TestCase: Expected container is missing, which is not an image mismatch
+	err = validateImageOnPods("openstorage/stork:2.10.0", "kube-test", map[string]string{"name": "stork"}, "other")
+	require.Error(t, err)
+	require.False(t, errors.As(err, &imageErr))
+}
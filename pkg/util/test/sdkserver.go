@@ -0,0 +1,152 @@
+package test
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"github.com/libopenstorage/openstorage/api"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MockSDKServer is a gRPC server that serves the OpenStorage SDK node and identity
+// services from an in-memory list of nodes. It can be used to exercise helpers that
+// talk to the SDK without a Portworx cluster.
+type MockSDKServer struct {
+	// OpenStorageNodeServer is embedded so the server satisfies the interface. Only
+	// Inspect, InspectCurrent, Enumerate and EnumerateWithFilters are implemented.
+	api.OpenStorageNodeServer
+
+	server   *grpc.Server
+	listener net.Listener
+	version  string
+	lock     sync.Mutex
+	nodes    []*api.StorageNode
+}
+
+// NewMockSDKServer starts a mock SDK server on a local port serving the given nodes.
+// The server should be stopped with Stop once it is no longer needed.
+func NewMockSDKServer(nodes ...*api.StorageNode) (*MockSDKServer, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	m := &MockSDKServer{
+		server:   grpc.NewServer(),
+		listener: listener,
+		nodes:    nodes,
+	}
+	api.RegisterOpenStorageNodeServer(m.server, m)
+	api.RegisterOpenStorageIdentityServer(m.server, &mockSDKIdentityServer{mock: m})
+	go m.server.Serve(listener)
+	return m, nil
+}
+
+// Target returns the address the mock SDK server can be dialed on
+func (m *MockSDKServer) Target() string {
+	return m.listener.Addr().String()
+}
+
+// Port returns the port the mock SDK server is listening on
+func (m *MockSDKServer) Port() int {
+	return m.listener.Addr().(*net.TCPAddr).Port
+}
+
+// SetNodes replaces the nodes served by the mock SDK server
+func (m *MockSDKServer) SetNodes(nodes ...*api.StorageNode) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.nodes = nodes
+}
+
+// SetVersion sets the version returned by the identity service
+func (m *MockSDKServer) SetVersion(version string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.version = version
+}
+
+// Stop stops the mock SDK server
+func (m *MockSDKServer) Stop() {
+	m.server.Stop()
+}
+
+// Inspect returns the node with the given id
+func (m *MockSDKServer) Inspect(
+	ctx context.Context,
+	req *api.SdkNodeInspectRequest,
+) (*api.SdkNodeInspectResponse, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for _, node := range m.nodes {
+		if node.Id == req.GetNodeId() {
+			return &api.SdkNodeInspectResponse{Node: node}, nil
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "node %s not found", req.GetNodeId())
+}
+
+// InspectCurrent returns the first node
+func (m *MockSDKServer) InspectCurrent(
+	ctx context.Context,
+	req *api.SdkNodeInspectCurrentRequest,
+) (*api.SdkNodeInspectCurrentResponse, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if len(m.nodes) == 0 {
+		return nil, status.Error(codes.NotFound, "no nodes found")
+	}
+	return &api.SdkNodeInspectCurrentResponse{Node: m.nodes[0]}, nil
+}
+
+// Enumerate returns the ids of all the nodes
+func (m *MockSDKServer) Enumerate(
+	ctx context.Context,
+	req *api.SdkNodeEnumerateRequest,
+) (*api.SdkNodeEnumerateResponse, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	var nodeIDs []string
+	for _, node := range m.nodes {
+		nodeIDs = append(nodeIDs, node.Id)
+	}
+	return &api.SdkNodeEnumerateResponse{NodeIds: nodeIDs}, nil
+}
+
+// EnumerateWithFilters returns all the nodes, filters are ignored
+func (m *MockSDKServer) EnumerateWithFilters(
+	ctx context.Context,
+	req *api.SdkNodeEnumerateWithFiltersRequest,
+) (*api.SdkNodeEnumerateWithFiltersResponse, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return &api.SdkNodeEnumerateWithFiltersResponse{
+		Nodes: append([]*api.StorageNode{}, m.nodes...),
+	}, nil
+}
+
+// mockSDKIdentityServer serves the identity service of the mock SDK server
+type mockSDKIdentityServer struct {
+	mock *MockSDKServer
+}
+
+func (s *mockSDKIdentityServer) Capabilities(
+	ctx context.Context,
+	req *api.SdkIdentityCapabilitiesRequest,
+) (*api.SdkIdentityCapabilitiesResponse, error) {
+	return &api.SdkIdentityCapabilitiesResponse{}, nil
+}
+
+func (s *mockSDKIdentityServer) Version(
+	ctx context.Context,
+	req *api.SdkIdentityVersionRequest,
+) (*api.SdkIdentityVersionResponse, error) {
+	s.mock.lock.Lock()
+	defer s.mock.lock.Unlock()
+	return &api.SdkIdentityVersionResponse{
+		Version: &api.StorageVersion{Version: s.mock.version},
+	}, nil
+}
@@ -0,0 +1,79 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/libopenstorage/openstorage/api"
+	coreops "github.com/portworx/sched-ops/k8s/core"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	fakek8sclient "k8s.io/client-go/kubernetes/fake"
+
+	corev1 "github.com/libopenstorage/operator/pkg/apis/core/v1"
+)
+
+func TestMockSDKServer(t *testing.T) {
+	mockSDK, err := NewMockSDKServer(
+		&api.StorageNode{Id: "node1-id", SchedulerNodeName: "node1", Status: api.Status_STATUS_OK},
+		&api.StorageNode{Id: "node2-id", SchedulerNodeName: "node2", Status: api.Status_STATUS_OK},
+	)
+	require.NoError(t, err)
+	defer mockSDK.Stop()
+	mockSDK.SetVersion("2.11.0")
+
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+	}
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(&v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "portworx-service",
+			Namespace: cluster.Namespace,
+		},
+		Spec: v1.ServiceSpec{
+			ClusterIP: "127.0.0.1",
+			Ports: []v1.ServicePort{
+				{
+					Name:       "px-sdk",
+					Port:       int32(mockSDK.Port()),
+					TargetPort: intstr.FromInt(mockSDK.Port()),
+				},
+			},
+		},
+	})))
+
+	// TestCase: Identity service returns the version
+	conn, err := dialSdkServer(mockSDK.Target())
+	require.NoError(t, err)
+	versionResp, err := api.NewOpenStorageIdentityClient(conn).Version(context.Background(), &api.SdkIdentityVersionRequest{})
+	require.NoError(t, err)
+	require.Equal(t, "2.11.0", versionResp.GetVersion().GetVersion())
+
+	// TestCase: Unknown node is not found
+	_, err = api.NewOpenStorageNodeClient(conn).Inspect(context.Background(), &api.SdkNodeInspectRequest{NodeId: "other"})
+	require.Error(t, err)
+	conn.Close()
+
+	// TestCase: Node count matches
+	err = validatePortworxNodes(cluster, 2)
+	require.NoError(t, err)
+
+	// TestCase: Node count does not match
+	err = validatePortworxNodes(cluster, 3)
+	require.Error(t, err)
+	require.Equal(t, "expected nodes: 3. actual nodes: 2", err.Error())
+
+	// TestCase: Node is not online
+	mockSDK.SetNodes(
+		&api.StorageNode{Id: "node1-id", SchedulerNodeName: "node1", Status: api.Status_STATUS_OK},
+		&api.StorageNode{Id: "node2-id", SchedulerNodeName: "node2", Status: api.Status_STATUS_OFFLINE},
+	)
+	err = validatePortworxNodes(cluster, 2)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "node node2 is not online")
+}
@@ -1,33 +1,45 @@
 package test
 
 import (
+	"bufio"
+	"bytes"
 	"context"
-	"crypto/rand"
-	"encoding/base64"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"math"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"path"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/hashicorp/go-version"
 	"github.com/libopenstorage/openstorage/api"
+	"github.com/libopenstorage/openstorage/pkg/auth"
 
 	ocp_configv1 "github.com/openshift/api/config/v1"
+	apiextensionsops "github.com/portworx/sched-ops/k8s/apiextensions"
 	appops "github.com/portworx/sched-ops/k8s/apps"
 	coreops "github.com/portworx/sched-ops/k8s/core"
 	k8serrors "github.com/portworx/sched-ops/k8s/errors"
 	operatorops "github.com/portworx/sched-ops/k8s/operator"
 	prometheusops "github.com/portworx/sched-ops/k8s/prometheus"
 	rbacops "github.com/portworx/sched-ops/k8s/rbac"
+	storageops "github.com/portworx/sched-ops/k8s/storage"
 	"github.com/portworx/sched-ops/task"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	"github.com/sirupsen/logrus"
@@ -42,18 +54,27 @@ import (
 	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	fakeextclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/client-go/kubernetes/scheme"
+	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
+	v1helper "k8s.io/kubernetes/pkg/apis/core/v1/helper"
 	pluginhelper "k8s.io/kubernetes/pkg/scheduler/framework/plugins/helper"
 	cluster_v1alpha1 "sigs.k8s.io/cluster-api/pkg/apis/deprecated/v1alpha1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	corev1 "github.com/libopenstorage/operator/pkg/apis/core/v1"
+	"github.com/libopenstorage/operator/pkg/constants"
 	"github.com/libopenstorage/operator/pkg/mock"
 	"github.com/libopenstorage/operator/pkg/util"
 	ocp_secv1 "github.com/openshift/api/security/v1"
@@ -89,26 +110,131 @@ const (
 
 	// PxMasterVersion is a tag for Portworx master version
 	PxMasterVersion = "3.0.0.0"
+
+	// PrometheusScrapeAnnotation is the pod annotation that enables Prometheus scraping
+	PrometheusScrapeAnnotation = "prometheus.io/scrape"
+	// PrometheusPortAnnotation is the pod annotation that sets the Prometheus scrape port
+	PrometheusPortAnnotation = "prometheus.io/port"
+
+	// ComponentPVCController is the PVC controller component, disabled by the pvc-controller annotation
+	ComponentPVCController = "pvc-controller"
+	// ComponentPortworxProxy is the Portworx proxy component, disabled by the portworx-proxy annotation
+	ComponentPortworxProxy = "portworx-proxy"
+	// ComponentStorageClass is the default Portworx StorageClasses, disabled by the disable-storage-class annotation
+	ComponentStorageClass = "storage-class"
+
+	// TelemetryCertSecretName is the secret with the telemetry certificate, created by the CCM container
+	TelemetryCertSecretName = "pure-telemetry-certs"
+	// TelemetryCertMountPath is the path where the telemetry certificate is mounted in the collector proxy
+	TelemetryCertMountPath = "/appliance-cert"
+	// TelemetryExternalArcusEndpoint is the cloud endpoint telemetry is uploaded to
+	TelemetryExternalArcusEndpoint = "rest.cloud-support.purestorage.com"
+	// TelemetryInternalArcusEndpoint is the staging endpoint telemetry is uploaded to
+	TelemetryInternalArcusEndpoint = "rest.staging-cloud-support.purestorage.com"
+	// TelemetryCollectorRunAsUser is the non-root user the telemetry collector containers run as
+	TelemetryCollectorRunAsUser = 1111
+
+	// updateConflictRetries is the number of times UpdateWithRetry retries an update after a conflict
+	updateConflictRetries = 5
+	// portworxSCCName is the name of the SecurityContextConstraints the operator creates on OpenShift
+	portworxSCCName = "portworx"
+	// portworxClusterRoleName is the name of the ClusterRole and ClusterRoleBinding of Portworx
+	portworxClusterRoleName = "portworx"
+	// podSecurityEnforceLabel is the namespace label that sets the enforced PodSecurity admission level
+	podSecurityEnforceLabel = "pod-security.kubernetes.io/enforce"
+	// podSecurityLevelPrivileged is the PodSecurity admission level required by Portworx pods
+	podSecurityLevelPrivileged = "privileged"
+	// internalKvdbClusterSize is the number of members of the internal kvdb cluster
+	internalKvdbClusterSize = 3
+	// pxEnabledLabel is the node label used to disable Portworx on a node when set to false
+	pxEnabledLabel = "px/enabled"
 )
 
 // TestSpecPath is the path for all test specs. Due to currently functional test and
 // unit test use different path, this needs to be set accordingly.
 var TestSpecPath = "testspec"
 
+var (
+	// testSchemeOnce guards registering types to the shared scheme used by FakeK8sClient
+	testSchemeOnce sync.Once
+	// decodeCodecsOnce guards building the private scheme used to decode the test spec files
+	decodeCodecsOnce sync.Once
+	decodeCodecs     serializer.CodecFactory
+)
+
 // MockDriver creates a mock storage driver
 func MockDriver(mockCtrl *gomock.Controller) *mock.MockDriver {
 	return mock.NewMockDriver(mockCtrl)
 }
 
+// MockDriverPreInstallError sets the mock driver to fail PreInstall with the given error,
+// such as a critical component error, to test how the error is surfaced
+func MockDriverPreInstallError(driver *mock.MockDriver, err error) *gomock.Call {
+	return driver.EXPECT().PreInstall(gomock.Any()).Return(err)
+}
+
+// MockDriverUpdateStatusError sets the mock driver to fail UpdateStorageClusterStatus with the given error
+func MockDriverUpdateStatusError(driver *mock.MockDriver, err error) *gomock.Call {
+	return driver.EXPECT().UpdateStorageClusterStatus(gomock.Any()).Return(err)
+}
+
+// MockDriverGetStoragePodSpecError sets the mock driver to fail GetStoragePodSpec with the given error
+func MockDriverGetStoragePodSpecError(driver *mock.MockDriver, err error) *gomock.Call {
+	return driver.EXPECT().GetStoragePodSpec(gomock.Any(), gomock.Any()).Return(v1.PodSpec{}, err)
+}
+
+// MockDriverDeleteStorageError sets the mock driver to fail DeleteStorage with the given error
+func MockDriverDeleteStorageError(driver *mock.MockDriver, err error) *gomock.Call {
+	return driver.EXPECT().DeleteStorage(gomock.Any()).Return(nil, err)
+}
+
 // FakeK8sClient creates a fake controller-runtime Kubernetes client. Also
-// adds the CRDs defined in this repository to the scheme
+// adds the CRDs defined in this repository to the scheme. Status updates done
+// through the client's status writer are persisted along with the object, as the
+// fake client in the vendored controller-runtime has no separate status subresource.
 func FakeK8sClient(initObjects ...runtime.Object) client.Client {
-	s := scheme.Scheme
+	registerTestSchemes()
+	return fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(initObjects...).Build()
+}
+
+// registerTestSchemes adds the types used by FakeK8sClient to the shared client-go scheme.
+// The scheme is shared, so the types are registered only once.
+func registerTestSchemes() {
+	testSchemeOnce.Do(func() {
+		addTestTypesToScheme(scheme.Scheme)
+		// Only the SCC types are added here. ocp_secv1.Install also re-registers the core types,
+		// which changes how the fake client stores core objects like ConfigMaps.
+		scheme.Scheme.AddKnownTypes(ocp_secv1.GroupVersion,
+			&ocp_secv1.SecurityContextConstraints{},
+			&ocp_secv1.SecurityContextConstraintsList{},
+		)
+		metav1.AddToGroupVersion(scheme.Scheme, ocp_secv1.GroupVersion)
+	})
+}
+
+// addTestTypesToScheme adds the types defined outside of client-go that the test helpers use
+func addTestTypesToScheme(s *runtime.Scheme) {
 	corev1.AddToScheme(s)
 	monitoringv1.AddToScheme(s)
 	cluster_v1alpha1.AddToScheme(s)
 	ocp_configv1.AddToScheme(s)
-	return fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(initObjects...).Build()
+	apiextensionsv1.AddToScheme(s)
+	apiextensionsv1beta1.AddToScheme(s)
+	policyv1beta1.AddToScheme(s)
+	storagev1.AddToScheme(s)
+}
+
+// getDecodeCodecs returns the codecs used to decode the test spec files. They are built once
+// from a private scheme, so decoding does not change the shared scheme used by FakeK8sClient.
+func getDecodeCodecs() serializer.CodecFactory {
+	decodeCodecsOnce.Do(func() {
+		s := runtime.NewScheme()
+		scheme.AddToScheme(s)
+		addTestTypesToScheme(s)
+		ocp_secv1.Install(s)
+		decodeCodecs = serializer.NewCodecFactory(s)
+	})
+	return decodeCodecs
 }
 
 // List returns a list of objects using the given Kubernetes client
@@ -116,6 +242,16 @@ func List(k8sClient client.Client, obj client.ObjectList) error {
 	return k8sClient.List(context.TODO(), obj, &client.ListOptions{})
 }
 
+// ListWithOptions returns a list of objects using the given Kubernetes client and list options
+func ListWithOptions(k8sClient client.Client, obj client.ObjectList, opts ...client.ListOption) error {
+	return k8sClient.List(context.TODO(), obj, opts...)
+}
+
+// ListByLabels returns a list of objects matching the given labels using the given Kubernetes client
+func ListByLabels(k8sClient client.Client, obj client.ObjectList, labels map[string]string) error {
+	return ListWithOptions(k8sClient, obj, client.MatchingLabels(labels))
+}
+
 // Get returns an object using the given Kubernetes client
 func Get(k8sClient client.Client, obj client.Object, name, namespace string) error {
 	return k8sClient.Get(
@@ -128,11 +264,66 @@ func Get(k8sClient client.Client, obj client.Object, name, namespace string) err
 	)
 }
 
+// GetEventually returns an object using the given Kubernetes client, retrying until the
+// object exists or the timeout elapses. On timeout the last NotFound error is returned.
+func GetEventually(
+	k8sClient client.Client,
+	obj client.Object,
+	name, namespace string,
+	timeout, interval time.Duration,
+) error {
+	var lastErr error
+	t := func() (interface{}, bool, error) {
+		lastErr = Get(k8sClient, obj, name, namespace)
+		if errors.IsNotFound(lastErr) {
+			return nil, true, lastErr
+		}
+		return nil, false, lastErr
+	}
+	if _, err := task.DoRetryWithTimeout(t, timeout, interval); err != nil {
+		if errors.IsNotFound(lastErr) {
+			return lastErr
+		}
+		return err
+	}
+	return nil
+}
+
 // Delete deletes an object using the given Kubernetes client
 func Delete(k8sClient client.Client, obj client.Object) error {
 	return k8sClient.Delete(context.TODO(), obj)
 }
 
+// ValidateSelfHeal deletes the given object and waits until the controller recreates it.
+// An object with the same UID as the deleted one is considered still terminating, so the
+// wait continues until a new incarnation shows up or the timeout elapses.
+func ValidateSelfHeal(
+	k8sClient client.Client,
+	obj client.Object,
+	recreateTimeout, interval time.Duration,
+) error {
+	name, namespace, oldUID := obj.GetName(), obj.GetNamespace(), obj.GetUID()
+	if err := Delete(k8sClient, obj); err != nil {
+		return fmt.Errorf("failed to delete %s/%s, Err: %v", namespace, name, err)
+	}
+
+	deadline := time.Now().Add(recreateTimeout)
+	for {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return fmt.Errorf("timed out waiting for %s/%s to be recreated after %v", namespace, name, recreateTimeout)
+		}
+		if err := GetEventually(k8sClient, obj, name, namespace, remaining, interval); err != nil {
+			return fmt.Errorf("failed to wait for %s/%s to be recreated, Err: %v", namespace, name, err)
+		}
+		if oldUID == "" || obj.GetUID() != oldUID {
+			logrus.Debugf("%s/%s was recreated", namespace, name)
+			return nil
+		}
+		time.Sleep(interval)
+	}
+}
+
 // Update changes an object using the given Kubernetes client and updates the resource version
 func Update(k8sClient client.Client, obj client.Object) error {
 	return k8sClient.Update(
@@ -141,170 +332,336 @@ func Update(k8sClient client.Client, obj client.Object) error {
 	)
 }
 
+// UpdateWithRetry applies the given mutation to the object and updates it using the given
+// Kubernetes client. If the update fails with a conflict, the latest object is fetched, the
+// mutation is applied again and the update retried, up to updateConflictRetries times.
+func UpdateWithRetry(k8sClient client.Client, obj client.Object, mutate func(client.Object)) error {
+	var err error
+	for i := 0; i <= updateConflictRetries; i++ {
+		if i > 0 {
+			if err = Get(k8sClient, obj, obj.GetName(), obj.GetNamespace()); err != nil {
+				return err
+			}
+		}
+		mutate(obj)
+		if err = Update(k8sClient, obj); !errors.IsConflict(err) {
+			return err
+		}
+		logrus.Debugf("Conflict updating %s/%s, retrying: %v", obj.GetNamespace(), obj.GetName(), err)
+	}
+	return err
+}
+
+// ValidateReconcilePaused pauses the components of the given cluster using the pause annotation,
+// applies the mutation to the given child object and validates that the operator does not revert
+// it for the whole window. The operator only honors the pause annotation while migrating, so the
+// cluster must already have the migration approval annotation. The cluster is left paused.
+func ValidateReconcilePaused(
+	k8sClient client.Client,
+	cluster *corev1.StorageCluster,
+	obj client.Object,
+	mutate func(client.Object),
+	window, interval time.Duration,
+) error {
+	if _, migrating := cluster.Annotations[constants.AnnotationMigrationApproved]; !migrating {
+		return fmt.Errorf("StorageCluster %s/%s is not migrating, components can only be paused with annotation %s",
+			cluster.Namespace, cluster.Name, constants.AnnotationMigrationApproved)
+	}
+	if err := UpdateWithRetry(k8sClient, cluster, func(o client.Object) {
+		annotations := o.GetAnnotations()
+		annotations[constants.AnnotationPauseComponentMigration] = "true"
+		o.SetAnnotations(annotations)
+	}); err != nil {
+		return fmt.Errorf("failed to pause StorageCluster %s/%s, Err: %v", cluster.Namespace, cluster.Name, err)
+	}
+
+	if err := UpdateWithRetry(k8sClient, obj, mutate); err != nil {
+		return fmt.Errorf("failed to update %s/%s, Err: %v", obj.GetNamespace(), obj.GetName(), err)
+	}
+
+	// The mutation is idempotent on an object that still has the change applied
+	deadline := time.Now().Add(window)
+	for time.Now().Before(deadline) {
+		time.Sleep(interval)
+		if err := Get(k8sClient, obj, obj.GetName(), obj.GetNamespace()); err != nil {
+			return fmt.Errorf("failed to get %s/%s, Err: %v", obj.GetNamespace(), obj.GetName(), err)
+		}
+		mutated := obj.DeepCopyObject().(client.Object)
+		mutate(mutated)
+		if !reflect.DeepEqual(obj, mutated) {
+			return fmt.Errorf("%s/%s was reverted while StorageCluster %s/%s is paused",
+				obj.GetNamespace(), obj.GetName(), cluster.Namespace, cluster.Name)
+		}
+	}
+	logrus.Debugf("%s/%s was not reverted for %v while reconciliation is paused", obj.GetNamespace(), obj.GetName(), window)
+	return nil
+}
+
+// ValidateSingleClusterPerNamespace creates a copy of the StorageCluster in the given namespace
+// and validates that it gets rejected, either by admission control on create or by the operator
+// marking it as failed. Any other error on create is returned. The duplicate StorageCluster is
+// deleted before returning.
+func ValidateSingleClusterPerNamespace(k8sClient client.Client, namespace string, timeout, interval time.Duration) error {
+	clusterList := &corev1.StorageClusterList{}
+	if err := ListWithOptions(k8sClient, clusterList, client.InNamespace(namespace)); err != nil {
+		return fmt.Errorf("failed to list StorageClusters in %s, Err: %v", namespace, err)
+	}
+	if len(clusterList.Items) == 0 {
+		return fmt.Errorf("failed to find a StorageCluster in %s", namespace)
+	}
+
+	existing := clusterList.Items[0]
+	duplicate := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      existing.Name + "-duplicate",
+			Namespace: namespace,
+		},
+		Spec: *existing.Spec.DeepCopy(),
+	}
+	if err := k8sClient.Create(context.TODO(), duplicate); err != nil {
+		if !isAdmissionDenial(err) {
+			return fmt.Errorf("failed to create duplicate StorageCluster %s/%s, Err: %v", namespace, duplicate.Name, err)
+		}
+		logrus.Debugf("Duplicate StorageCluster %s/%s was rejected on create: %v", namespace, duplicate.Name, err)
+		return nil
+	}
+	defer func() {
+		if err := Delete(k8sClient, duplicate); err != nil && !errors.IsNotFound(err) {
+			logrus.Warnf("Failed to delete duplicate StorageCluster %s/%s, Err: %v", namespace, duplicate.Name, err)
+		}
+	}()
+
+	t := func() (interface{}, bool, error) {
+		cluster := &corev1.StorageCluster{}
+		if err := Get(k8sClient, cluster, duplicate.Name, namespace); err != nil {
+			return nil, true, err
+		}
+		if cluster.Status.Phase != string(corev1.ClusterOperationFailed) {
+			return nil, true, fmt.Errorf("waiting for duplicate StorageCluster %s/%s to be rejected, phase: %q",
+				namespace, duplicate.Name, cluster.Status.Phase)
+		}
+		return nil, false, nil
+	}
+	if _, err := doRetryWithTimeout(t, timeout, interval); err != nil {
+		return fmt.Errorf("duplicate StorageCluster %s/%s was not rejected, Err: %v", namespace, duplicate.Name, err)
+	}
+	logrus.Debugf("Duplicate StorageCluster %s/%s was rejected by the operator", namespace, duplicate.Name)
+	return nil
+}
+
+// isAdmissionDenial returns true if the error is an API server rejection by admission control.
+// RBAC failures are also Forbidden errors, so those are only accepted when the status message
+// reports that an admission webhook or policy denied the request.
+func isAdmissionDenial(err error) bool {
+	if errors.IsInvalid(err) {
+		return true
+	}
+	status, ok := err.(errors.APIStatus)
+	if !ok {
+		return false
+	}
+	message := status.Status().Message
+	return strings.Contains(message, "denied the request") || strings.Contains(message, "denied request")
+}
+
 // GetExpectedClusterRole returns the ClusterRole object from given yaml spec file
 func GetExpectedClusterRole(t *testing.T, fileName string) *rbacv1.ClusterRole {
-	obj := getKubernetesObject(t, fileName)
-	clusterRole, ok := obj.(*rbacv1.ClusterRole)
-	assert.True(t, ok, "Expected ClusterRole object")
+	clusterRole := &rbacv1.ClusterRole{}
+	GetExpectedObject(t, fileName, clusterRole)
 	return clusterRole
 }
 
 // GetExpectedClusterRoleBinding returns the ClusterRoleBinding object from given
 // yaml spec file
 func GetExpectedClusterRoleBinding(t *testing.T, fileName string) *rbacv1.ClusterRoleBinding {
-	obj := getKubernetesObject(t, fileName)
-	crb, ok := obj.(*rbacv1.ClusterRoleBinding)
-	assert.True(t, ok, "Expected ClusterRoleBinding object")
+	crb := &rbacv1.ClusterRoleBinding{}
+	GetExpectedObject(t, fileName, crb)
 	return crb
 }
 
 // GetExpectedRole returns the Role object from given yaml spec file
 func GetExpectedRole(t *testing.T, fileName string) *rbacv1.Role {
-	obj := getKubernetesObject(t, fileName)
-	role, ok := obj.(*rbacv1.Role)
-	assert.True(t, ok, "Expected Role object")
+	role := &rbacv1.Role{}
+	GetExpectedObject(t, fileName, role)
 	return role
 }
 
 // GetExpectedRoleBinding returns the RoleBinding object from given yaml spec file
 func GetExpectedRoleBinding(t *testing.T, fileName string) *rbacv1.RoleBinding {
-	obj := getKubernetesObject(t, fileName)
-	roleBinding, ok := obj.(*rbacv1.RoleBinding)
-	assert.True(t, ok, "Expected RoleBinding object")
+	roleBinding := &rbacv1.RoleBinding{}
+	GetExpectedObject(t, fileName, roleBinding)
 	return roleBinding
 }
 
 // GetExpectedStorageClass returns the StorageClass object from given yaml spec file
 func GetExpectedStorageClass(t *testing.T, fileName string) *storagev1.StorageClass {
-	obj := getKubernetesObject(t, fileName)
-	storageClass, ok := obj.(*storagev1.StorageClass)
-	assert.True(t, ok, "Expected StorageClass object")
+	storageClass := &storagev1.StorageClass{}
+	GetExpectedObject(t, fileName, storageClass)
 	return storageClass
 }
 
 // GetExpectedConfigMap returns the ConfigMap object from given yaml spec file
 func GetExpectedConfigMap(t *testing.T, fileName string) *v1.ConfigMap {
-	obj := getKubernetesObject(t, fileName)
-	configMap, ok := obj.(*v1.ConfigMap)
-	assert.True(t, ok, "Expected ConfigMap object")
+	configMap := &v1.ConfigMap{}
+	GetExpectedObject(t, fileName, configMap)
 	return configMap
 }
 
 // GetExpectedSecret returns the Secret object from given yaml spec file
 func GetExpectedSecret(t *testing.T, fileName string) *v1.Secret {
-	obj := getKubernetesObject(t, fileName)
-	secret, ok := obj.(*v1.Secret)
-	assert.True(t, ok, "Expected Secret object")
+	secret := &v1.Secret{}
+	GetExpectedObject(t, fileName, secret)
 	return secret
 }
 
+// GetExpectedServiceAccount returns the ServiceAccount object from given yaml spec file
+func GetExpectedServiceAccount(t *testing.T, fileName string) *v1.ServiceAccount {
+	serviceAccount := &v1.ServiceAccount{}
+	GetExpectedObject(t, fileName, serviceAccount)
+	return serviceAccount
+}
+
 // GetExpectedService returns the Service object from given yaml spec file
 func GetExpectedService(t *testing.T, fileName string) *v1.Service {
-	obj := getKubernetesObject(t, fileName)
-	service, ok := obj.(*v1.Service)
-	assert.True(t, ok, "Expected Service object")
+	service := &v1.Service{}
+	GetExpectedObject(t, fileName, service)
 	return service
 }
 
 // GetExpectedDeployment returns the Deployment object from given yaml spec file
 func GetExpectedDeployment(t *testing.T, fileName string) *appsv1.Deployment {
-	obj := getKubernetesObject(t, fileName)
-	deployment, ok := obj.(*appsv1.Deployment)
-	assert.True(t, ok, "Expected Deployment object")
+	deployment := &appsv1.Deployment{}
+	GetExpectedObject(t, fileName, deployment)
 	return deployment
 }
 
 // GetExpectedStatefulSet returns the StatefulSet object from given yaml spec file
 func GetExpectedStatefulSet(t *testing.T, fileName string) *appsv1.StatefulSet {
-	obj := getKubernetesObject(t, fileName)
-	statefulSet, ok := obj.(*appsv1.StatefulSet)
-	assert.True(t, ok, "Expected StatefulSet object")
+	statefulSet := &appsv1.StatefulSet{}
+	GetExpectedObject(t, fileName, statefulSet)
 	return statefulSet
 }
 
 // GetExpectedDaemonSet returns the DaemonSet object from given yaml spec file
 func GetExpectedDaemonSet(t *testing.T, fileName string) *appsv1.DaemonSet {
-	obj := getKubernetesObject(t, fileName)
-	daemonSet, ok := obj.(*appsv1.DaemonSet)
-	assert.True(t, ok, "Expected DaemonSet object")
+	daemonSet := &appsv1.DaemonSet{}
+	GetExpectedObject(t, fileName, daemonSet)
 	return daemonSet
 }
 
 // GetExpectedCRD returns the CustomResourceDefinition object from given yaml spec file
 func GetExpectedCRD(t *testing.T, fileName string) *apiextensionsv1beta1.CustomResourceDefinition {
-	obj := getKubernetesObject(t, fileName)
-	crd, ok := obj.(*apiextensionsv1beta1.CustomResourceDefinition)
-	assert.True(t, ok, "Expected CustomResourceDefinition object")
+	crd := &apiextensionsv1beta1.CustomResourceDefinition{}
+	GetExpectedObject(t, fileName, crd)
 	return crd
 }
 
 // GetExpectedCRDV1 returns the CustomResourceDefinition object from given yaml spec file
 func GetExpectedCRDV1(t *testing.T, fileName string) *apiextensionsv1.CustomResourceDefinition {
-	obj := getKubernetesObject(t, fileName)
-	crd, ok := obj.(*apiextensionsv1.CustomResourceDefinition)
-	assert.True(t, ok, "Expected CustomResourceDefinition object")
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	GetExpectedObject(t, fileName, crd)
 	return crd
 }
 
 // GetExpectedPrometheus returns the Prometheus object from given yaml spec file
 func GetExpectedPrometheus(t *testing.T, fileName string) *monitoringv1.Prometheus {
-	obj := getKubernetesObject(t, fileName)
-	prometheus, ok := obj.(*monitoringv1.Prometheus)
-	assert.True(t, ok, "Expected Prometheus object")
+	prometheus := &monitoringv1.Prometheus{}
+	GetExpectedObject(t, fileName, prometheus)
 	return prometheus
 }
 
 // GetExpectedServiceMonitor returns the ServiceMonitor object from given yaml spec file
 func GetExpectedServiceMonitor(t *testing.T, fileName string) *monitoringv1.ServiceMonitor {
-	obj := getKubernetesObject(t, fileName)
-	serviceMonitor, ok := obj.(*monitoringv1.ServiceMonitor)
-	assert.True(t, ok, "Expected ServiceMonitor object")
+	serviceMonitor := &monitoringv1.ServiceMonitor{}
+	GetExpectedObject(t, fileName, serviceMonitor)
 	return serviceMonitor
 }
 
 // GetExpectedPrometheusRule returns the PrometheusRule object from given yaml spec file
 func GetExpectedPrometheusRule(t *testing.T, fileName string) *monitoringv1.PrometheusRule {
-	obj := getKubernetesObject(t, fileName)
-	prometheusRule, ok := obj.(*monitoringv1.PrometheusRule)
-	assert.True(t, ok, "Expected PrometheusRule object")
+	prometheusRule := &monitoringv1.PrometheusRule{}
+	GetExpectedObject(t, fileName, prometheusRule)
 	return prometheusRule
 }
 
 // GetExpectedAlertManager returns the AlertManager object from given yaml spec file
 func GetExpectedAlertManager(t *testing.T, fileName string) *monitoringv1.Alertmanager {
-	obj := getKubernetesObject(t, fileName)
-	alertManager, ok := obj.(*monitoringv1.Alertmanager)
-	assert.True(t, ok, "Expected Alertmanager object")
+	alertManager := &monitoringv1.Alertmanager{}
+	GetExpectedObject(t, fileName, alertManager)
 	return alertManager
-
 }
 
 // GetExpectedPSP returns the PodSecurityPolicy object from given yaml spec file
 func GetExpectedPSP(t *testing.T, fileName string) *policyv1beta1.PodSecurityPolicy {
-	obj := getKubernetesObject(t, fileName)
-	psp, ok := obj.(*policyv1beta1.PodSecurityPolicy)
-	assert.True(t, ok, "Expected PodSecurityPolicy object")
+	psp := &policyv1beta1.PodSecurityPolicy{}
+	GetExpectedObject(t, fileName, psp)
 	return psp
 }
 
+// GetExpectedPDB returns the PodDisruptionBudget object from given yaml spec file
+func GetExpectedPDB(t *testing.T, fileName string) *policyv1beta1.PodDisruptionBudget {
+	pdb := &policyv1beta1.PodDisruptionBudget{}
+	GetExpectedObject(t, fileName, pdb)
+	return pdb
+}
+
 // GetExpectedSCC returns the SecurityContextConstraints object from given yaml spec file
 func GetExpectedSCC(t *testing.T, fileName string) *ocp_secv1.SecurityContextConstraints {
-	obj := getKubernetesObject(t, fileName)
-	scc, ok := obj.(*ocp_secv1.SecurityContextConstraints)
-	assert.True(t, ok, "Expected SecurityContextConstraints object")
+	scc := &ocp_secv1.SecurityContextConstraints{}
+	GetExpectedObject(t, fileName, scc)
 	return scc
 }
 
+// GetExpectedObject decodes the Kubernetes object from given yaml spec file into
+// the given object. It fails the test and returns false if the spec file has an
+// object of a different type.
+func GetExpectedObject(t *testing.T, fileName string, into runtime.Object) bool {
+	obj := getKubernetesObject(t, fileName)
+	if !assert.IsType(t, into, obj, "Expected %T object", into) {
+		return false
+	}
+	reflect.ValueOf(into).Elem().Set(reflect.ValueOf(obj).Elem())
+	return true
+}
+
+// GetExpectedObjects returns all the Kubernetes objects from given multi-document
+// yaml spec file. Empty documents are skipped.
+func GetExpectedObjects(t *testing.T, fileName string) []runtime.Object {
+	file, err := os.Open(path.Join(TestSpecPath, fileName))
+	if !assert.NoError(t, err) {
+		return nil
+	}
+	defer file.Close()
+
+	codecs := getDecodeCodecs()
+
+	var objects []runtime.Object
+	specReader := yaml.NewYAMLReader(bufio.NewReader(file))
+	for {
+		specContents, err := specReader.Read()
+		if err == io.EOF {
+			break
+		} else if !assert.NoError(t, err) {
+			return nil
+		}
+
+		if len(bytes.TrimSpace(specContents)) == 0 {
+			continue
+		}
+		obj, _, err := codecs.UniversalDeserializer().Decode(specContents, nil, nil)
+		if !assert.NoError(t, err) {
+			return nil
+		}
+		objects = append(objects, obj)
+	}
+	return objects
+}
+
 // getKubernetesObject returns a generic Kubernetes object from given yaml file
 func getKubernetesObject(t *testing.T, fileName string) runtime.Object {
 	json, err := ioutil.ReadFile(path.Join(TestSpecPath, fileName))
 	assert.NoError(t, err)
-	s := scheme.Scheme
-	apiextensionsv1beta1.AddToScheme(s)
-	apiextensionsv1.AddToScheme(s)
-	monitoringv1.AddToScheme(s)
-	ocp_secv1.Install(s)
-	codecs := serializer.NewCodecFactory(s)
+	codecs := getDecodeCodecs()
 	obj, _, err := codecs.UniversalDeserializer().Decode([]byte(json), nil, nil)
 	assert.NoError(t, err)
 	return obj
@@ -316,7 +673,21 @@ func GetPullPolicyForContainer(
 	deployment *appsv1.Deployment,
 	containerName string,
 ) v1.PullPolicy {
-	for _, c := range deployment.Spec.Template.Spec.Containers {
+	return GetPullPolicy(&deployment.Spec.Template.Spec, containerName)
+}
+
+// GetPullPolicy returns the image pull policy for given pod spec and container name.
+// Both containers and init containers are searched.
+func GetPullPolicy(
+	podSpec *v1.PodSpec,
+	containerName string,
+) v1.PullPolicy {
+	for _, c := range podSpec.Containers {
+		if c.Name == containerName {
+			return c.ImagePullPolicy
+		}
+	}
+	for _, c := range podSpec.InitContainers {
 		if c.Name == containerName {
 			return c.ImagePullPolicy
 		}
@@ -394,6 +765,19 @@ func UninstallStorageCluster(cluster *corev1.StorageCluster, kubeconfig ...strin
 	return operatorops.Instance().DeleteStorageCluster(cluster.Name, cluster.Namespace)
 }
 
+// UninstallStorageClusterAndWait uninstalls the StorageCluster same as UninstallStorageCluster and
+// waits until the StorageCluster and its pods are removed
+func UninstallStorageClusterAndWait(
+	cluster *corev1.StorageCluster,
+	timeout, interval time.Duration,
+	kubeconfig ...string,
+) error {
+	if err := UninstallStorageCluster(cluster, kubeconfig...); err != nil {
+		return err
+	}
+	return validateStorageClusterAndPodsDeleted(cluster, timeout, interval)
+}
+
 // FindAndCopyVsphereSecretToCustomNamespace attempt to find and copy PX vSphere secret to a given namespace
 func FindAndCopyVsphereSecretToCustomNamespace(customNamespace string) error {
 	var pxVsphereSecret *v1.Secret
@@ -483,6 +867,143 @@ func CreateVsphereCredentialEnvVarsFromSecret(namespace string) ([]v1.EnvVar, er
 	return envVars, nil
 }
 
+// ValidationCheck is the outcome of a single check performed while validating a StorageCluster
+type ValidationCheck struct {
+	// Name of the check
+	Name string
+	// Duration is how long the check took
+	Duration time.Duration
+	// Attempts is the number of attempts made by the tasks the check retries with its context,
+	// 0 if the check is not retried or does not take a context
+	Attempts int
+	// Err is the error returned by the check, nil if it passed
+	Err error
+}
+
+// ValidationReport is a timed report of the checks performed while validating a StorageCluster
+type ValidationReport struct {
+	// Checks in the order they were performed
+	Checks []ValidationCheck
+	// Duration is the total time taken by all the checks
+	Duration time.Duration
+}
+
+// retryAttemptsKey is the context key of the counter of attempts made by doRetryWithContext
+type retryAttemptsKey struct{}
+
+// withRetryAttempts returns a context that counts the attempts of the tasks retried with it,
+// along with the counter, so the attempts can be reported per check in a ValidationReport
+func withRetryAttempts(ctx context.Context) (context.Context, *int64) {
+	attempts := new(int64)
+	return context.WithValue(ctx, retryAttemptsKey{}, attempts), attempts
+}
+
+// doRetryWithTimeout retries the given task same as task.DoRetryWithTimeout
+func doRetryWithTimeout(t func() (interface{}, bool, error), timeout, timeBeforeRetry time.Duration) (interface{}, error) {
+	return doRetryWithContext(context.Background(), t, timeout, timeBeforeRetry)
+}
+
+// doRetryWithContext retries the given task until it succeeds, returns an error that should not
+// be retried, or the timeout is reached. Unlike task.DoRetryWithTimeout, the retries stop as soon
+// as the given context is cancelled, in which case the context error is returned.
+func doRetryWithContext(
+	ctx context.Context,
+	t func() (interface{}, bool, error),
+	timeout, timeBeforeRetry time.Duration,
+) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	retryCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// Buffered, so the task goroutine never blocks once nobody is waiting for the result
+	attempts, _ := ctx.Value(retryAttemptsKey{}).(*int64)
+	resultChan := make(chan interface{}, 1)
+	errChan := make(chan error, 1)
+	go func() {
+		for {
+			if attempts != nil {
+				atomic.AddInt64(attempts, 1)
+			}
+			out, retry, err := t()
+			if err == nil {
+				resultChan <- out
+				return
+			}
+			if !retry {
+				errChan <- err
+				return
+			}
+
+			logrus.Debugf("%v Next retry in: %v", err, timeBeforeRetry)
+			select {
+			case <-retryCtx.Done():
+				return
+			case <-time.After(timeBeforeRetry):
+			}
+		}
+	}()
+
+	select {
+	case result := <-resultChan:
+		return result, nil
+	case err := <-errChan:
+		return nil, err
+	case <-retryCtx.Done():
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return nil, &task.ErrTimedOut{Reason: retryCtx.Err().Error()}
+	}
+}
+
+// run performs the given check and records its outcome in the report. The check is given
+// a context that counts the attempts of the tasks it retries with it.
+func (r *ValidationReport) run(ctx context.Context, name string, check func(ctx context.Context) error) error {
+	if r == nil {
+		return check(ctx)
+	}
+
+	checkCtx, attempts := withRetryAttempts(ctx)
+	start := time.Now()
+	err := check(checkCtx)
+	duration := time.Since(start)
+
+	r.Checks = append(r.Checks, ValidationCheck{
+		Name:     name,
+		Duration: duration,
+		Attempts: int(atomic.LoadInt64(attempts)),
+		Err:      err,
+	})
+	r.Duration += duration
+	return err
+}
+
+// ValidateOptions are the options for validating a StorageCluster
+type ValidateOptions struct {
+	// PxImageList is the list of expected images, keyed by component
+	PxImageList map[string]string
+	// ClusterSpec is the expected StorageCluster
+	ClusterSpec *corev1.StorageCluster
+	// Timeout is the timeout of every retried check
+	Timeout time.Duration
+	// Interval is the interval between the retries of a check
+	Interval time.Duration
+	// ShouldStartSuccessfully is true if the cluster is expected to come online, false if
+	// it is expected to fail
+	ShouldStartSuccessfully bool
+	// Kubeconfig is the path of the kubeconfig to use, if empty the current one is used
+	Kubeconfig string
+	// SkipSDKNodeValidation skips validating the Portworx nodes through the SDK, for
+	// environments where the SDK endpoint is not reachable. The pods and components
+	// of the cluster are still validated.
+	SkipSDKNodeValidation bool
+	// CSINamespace is the namespace of the px-csi-ext deployment, for layouts that run the
+	// CSI sidecars outside of the StorageCluster namespace. Defaults to the cluster namespace.
+	CSINamespace string
+}
+
 // ValidateStorageCluster validates a StorageCluster spec
 func ValidateStorageCluster(
 	pxImageList map[string]string,
@@ -491,31 +1012,121 @@ func ValidateStorageCluster(
 	shouldStartSuccessfully bool,
 	kubeconfig ...string,
 ) error {
+	return ValidateStorageClusterCtx(context.Background(), pxImageList, clusterSpec, timeout, interval, shouldStartSuccessfully, kubeconfig...)
+}
+
+// ValidateStorageClusterCtx validates a StorageCluster spec same as ValidateStorageCluster, and
+// stops waiting for the cluster, its nodes and pods as soon as the given context is cancelled.
+// The component checks are not started once the context is cancelled, but are not interrupted.
+func ValidateStorageClusterCtx(
+	ctx context.Context,
+	pxImageList map[string]string,
+	clusterSpec *corev1.StorageCluster,
+	timeout, interval time.Duration,
+	shouldStartSuccessfully bool,
+	kubeconfig ...string,
+) error {
+	return ValidateStorageClusterWithOptionsCtx(ctx, newValidateOptions(pxImageList, clusterSpec, timeout, interval, shouldStartSuccessfully, kubeconfig...))
+}
+
+// ValidateStorageClusterWithOptions validates a StorageCluster spec same as ValidateStorageCluster,
+// with the expected cluster and the checks given by the options
+func ValidateStorageClusterWithOptions(opts *ValidateOptions) error {
+	return ValidateStorageClusterWithOptionsCtx(context.Background(), opts)
+}
+
+// ValidateStorageClusterWithOptionsCtx validates a StorageCluster spec same as
+// ValidateStorageClusterWithOptions, and can be cancelled same as ValidateStorageClusterCtx
+func ValidateStorageClusterWithOptionsCtx(ctx context.Context, opts *ValidateOptions) error {
+	return validateStorageCluster(ctx, opts, nil)
+}
+
+// ValidateStorageClusterWithReport validates a StorageCluster spec same as ValidateStorageCluster,
+// and also returns a report with the duration, attempts and error of each check performed.
+// The report contains all checks up to and including the first one that failed.
+func ValidateStorageClusterWithReport(
+	pxImageList map[string]string,
+	clusterSpec *corev1.StorageCluster,
+	timeout, interval time.Duration,
+	shouldStartSuccessfully bool,
+	kubeconfig ...string,
+) (*ValidationReport, error) {
+	report := &ValidationReport{}
+	err := validateStorageCluster(context.Background(), newValidateOptions(pxImageList, clusterSpec, timeout, interval, shouldStartSuccessfully, kubeconfig...), report)
+	return report, err
+}
+
+func newValidateOptions(
+	pxImageList map[string]string,
+	clusterSpec *corev1.StorageCluster,
+	timeout, interval time.Duration,
+	shouldStartSuccessfully bool,
+	kubeconfig ...string,
+) *ValidateOptions {
+	opts := &ValidateOptions{
+		PxImageList:             pxImageList,
+		ClusterSpec:             clusterSpec,
+		Timeout:                 timeout,
+		Interval:                interval,
+		ShouldStartSuccessfully: shouldStartSuccessfully,
+	}
+	if len(kubeconfig) != 0 {
+		opts.Kubeconfig = kubeconfig[0]
+	}
+	return opts
+}
+
+func validateStorageCluster(ctx context.Context, opts *ValidateOptions, report *ValidationReport) error {
+	if opts == nil || opts.ClusterSpec == nil {
+		return fmt.Errorf("no StorageCluster given to validate")
+	}
+	pxImageList := opts.PxImageList
+	clusterSpec := opts.ClusterSpec
+	timeout, interval := opts.Timeout, opts.Interval
+
 	// Set kubeconfig
-	if len(kubeconfig) != 0 && kubeconfig[0] != "" {
-		os.Setenv("KUBECONFIG", kubeconfig[0])
+	if opts.Kubeconfig != "" {
+		os.Setenv("KUBECONFIG", opts.Kubeconfig)
+	}
+
+	// Do not start any more checks once the context is cancelled
+	run := func(name string, check func(ctx context.Context) error) error {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("validation of StorageCluster %s/%s cancelled before %s check, Err: %w",
+				clusterSpec.Namespace, clusterSpec.Name, name, err)
+		}
+		return report.run(ctx, name, check)
 	}
 
 	// Validate StorageCluster
 	var liveCluster *corev1.StorageCluster
 	var err error
-	if shouldStartSuccessfully {
-		liveCluster, err = ValidateStorageClusterIsOnline(clusterSpec, timeout, interval)
+	if opts.ShouldStartSuccessfully {
+		err = run("StorageClusterIsOnline", func(ctx context.Context) error {
+			liveCluster, err = validateStorageClusterIsOnline(ctx, clusterSpec, timeout, interval)
+			return err
+		})
 		if err != nil {
 			return err
 		}
 	} else {
 		// If we shouldn't start successfully, this is all we need to check
-		return validateStorageClusterIsFailed(clusterSpec, timeout, interval)
+		return run("StorageClusterIsFailed", func(ctx context.Context) error {
+			return validateStorageClusterIsFailed(ctx, clusterSpec, timeout, interval)
+		})
 	}
 
 	// Validate that spec matches live spec
-	if err = validateDeployedSpec(clusterSpec, liveCluster); err != nil {
+	if err = run("DeployedSpec", func(context.Context) error {
+		return validateDeployedSpec(clusterSpec, liveCluster)
+	}); err != nil {
 		return err
 	}
 
 	// Validate StorageNodes
-	if err = validateStorageNodes(pxImageList, clusterSpec, timeout, interval); err != nil {
+	if err = run("StorageNodes", func(ctx context.Context) error {
+		return validateStorageNodes(ctx, pxImageList, clusterSpec, timeout, interval)
+	}); err != nil {
 		return err
 	}
 
@@ -529,33 +1140,45 @@ func ValidateStorageCluster(
 	podTestFn := func(pod v1.Pod) bool {
 		return coreops.Instance().IsPodReady(pod)
 	}
-	if err = validateStorageClusterPods(clusterSpec, expectedPxNodeNameList, timeout, interval, podTestFn); err != nil {
+	if err = run("StorageClusterPods", func(ctx context.Context) error {
+		return validateStorageClusterPodsWithThreshold(ctx, clusterSpec, expectedPxNodeNameList, timeout, interval, podTestFn, 100)
+	}); err != nil {
 		return err
 	}
 
 	// Validate Portworx nodes
-	if err = validatePortworxNodes(liveCluster, len(expectedPxNodeNameList)); err != nil {
+	if opts.SkipSDKNodeValidation {
+		logrus.Debug("Skipping validation of Portworx nodes through the SDK")
+	} else if err = run("PortworxNodes", func(context.Context) error {
+		return validatePortworxNodes(liveCluster, len(expectedPxNodeNameList))
+	}); err != nil {
 		return err
 	}
 
 	// Validate Portworx Service
-	if err = validatePortworxService(liveCluster.Namespace); err != nil {
+	if err = run("PortworxService", func(context.Context) error {
+		return validatePortworxService(liveCluster.Namespace)
+	}); err != nil {
 		return err
 	}
 
 	// Validate Portworx API Service
-	if err = validatePortworxAPIService(liveCluster, timeout, interval); err != nil {
+	if err = run("PortworxAPIService", func(ctx context.Context) error {
+		return validatePortworxAPIService(ctx, liveCluster, timeout, interval)
+	}); err != nil {
 		return err
 	}
 
-	if err = validateComponents(pxImageList, liveCluster, timeout, interval); err != nil {
+	if err = run("Components", func(context.Context) error {
+		return validateComponents(pxImageList, liveCluster, opts.CSINamespace, timeout, interval)
+	}); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func validateStorageNodes(pxImageList map[string]string, cluster *corev1.StorageCluster, timeout, interval time.Duration) error {
+func validateStorageNodes(ctx context.Context, pxImageList map[string]string, cluster *corev1.StorageCluster, timeout, interval time.Duration) error {
 	var expectedPxVersion string
 
 	imageOverride := ""
@@ -568,7 +1191,13 @@ func validateStorageNodes(pxImageList map[string]string, cluster *corev1.Storage
 		}
 	}
 
-	expectedPxVersion = getPxVersion(pxImageList, cluster)
+	// The expected PX version is only needed if we didn't specify a custom image
+	if imageOverride == "" {
+		var err error
+		if expectedPxVersion, err = ExtractPxVersion(pxImageList, cluster); err != nil {
+			return err
+		}
+	}
 
 	t := func() (interface{}, bool, error) {
 		// Get all StorageNodes
@@ -579,7 +1208,7 @@ func validateStorageNodes(pxImageList map[string]string, cluster *corev1.Storage
 
 		// Check StorageNodes status and PX version
 		expectedStatus := "Online"
-		var readyNodes int
+		var laggingNodes []string
 		for _, storageNode := range storageNodeList.Items {
 			logString := fmt.Sprintf("storagenode: %s Expected status: %s Got: %s, ", storageNode.Name, expectedStatus, storageNode.Status.Phase)
 			if imageOverride != "" {
@@ -590,75 +1219,359 @@ func validateStorageNodes(pxImageList map[string]string, cluster *corev1.Storage
 			logrus.Debug(logString)
 
 			// Don't mark this node as ready if it's not in the expected phase
+			var reasons []string
 			if storageNode.Status.Phase != expectedStatus {
-				continue
+				reasons = append(reasons, fmt.Sprintf("phase %q", storageNode.Status.Phase))
 			}
 			// If we didn't specify a custom image, make sure it's running the expected version
 			if imageOverride == "" && !strings.Contains(storageNode.Spec.Version, expectedPxVersion) {
-				continue
+				reasons = append(reasons, fmt.Sprintf("version %q, expected %s", storageNode.Spec.Version, expectedPxVersion))
+			}
+			if len(reasons) > 0 {
+				laggingNodes = append(laggingNodes, fmt.Sprintf("%s (%s)", storageNode.Name, strings.Join(reasons, ", ")))
 			}
-
-			readyNodes++
 		}
 
-		if readyNodes != len(storageNodeList.Items) {
-			return nil, true, fmt.Errorf("waiting for all storagenodes to be ready: %d/%d", readyNodes, len(storageNodeList.Items))
+		if len(laggingNodes) > 0 {
+			return nil, true, fmt.Errorf("waiting for all storagenodes to be ready: %d/%d, lagging: %s",
+				len(storageNodeList.Items)-len(laggingNodes), len(storageNodeList.Items), strings.Join(laggingNodes, "; "))
 		}
 		return nil, false, nil
 	}
 
-	if _, err := task.DoRetryWithTimeout(t, timeout, interval); err != nil {
+	if _, err := doRetryWithContext(ctx, t, timeout, interval); err != nil {
+		// Report the lagging nodes from the last check instead of the retry timeout
+		if _, ok := err.(*task.ErrTimedOut); ok {
+			if _, _, nodesErr := t(); nodesErr != nil {
+				return fmt.Errorf("failed to wait for StorageNodes to be ready, Err: %v", nodesErr)
+			}
+		}
 		return err
 	}
 
 	return nil
 }
 
-// nodeSpecsToMaps takes the given node spec list and converts it to a map of node names to
-// cloud storage specs. Note that this will not work for label selectors at the moment, only
-// node names.
-func nodeSpecsToMaps(nodes []corev1.NodeSpec) map[string]*corev1.CloudStorageNodeSpec {
-	toReturn := map[string]*corev1.CloudStorageNodeSpec{}
+// ValidateStorageNodesVersion validates that all StorageNodes of the given cluster
+// report the expected PX version, retrying until they converge or timeout is reached
+func ValidateStorageNodesVersion(cluster *corev1.StorageCluster, expectedVersion string, timeout, interval time.Duration) error {
+	t := func() (interface{}, bool, error) {
+		storageNodeList, err := operatorops.Instance().ListStorageNodes(cluster.Namespace)
+		if err != nil {
+			return nil, true, fmt.Errorf("failed to list StorageNodes in %s, Err: %v", cluster.Namespace, err)
+		}
 
-	for _, node := range nodes {
-		toReturn[node.Selector.NodeName] = node.CloudStorage
-	}
+		if len(storageNodeList.Items) == 0 {
+			return nil, true, fmt.Errorf("waiting for StorageNodes to be created in %s", cluster.Namespace)
+		}
 
-	return toReturn
+		var nodesNotUpdated []string
+		for _, storageNode := range storageNodeList.Items {
+			logrus.Debugf("storagenode: %s Expected PX version: %s Got: %s", storageNode.Name, expectedVersion, storageNode.Spec.Version)
+			if !strings.Contains(storageNode.Spec.Version, expectedVersion) {
+				nodesNotUpdated = append(nodesNotUpdated, storageNode.Name)
+			}
+		}
+
+		if len(nodesNotUpdated) > 0 {
+			return nil, true, fmt.Errorf("waiting for storagenodes %v to be on PX version %s: %d/%d",
+				nodesNotUpdated, expectedVersion, len(storageNodeList.Items)-len(nodesNotUpdated), len(storageNodeList.Items))
+		}
+		return nil, false, nil
+	}
+
+	if _, err := doRetryWithTimeout(t, timeout, interval); err != nil {
+		return err
+	}
+
+	logrus.Debugf("All StorageNodes are on PX version %s", expectedVersion)
+	return nil
+}
+
+// ValidateStorageNodeStatusDetails validates that the network and storage details in the
+// status of the given StorageNode are populated and plausible. Any non-empty field in the
+// expected status must also match exactly. The first missing or invalid field is returned.
+func ValidateStorageNodeStatusDetails(node *corev1.StorageNode, expected *corev1.NodeStatus) error {
+	if expected == nil {
+		expected = &corev1.NodeStatus{}
+	}
+
+	ips := []struct {
+		field    string
+		actual   string
+		expected string
+	}{
+		{"network.dataIP", node.Status.Network.DataIP, expected.Network.DataIP},
+		{"network.mgmtIP", node.Status.Network.MgmtIP, expected.Network.MgmtIP},
+	}
+	for _, ip := range ips {
+		if ip.actual == "" {
+			return fmt.Errorf("StorageNode %s/%s is missing status.%s", node.Namespace, node.Name, ip.field)
+		}
+		if net.ParseIP(ip.actual) == nil {
+			return fmt.Errorf("StorageNode %s/%s has invalid status.%s %s", node.Namespace, node.Name, ip.field, ip.actual)
+		}
+		if ip.expected != "" && ip.expected != ip.actual {
+			return fmt.Errorf("StorageNode %s/%s has wrong status.%s, expected: %s, actual: %s",
+				node.Namespace, node.Name, ip.field, ip.expected, ip.actual)
+		}
+	}
+
+	storage := node.Status.Storage
+	if storage.TotalSize.IsZero() {
+		return fmt.Errorf("StorageNode %s/%s is missing status.storage.totalSize", node.Namespace, node.Name)
+	}
+	if storage.UsedSize.Cmp(storage.TotalSize) > 0 {
+		return fmt.Errorf("StorageNode %s/%s has status.storage.usedSize %s greater than totalSize %s",
+			node.Namespace, node.Name, storage.UsedSize.String(), storage.TotalSize.String())
+	}
+	if !expected.Storage.TotalSize.IsZero() && expected.Storage.TotalSize.Cmp(storage.TotalSize) != 0 {
+		return fmt.Errorf("StorageNode %s/%s has wrong status.storage.totalSize, expected: %s, actual: %s",
+			node.Namespace, node.Name, expected.Storage.TotalSize.String(), storage.TotalSize.String())
+	}
+	return nil
+}
+
+// nodeSpecsToMaps takes the given node spec list and converts it to a map of node names to
+// cloud storage specs. Note that this will not work for label selectors at the moment, only
+// node names.
+func nodeSpecsToMaps(nodes []corev1.NodeSpec) map[string]*corev1.CloudStorageNodeSpec {
+	toReturn := map[string]*corev1.CloudStorageNodeSpec{}
+
+	for _, node := range nodes {
+		toReturn[node.Selector.NodeName] = node.CloudStorage
+	}
+
+	return toReturn
 }
 
 func validateDeployedSpec(expected, live *corev1.StorageCluster) error {
-	// Validate cloudStorage
-	if !reflect.DeepEqual(expected.Spec.CloudStorage, live.Spec.CloudStorage) {
-		return fmt.Errorf("deployed CloudStorage spec doesn't match expected")
+	if diffs := DiffStorageClusterSpec(expected, live); len(diffs) > 0 {
+		return fmt.Errorf("deployed spec doesn't match expected, mismatched fields: %s", strings.Join(diffs, ", "))
+	}
+	return nil
+}
+
+// DiffStorageClusterSpec compares the expected StorageCluster spec with the live one and
+// returns the paths of all fields that differ. Placement and Storage are defaulted by the
+// operator, so they are only compared if they are set in the expected spec.
+func DiffStorageClusterSpec(expected, live *corev1.StorageCluster) []string {
+	var diffs []string
+	addIfDifferent := func(path string, expectedVal, liveVal interface{}) {
+		if !reflect.DeepEqual(expectedVal, liveVal) {
+			diffs = append(diffs, path)
+		}
+	}
+
+	addIfDifferent("spec.cloudStorage", expected.Spec.CloudStorage, live.Spec.CloudStorage)
+	addIfDifferent("spec.kvdb", expected.Spec.Kvdb, live.Spec.Kvdb)
+
+	// Nodes are compared by node name, as only node name selectors are supported
+	expectedNodes := nodeSpecsToMaps(expected.Spec.Nodes)
+	liveNodes := nodeSpecsToMaps(live.Spec.Nodes)
+	nodeNames := make(map[string]bool)
+	for name := range expectedNodes {
+		nodeNames[name] = true
+	}
+	for name := range liveNodes {
+		nodeNames[name] = true
 	}
-	// Validate kvdb
-	if !reflect.DeepEqual(expected.Spec.Kvdb, live.Spec.Kvdb) {
-		return fmt.Errorf("deployed Kvdb spec doesn't match expected")
+	var sortedNodeNames []string
+	for name := range nodeNames {
+		sortedNodeNames = append(sortedNodeNames, name)
 	}
-	// Validate nodes
-	if !reflect.DeepEqual(nodeSpecsToMaps(expected.Spec.Nodes), nodeSpecsToMaps(live.Spec.Nodes)) {
-		return fmt.Errorf("deployed Nodes spec doesn't match expected")
+	sort.Strings(sortedNodeNames)
+	for _, name := range sortedNodeNames {
+		expectedNode, expectedOk := expectedNodes[name]
+		liveNode, liveOk := liveNodes[name]
+		if expectedOk != liveOk {
+			diffs = append(diffs, fmt.Sprintf("spec.nodes[%s]", name))
+			continue
+		}
+		addIfDifferent(fmt.Sprintf("spec.nodes[%s].cloudStorage", name), expectedNode, liveNode)
+	}
+
+	addIfDifferent("spec.resources", expected.Spec.Resources, live.Spec.Resources)
+	if expected.Spec.Placement != nil {
+		addIfDifferent("spec.placement", expected.Spec.Placement, live.Spec.Placement)
 	}
+	addIfDifferent("spec.env", expected.Spec.Env, live.Spec.Env)
+	addIfDifferent("spec.network", expected.Spec.Network, live.Spec.Network)
+	if expected.Spec.Storage != nil {
+		addIfDifferent("spec.storage", expected.Spec.Storage, live.Spec.Storage)
+	}
+	addIfDifferent("spec.runtimeOptions", expected.Spec.RuntimeOpts, live.Spec.RuntimeOpts)
+	addIfDifferent("spec.volumes", expected.Spec.Volumes, live.Spec.Volumes)
+
+	return diffs
+}
 
-	// TODO: validate more parts of the spec as we test with them
+// ValidatePortworxDaemonSet validates that the live DaemonSet with the name of the expected one
+// in the given namespace matches the expected DaemonSet. Only the key fields compared by
+// DiffDaemonSetSpec are validated.
+func ValidatePortworxDaemonSet(expected *appsv1.DaemonSet, namespace string, timeout, interval time.Duration) error {
+	t := func() (interface{}, bool, error) {
+		live, err := appops.Instance().GetDaemonSet(expected.Name, namespace)
+		if err != nil {
+			return nil, true, fmt.Errorf("failed to get DaemonSet %s/%s, Err: %v", namespace, expected.Name, err)
+		}
+		if diffs := DiffDaemonSetSpec(expected, live); len(diffs) > 0 {
+			return nil, true, fmt.Errorf("DaemonSet %s/%s doesn't match expected, mismatched fields: %s",
+				namespace, expected.Name, strings.Join(diffs, ", "))
+		}
+		return nil, false, nil
+	}
 
+	if _, err := doRetryWithTimeout(t, timeout, interval); err != nil {
+		// Re-check once more so the mismatched fields are reported instead of the retry timeout
+		if _, _, checkErr := t(); checkErr != nil {
+			return checkErr
+		}
+		return err
+	}
 	return nil
 }
 
-// NewResourceVersion creates a random 16 character string
-// to simulate a k8s resource version
+// DiffDaemonSetSpec compares the pod template of the expected DaemonSet with the live one and
+// returns the paths of all fields that differ. The compared fields are the image, command, args
+// and volume mounts of every container and init container, the volumes and the tolerations.
+func DiffDaemonSetSpec(expected, live *appsv1.DaemonSet) []string {
+	var diffs []string
+	addIfDifferent := func(path string, expectedVal, liveVal interface{}) {
+		if !reflect.DeepEqual(expectedVal, liveVal) {
+			diffs = append(diffs, path)
+		}
+	}
+
+	expectedPodSpec := expected.Spec.Template.Spec
+	livePodSpec := live.Spec.Template.Spec
+	diffs = append(diffs, diffContainers("spec.template.spec.initContainers", expectedPodSpec.InitContainers, livePodSpec.InitContainers)...)
+	diffs = append(diffs, diffContainers("spec.template.spec.containers", expectedPodSpec.Containers, livePodSpec.Containers)...)
+
+	// Volumes are compared by name, so the order of the volumes does not matter
+	liveVolumes := make(map[string]v1.Volume)
+	for _, volume := range livePodSpec.Volumes {
+		liveVolumes[volume.Name] = volume
+	}
+	expectedVolumeNames := make(map[string]bool)
+	for _, volume := range expectedPodSpec.Volumes {
+		expectedVolumeNames[volume.Name] = true
+		path := fmt.Sprintf("spec.template.spec.volumes[%s]", volume.Name)
+		liveVolume, ok := liveVolumes[volume.Name]
+		if !ok {
+			diffs = append(diffs, path)
+			continue
+		}
+		addIfDifferent(path, volume.VolumeSource, liveVolume.VolumeSource)
+	}
+	for _, volume := range livePodSpec.Volumes {
+		if !expectedVolumeNames[volume.Name] {
+			diffs = append(diffs, fmt.Sprintf("spec.template.spec.volumes[%s]", volume.Name))
+		}
+	}
+
+	addIfDifferent("spec.template.spec.tolerations", expectedPodSpec.Tolerations, livePodSpec.Tolerations)
+	return diffs
+}
+
+func diffContainers(path string, expected, live []v1.Container) []string {
+	var diffs []string
+	liveContainers := make(map[string]v1.Container)
+	for _, container := range live {
+		liveContainers[container.Name] = container
+	}
+	expectedNames := make(map[string]bool)
+	for _, expectedContainer := range expected {
+		expectedNames[expectedContainer.Name] = true
+		containerPath := fmt.Sprintf("%s[%s]", path, expectedContainer.Name)
+		liveContainer, ok := liveContainers[expectedContainer.Name]
+		if !ok {
+			diffs = append(diffs, containerPath)
+			continue
+		}
+		if expectedContainer.Image != liveContainer.Image {
+			diffs = append(diffs, containerPath+".image")
+		}
+		if diff := diffStringSlices(expectedContainer.Command, liveContainer.Command); diff != "" {
+			diffs = append(diffs, containerPath+".command")
+		}
+		if diff := diffStringSlices(expectedContainer.Args, liveContainer.Args); diff != "" {
+			diffs = append(diffs, containerPath+".args")
+		}
+		diffs = append(diffs, diffVolumeMounts(containerPath+".volumeMounts", expectedContainer.VolumeMounts, liveContainer.VolumeMounts)...)
+	}
+	for _, liveContainer := range live {
+		if !expectedNames[liveContainer.Name] {
+			diffs = append(diffs, fmt.Sprintf("%s[%s]", path, liveContainer.Name))
+		}
+	}
+	return diffs
+}
+
+// diffVolumeMounts compares the volume mounts by volume name, mount path and sub path, so the order
+// of the mounts does not matter and the same volume can be mounted more than once
+func diffVolumeMounts(path string, expected, live []v1.VolumeMount) []string {
+	var diffs []string
+	liveMounts := make(map[string]v1.VolumeMount)
+	for _, mount := range live {
+		liveMounts[volumeMountKey(mount)] = mount
+	}
+	expectedKeys := make(map[string]bool)
+	for _, mount := range expected {
+		key := volumeMountKey(mount)
+		expectedKeys[key] = true
+		if liveMount, ok := liveMounts[key]; !ok || !reflect.DeepEqual(mount, liveMount) {
+			diffs = append(diffs, fmt.Sprintf("%s[%s]", path, key))
+		}
+	}
+	for _, mount := range live {
+		if key := volumeMountKey(mount); !expectedKeys[key] {
+			diffs = append(diffs, fmt.Sprintf("%s[%s]", path, key))
+		}
+	}
+	return diffs
+}
+
+// volumeMountKey identifies a volume mount by its volume name, mount path and sub path
+func volumeMountKey(mount v1.VolumeMount) string {
+	key := mount.Name + ":" + mount.MountPath
+	if mount.SubPath != "" {
+		key += ":" + mount.SubPath
+	}
+	return key
+}
+
+// NewResourceVersion creates a 16 character resource version to simulate
+// a k8s resource version. Versions strictly increase with every call.
 func NewResourceVersion() string {
-	var randBytes = make([]byte, 32)
-	_, err := rand.Read(randBytes)
+	return formatResourceVersion(atomic.AddUint64(&resourceVersion, 1))
+}
+
+// NewResourceVersionAfter creates a resource version that is greater than the
+// given previous version. If the previous version is not numeric, it returns
+// the same as NewResourceVersion.
+func NewResourceVersionAfter(prev string) string {
+	prevVersion, err := strconv.ParseUint(prev, 10, 64)
 	if err != nil {
-		return ""
+		return NewResourceVersion()
+	}
+	for {
+		current := atomic.LoadUint64(&resourceVersion)
+		next := current + 1
+		if next <= prevVersion {
+			next = prevVersion + 1
+		}
+		if atomic.CompareAndSwapUint64(&resourceVersion, current, next) {
+			return formatResourceVersion(next)
+		}
 	}
+}
 
-	ver := make([]byte, base64.StdEncoding.EncodedLen(len(randBytes)))
-	base64.StdEncoding.Encode(ver, randBytes)
+// resourceVersion is the last resource version returned by NewResourceVersion
+var resourceVersion uint64
 
-	return string(ver[:16])
+func formatResourceVersion(version uint64) string {
+	return fmt.Sprintf("%016d", version)
 }
 
 func getSdkConnection(cluster *corev1.StorageCluster) (*grpc.ClientConn, error) {
@@ -667,310 +1580,1719 @@ func getSdkConnection(cluster *corev1.StorageCluster) (*grpc.ClientConn, error)
 		return nil, err
 	}
 
-	svc, err := coreops.Instance().GetService("portworx-service", cluster.Namespace)
+	servicePort, nodePort, err := getSdkServicePorts(cluster)
 	if err != nil {
 		return nil, err
 	}
 
-	servicePort := int32(0)
-	nodePort := ""
+	// try over the service endpoint
+	conn, err := dialSdkServer(fmt.Sprintf("%s:%d", pxEndpoint, servicePort))
+	if err == nil {
+		return conn, nil
+	}
+
+	// if service endpoint IP is not accessible, we pick one node IP
+	if conn, nodeErr := getSdkNodeConnection(cluster, nodePort); nodeErr == nil {
+		return conn, nil
+	}
+	return nil, err
+}
+
+// getSdkServicePorts returns the SDK service port and the target port on the nodes
+// from the portworx-service
+func getSdkServicePorts(cluster *corev1.StorageCluster) (int32, string, error) {
+	svc, err := coreops.Instance().GetService("portworx-service", cluster.Namespace)
+	if err != nil {
+		return 0, "", err
+	}
+
 	for _, port := range svc.Spec.Ports {
 		if port.Name == "px-sdk" {
-			servicePort = port.Port
-			nodePort = port.TargetPort.StrVal
-			break
+			return port.Port, port.TargetPort.String(), nil
 		}
 	}
+	return 0, "", fmt.Errorf("px-sdk port not found in service")
+}
 
-	if servicePort == 0 {
-		return nil, fmt.Errorf("px-sdk port not found in service")
-	}
-
-	conn, err := grpc.Dial(fmt.Sprintf("%s:%d", pxEndpoint, servicePort), grpc.WithInsecure())
+// getSdkNodeConnection connects to the SDK server over the first reachable node IP
+func getSdkNodeConnection(cluster *corev1.StorageCluster, nodePort string) (*grpc.ClientConn, error) {
+	addresses, err := getSdkNodeAddresses(cluster)
 	if err != nil {
 		return nil, err
 	}
-	// try over the service endpoint
-	cli := api.NewOpenStorageIdentityClient(conn)
-	if _, err = cli.Version(context.Background(), &api.SdkIdentityVersionRequest{}); err == nil {
-		return conn, nil
-	}
 
-	// if  service endpoint IP is not accessible, we pick one node IP
-	if nodes, err := coreops.Instance().GetNodes(); err == nil {
-		for _, node := range nodes.Items {
-			for _, addr := range node.Status.Addresses {
-				if addr.Type == v1.NodeInternalIP {
-					conn, err := grpc.Dial(fmt.Sprintf("%s:%s", addr.Address, nodePort), grpc.WithInsecure())
-					if err != nil {
-						return nil, err
-					}
-					if _, err = cli.Version(context.Background(), &api.SdkIdentityVersionRequest{}); err == nil {
-						return conn, nil
-					}
-				}
-			}
+	err = fmt.Errorf("no node addresses found to connect to SDK server")
+	for _, address := range addresses {
+		var conn *grpc.ClientConn
+		if conn, err = dialSdkServer(fmt.Sprintf("%s:%s", address, nodePort)); err == nil {
+			return conn, nil
 		}
 	}
 	return nil, err
 }
 
-// ValidateUninstallStorageCluster validates if storagecluster and its related objects
-// were properly uninstalled and cleaned
-func ValidateUninstallStorageCluster(
-	cluster *corev1.StorageCluster,
-	timeout, interval time.Duration,
-	kubeconfig ...string,
-) error {
-	if len(kubeconfig) != 0 && kubeconfig[0] != "" {
-		os.Setenv("KUBECONFIG", kubeconfig[0])
+// getSdkNodeAddresses returns the node addresses the SDK server can be reached on. If a
+// management interface is configured, Portworx binds to the management IP of the node,
+// so those are preferred over the internal IPs of the Kubernetes nodes.
+func getSdkNodeAddresses(cluster *corev1.StorageCluster) ([]string, error) {
+	var addresses []string
+	seen := make(map[string]bool)
+	addAddress := func(address string) {
+		if address != "" && !seen[address] {
+			seen[address] = true
+			addresses = append(addresses, address)
+		}
 	}
-	t := func() (interface{}, bool, error) {
-		cluster, err := operatorops.Instance().GetStorageCluster(cluster.Name, cluster.Namespace)
+
+	if isMgmtInterfaceConfigured(cluster) {
+		mgmtIPs, err := getStorageNodeMgmtIPs(cluster)
 		if err != nil {
-			if errors.IsNotFound(err) {
-				return "", false, nil
-			}
-			return "", true, err
+			logrus.Warnf("Failed to get management IPs of StorageNodes, Err: %v", err)
 		}
-
-		pods, err := coreops.Instance().GetPodsByOwner(cluster.UID, cluster.Namespace)
-		if err != nil && err != k8serrors.ErrPodsNotFound {
-			return "", true, fmt.Errorf("failed to get pods for StorageCluster %s/%s. Err: %v",
-				cluster.Namespace, cluster.Name, err)
+		for _, mgmtIP := range mgmtIPs {
+			addAddress(mgmtIP)
 		}
+	}
 
-		var podsToBeDeleted []string
-		for _, pod := range pods {
-			podsToBeDeleted = append(podsToBeDeleted, pod.Name)
+	nodes, err := coreops.Instance().GetNodes()
+	if err != nil {
+		if len(addresses) > 0 {
+			return addresses, nil
+		}
+		return nil, err
+	}
+	for _, node := range nodes.Items {
+		for _, addr := range node.Status.Addresses {
+			if addr.Type == v1.NodeInternalIP {
+				addAddress(addr.Address)
+			}
 		}
+	}
+	return addresses, nil
+}
 
-		if len(pods) > 0 {
-			return "", true, fmt.Errorf("%d pods are still present, waiting for Portworx pods to be deleted: %s", len(pods), podsToBeDeleted)
+// getStorageNodeMgmtIPs returns the management IPs reported by the StorageNodes
+func getStorageNodeMgmtIPs(cluster *corev1.StorageCluster) ([]string, error) {
+	storageNodeList, err := operatorops.Instance().ListStorageNodes(cluster.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var mgmtIPs []string
+	for _, storageNode := range storageNodeList.Items {
+		if storageNode.Status.Network.MgmtIP != "" {
+			mgmtIPs = append(mgmtIPs, storageNode.Status.Network.MgmtIP)
 		}
+	}
+	return mgmtIPs, nil
+}
 
-		return "", true, fmt.Errorf("pods are deleted, but StorageCluster %v/%v still present",
-			cluster.Namespace, cluster.Name)
+// dialSdkServer connects to the SDK server on the given address and verifies it responds
+func dialSdkServer(address string) (*grpc.ClientConn, error) {
+	conn, err := grpc.Dial(address, grpc.WithInsecure())
+	if err != nil {
+		return nil, err
+	}
+	cli := api.NewOpenStorageIdentityClient(conn)
+	if _, err = cli.Version(context.Background(), &api.SdkIdentityVersionRequest{}); err != nil {
+		conn.Close()
+		return nil, err
 	}
+	return conn, nil
+}
 
-	if _, err := task.DoRetryWithTimeout(t, timeout, interval); err != nil {
-		return err
+func isMgmtInterfaceConfigured(cluster *corev1.StorageCluster) bool {
+	return cluster.Spec.Network != nil &&
+		cluster.Spec.Network.MgmtInterface != nil &&
+		*cluster.Spec.Network.MgmtInterface != ""
+}
+
+// ValidateSDKReachableOnMgmtInterface validates that the SDK server is reachable over the node
+// IPs and that the connection uses the management IP of a StorageNode, as expected when a
+// management interface is configured in the StorageCluster
+func ValidateSDKReachableOnMgmtInterface(cluster *corev1.StorageCluster) error {
+	if !isMgmtInterfaceConfigured(cluster) {
+		return fmt.Errorf("management interface is not configured in StorageCluster %s/%s", cluster.Namespace, cluster.Name)
 	}
 
-	// Validate deletion of Portworx ConfigMaps
-	if err := validatePortworxConfigMapsDeleted(cluster, timeout, interval); err != nil {
+	mgmtIPs, err := getStorageNodeMgmtIPs(cluster)
+	if err != nil {
+		return fmt.Errorf("failed to get management IPs of StorageNodes, Err: %v", err)
+	}
+	if len(mgmtIPs) == 0 {
+		return fmt.Errorf("no StorageNodes in %s report a management IP", cluster.Namespace)
+	}
+
+	_, nodePort, err := getSdkServicePorts(cluster)
+	if err != nil {
 		return err
 	}
 
-	return nil
+	conn, err := getSdkNodeConnection(cluster, nodePort)
+	if err != nil {
+		return fmt.Errorf("failed to connect to SDK server on node IPs, Err: %v", err)
+	}
+	defer conn.Close()
+
+	host, _, err := net.SplitHostPort(conn.Target())
+	if err != nil {
+		return fmt.Errorf("failed to parse SDK connection target %s, Err: %v", conn.Target(), err)
+	}
+	for _, mgmtIP := range mgmtIPs {
+		if host == mgmtIP {
+			logrus.Debugf("SDK server is reachable on management IP %s", host)
+			return nil
+		}
+	}
+	return fmt.Errorf("SDK connection uses address %s, expected one of the management IPs %v", host, mgmtIPs)
 }
 
-func validatePortworxConfigMapsDeleted(cluster *corev1.StorageCluster, timeout, interval time.Duration) error {
-	configMapList := []string{"px-attach-driveset-lock", fmt.Sprintf("px-bootstrap-%s", cluster.Name), "px-bringup-queue-lockdefault", fmt.Sprintf("px-cloud-drive-%s", cluster.Name)}
+// ValidateSDKReachableOnAllNodes tries a Version call against the SDK server on every node IP
+// and returns the result per IP, nil meaning the IP served the call. An error listing the
+// unreachable IPs is returned along with the results if any of the IPs failed.
+func ValidateSDKReachableOnAllNodes(cluster *corev1.StorageCluster) (map[string]error, error) {
+	_, nodePort, err := getSdkServicePorts(cluster)
+	if err != nil {
+		return nil, err
+	}
+	addresses, err := getSdkNodeAddresses(cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get node addresses, Err: %v", err)
+	}
+	if len(addresses) == 0 {
+		return nil, fmt.Errorf("no node addresses found to connect to SDK server")
+	}
 
-	t := func() (interface{}, bool, error) {
-		var presentConfigMaps []string
-		for _, configMapName := range configMapList {
-			_, err := coreops.Instance().GetConfigMap(configMapName, "kube-system")
-			if err != nil {
-				if errors.IsNotFound(err) {
-					continue
-				}
-				return "", true, err
-			}
-			presentConfigMaps = append(presentConfigMaps, configMapName)
-		}
-		if len(presentConfigMaps) > 0 {
-			return "", true, fmt.Errorf("not all expected configMaps have been deleted, waiting for %s to be deleted", presentConfigMaps)
+	results := make(map[string]error)
+	var unreachable []string
+	for _, address := range addresses {
+		conn, err := dialSdkServer(net.JoinHostPort(address, nodePort))
+		if err != nil {
+			unreachable = append(unreachable, address)
+		} else {
+			conn.Close()
 		}
-		return "", false, nil
+		results[address] = err
 	}
 
-	if _, err := task.DoRetryWithTimeout(t, timeout, interval); err != nil {
-		return err
+	if len(unreachable) > 0 {
+		return results, fmt.Errorf("SDK server is not reachable on port %s of node IPs %v, reachable on %d/%d",
+			nodePort, unreachable, len(addresses)-len(unreachable), len(addresses))
 	}
+	logrus.Debugf("SDK server is reachable on port %s of all node IPs %v", nodePort, addresses)
+	return results, nil
+}
 
-	logrus.Debug("Portworx ConfigMaps have been deleted successfully")
-	return nil
+// expectedPortworxServicePorts returns the named ports expected on the portworx-service
+// and the target ports they should point to, based on the start port of the cluster
+func expectedPortworxServicePorts(cluster *corev1.StorageCluster) map[string]int {
+	pxStartPort := startPort(cluster)
+	sdkTargetPort := 9020
+	restGatewayTargetPort := 9021
+	if pxStartPort != 9001 {
+		sdkTargetPort = pxStartPort + 16
+		restGatewayTargetPort = pxStartPort + 17
+	}
+	return map[string]int{
+		"px-api":          pxStartPort,
+		"px-sdk":          sdkTargetPort,
+		"px-rest-gateway": restGatewayTargetPort,
+	}
 }
 
-type podTestFnType func(pod v1.Pod) bool
+// ValidatePortworxService validates that the portworx-service exposes all the expected
+// named ports and that they point to the right target ports on the Portworx pods
+func ValidatePortworxService(cluster *corev1.StorageCluster) error {
+	svc, err := coreops.Instance().GetService("portworx-service", cluster.Namespace)
+	if err != nil {
+		return fmt.Errorf("failed to get service %s/portworx-service, Err: %v", cluster.Namespace, err)
+	}
 
-func validateStorageClusterPods(
-	clusterSpec *corev1.StorageCluster,
-	expectedPxNodeNameList []string,
-	timeout, interval time.Duration,
-	podTestFn podTestFnType,
-) error {
-	t := func() (interface{}, bool, error) {
-		cluster, err := operatorops.Instance().GetStorageCluster(clusterSpec.Name, clusterSpec.Namespace)
-		if err != nil {
-			return "", true, err
+	actualPorts := make(map[string]v1.ServicePort)
+	for _, port := range svc.Spec.Ports {
+		actualPorts[port.Name] = port
+	}
+
+	expectedPorts := expectedPortworxServicePorts(cluster)
+	var portNames []string
+	for name := range expectedPorts {
+		portNames = append(portNames, name)
+	}
+	sort.Strings(portNames)
+
+	var missingPorts, mismatchedPorts []string
+	for _, name := range portNames {
+		port, ok := actualPorts[name]
+		if !ok {
+			missingPorts = append(missingPorts, name)
+		} else if port.TargetPort.String() != strconv.Itoa(expectedPorts[name]) {
+			mismatchedPorts = append(mismatchedPorts, fmt.Sprintf("%s (target port %s, expected %d)",
+				name, port.TargetPort.String(), expectedPorts[name]))
 		}
+	}
 
-		pods, err := coreops.Instance().GetPodsByOwner(cluster.UID, cluster.Namespace)
-		if err != nil || pods == nil {
-			return "", true, fmt.Errorf("failed to get pods for StorageCluster %s/%s. Err: %v",
+	if len(missingPorts) > 0 || len(mismatchedPorts) > 0 {
+		return fmt.Errorf("service %s/portworx-service has missing ports %v and mismatched ports %v",
+			cluster.Namespace, missingPorts, mismatchedPorts)
+	}
+	logrus.Debugf("Validated ports of service %s/portworx-service", cluster.Namespace)
+	return nil
+}
+
+// ValidatePortworxServiceType validates that the portworx-service and portworx-api services
+// are of the expected type and carry the expected annotations, e.g. the cloud provider
+// annotations for an internal load balancer.
+func ValidatePortworxServiceType(
+	cluster *corev1.StorageCluster,
+	expectedType v1.ServiceType,
+	expectedAnnotations map[string]string,
+) error {
+	var svcErrors []string
+	for _, name := range []string{"portworx-service", "portworx-api"} {
+		svc, err := coreops.Instance().GetService(name, cluster.Namespace)
+		if err != nil {
+			return fmt.Errorf("failed to get service %s/%s, Err: %v", cluster.Namespace, name, err)
+		}
+		if svc.Spec.Type != expectedType {
+			svcErrors = append(svcErrors, fmt.Sprintf("service [%s] has type %s, expected: %s", name, svc.Spec.Type, expectedType))
+		}
+		missingAnnotations := getMissingKeyValues(svc.Annotations, expectedAnnotations)
+		if len(missingAnnotations) > 0 {
+			svcErrors = append(svcErrors, fmt.Sprintf("service [%s] is missing annotations %v", name, missingAnnotations))
+		}
+	}
+
+	if len(svcErrors) > 0 {
+		return fmt.Errorf("failed to validate Portworx service type: %s", strings.Join(svcErrors, ", "))
+	}
+	logrus.Debugf("Validated Portworx services in %s are of type %s", cluster.Namespace, expectedType)
+	return nil
+}
+
+// ValidateStorageClasses validates that the default StorageClasses created by the operator for
+// the given cluster match the expected ones. Provisioner, parameters and volume expansion are
+// compared and the first differing field of every StorageClass is reported.
+func ValidateStorageClasses(cluster *corev1.StorageCluster, expected []*storagev1.StorageClass) error {
+	disableAnnotation := componentDisableAnnotations[ComponentStorageClass]
+	if disabled, err := strconv.ParseBool(cluster.Annotations[disableAnnotation.annotation]); err == nil && disabled {
+		return fmt.Errorf("default StorageClasses are disabled on StorageCluster %s/%s using annotation %s",
+			cluster.Namespace, cluster.Name, disableAnnotation.annotation)
+	}
+
+	var scErrors []string
+	for _, expectedSC := range expected {
+		actualSC, err := storageops.Instance().GetStorageClass(expectedSC.Name)
+		if err != nil {
+			scErrors = append(scErrors, fmt.Sprintf("failed to get StorageClass %s, Err: %v", expectedSC.Name, err))
+			continue
+		}
+		if err := validateStorageClass(expectedSC, actualSC); err != nil {
+			scErrors = append(scErrors, err.Error())
+		}
+	}
+
+	if len(scErrors) > 0 {
+		return fmt.Errorf("failed to validate StorageClasses: %s", strings.Join(scErrors, ", "))
+	}
+	logrus.Debugf("Validated %d StorageClasses of StorageCluster %s/%s", len(expected), cluster.Namespace, cluster.Name)
+	return nil
+}
+
+func validateStorageClass(expected, actual *storagev1.StorageClass) error {
+	if expected.Provisioner != actual.Provisioner {
+		return fmt.Errorf("StorageClass [%s] has wrong provisioner, expected: %s, actual: %s",
+			expected.Name, expected.Provisioner, actual.Provisioner)
+	}
+
+	var keys []string
+	for key := range expected.Parameters {
+		keys = append(keys, key)
+	}
+	for key := range actual.Parameters {
+		if _, ok := expected.Parameters[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		expectedValue, expectedOk := expected.Parameters[key]
+		actualValue, actualOk := actual.Parameters[key]
+		switch {
+		case !actualOk:
+			return fmt.Errorf("StorageClass [%s] is missing parameter %s", expected.Name, key)
+		case !expectedOk:
+			return fmt.Errorf("StorageClass [%s] has unexpected parameter %s=%s", expected.Name, key, actualValue)
+		case expectedValue != actualValue:
+			return fmt.Errorf("StorageClass [%s] has wrong parameter %s, expected: %s, actual: %s",
+				expected.Name, key, expectedValue, actualValue)
+		}
+	}
+
+	if expected.AllowVolumeExpansion != nil {
+		actualExpansion := "<nil>"
+		if actual.AllowVolumeExpansion != nil {
+			actualExpansion = strconv.FormatBool(*actual.AllowVolumeExpansion)
+		}
+		if actualExpansion != strconv.FormatBool(*expected.AllowVolumeExpansion) {
+			return fmt.Errorf("StorageClass [%s] has wrong allowVolumeExpansion, expected: %v, actual: %s",
+				expected.Name, *expected.AllowVolumeExpansion, actualExpansion)
+		}
+	}
+	return nil
+}
+
+// ValidateUninstallStorageCluster validates if storagecluster and its related objects
+// were properly uninstalled and cleaned
+func ValidateUninstallStorageCluster(
+	cluster *corev1.StorageCluster,
+	timeout, interval time.Duration,
+	kubeconfig ...string,
+) error {
+	if len(kubeconfig) != 0 && kubeconfig[0] != "" {
+		os.Setenv("KUBECONFIG", kubeconfig[0])
+	}
+
+	if err := validateStorageClusterAndPodsDeleted(cluster, timeout, interval); err != nil {
+		return err
+	}
+
+	// Validate deletion of Portworx ConfigMaps
+	if err := validatePortworxConfigMapsDeleted(cluster, timeout, interval); err != nil {
+		return err
+	}
+
+	// Validate deletion of telemetry resources
+	if err := validateTelemetryResourcesDeleted(cluster, timeout, interval); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateStorageClusterAndPodsDeleted waits until the StorageCluster and its pods are deleted
+func validateStorageClusterAndPodsDeleted(cluster *corev1.StorageCluster, timeout, interval time.Duration) error {
+	t := func() (interface{}, bool, error) {
+		cluster, err := operatorops.Instance().GetStorageCluster(cluster.Name, cluster.Namespace)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return "", false, nil
+			}
+			return "", true, err
+		}
+
+		pods, err := coreops.Instance().GetPodsByOwner(cluster.UID, cluster.Namespace)
+		if err != nil && err != k8serrors.ErrPodsNotFound {
+			return "", true, fmt.Errorf("failed to get pods for StorageCluster %s/%s. Err: %v",
+				cluster.Namespace, cluster.Name, err)
+		}
+
+		var podsToBeDeleted []string
+		for _, pod := range pods {
+			podsToBeDeleted = append(podsToBeDeleted, pod.Name)
+		}
+
+		if len(pods) > 0 {
+			return "", true, fmt.Errorf("%d pods are still present, waiting for Portworx pods to be deleted: %s", len(pods), podsToBeDeleted)
+		}
+
+		return "", true, fmt.Errorf("pods are deleted, but StorageCluster %v/%v still present",
+			cluster.Namespace, cluster.Name)
+	}
+
+	_, err := doRetryWithTimeout(t, timeout, interval)
+	return err
+}
+
+// ValidateUninstallStorageClusterWithCRDs validates the StorageCluster was uninstalled same as
+// ValidateUninstallStorageCluster, and also that the given CRDs were deleted. It should be used
+// only with delete strategies that remove the CRDs, e.g. UninstallAndWipe.
+func ValidateUninstallStorageClusterWithCRDs(
+	cluster *corev1.StorageCluster,
+	crdNames []string,
+	timeout, interval time.Duration,
+	kubeconfig ...string,
+) error {
+	if err := ValidateUninstallStorageCluster(cluster, timeout, interval, kubeconfig...); err != nil {
+		return err
+	}
+	return validateCRDsDeleted(crdNames, timeout, interval)
+}
+
+func validateCRDsDeleted(crdNames []string, timeout, interval time.Duration) error {
+	getPresentCRDs := func() ([]string, error) {
+		var presentCRDs []string
+		for _, crdName := range crdNames {
+			_, err := apiextensionsops.Instance().GetCRD(crdName, metav1.GetOptions{})
+			if err == nil {
+				presentCRDs = append(presentCRDs, crdName)
+			} else if !errors.IsNotFound(err) {
+				return nil, err
+			}
+		}
+		return presentCRDs, nil
+	}
+
+	t := func() (interface{}, bool, error) {
+		presentCRDs, err := getPresentCRDs()
+		if err != nil {
+			return "", true, err
+		}
+		if len(presentCRDs) > 0 {
+			return "", true, fmt.Errorf("waiting for CRDs %s to be deleted", presentCRDs)
+		}
+		return "", false, nil
+	}
+
+	if _, err := doRetryWithTimeout(t, timeout, interval); err != nil {
+		if presentCRDs, _ := getPresentCRDs(); len(presentCRDs) > 0 {
+			return fmt.Errorf("failed to validate CRDs are deleted, still present: %s", strings.Join(presentCRDs, ", "))
+		}
+		return err
+	}
+
+	logrus.Debugf("CRDs %s have been deleted successfully", crdNames)
+	return nil
+}
+
+// validateTelemetryResourcesDeleted validates that the telemetry resources in the cluster namespace are
+// deleted. They are owned by the StorageCluster, so they are removed irrespective of the delete strategy.
+func validateTelemetryResourcesDeleted(cluster *corev1.StorageCluster, timeout, interval time.Duration) error {
+	getPresentResources := func() ([]string, error) {
+		var presentResources []string
+		for _, configMapName := range []string{
+			"px-telemetry-config",
+			"px-ccm-service-proxy-config",
+			"px-collector-config",
+			"px-collector-proxy-config",
+		} {
+			_, err := coreops.Instance().GetConfigMap(configMapName, cluster.Namespace)
+			if err == nil {
+				presentResources = append(presentResources, fmt.Sprintf("ConfigMap %s/%s", cluster.Namespace, configMapName))
+			} else if !errors.IsNotFound(err) {
+				return nil, err
+			}
+		}
+		_, err := coreops.Instance().GetServiceAccount("px-metrics-collector", cluster.Namespace)
+		if err == nil {
+			presentResources = append(presentResources, fmt.Sprintf("ServiceAccount %s/px-metrics-collector", cluster.Namespace))
+		} else if !errors.IsNotFound(err) {
+			return nil, err
+		}
+		return presentResources, nil
+	}
+
+	t := func() (interface{}, bool, error) {
+		presentResources, err := getPresentResources()
+		if err != nil {
+			return "", true, err
+		}
+		if len(presentResources) > 0 {
+			return "", true, fmt.Errorf("waiting for telemetry resources %s to be deleted", presentResources)
+		}
+		return "", false, nil
+	}
+
+	if _, err := doRetryWithTimeout(t, timeout, interval); err != nil {
+		if presentResources, _ := getPresentResources(); len(presentResources) > 0 {
+			return fmt.Errorf("failed to validate telemetry resources are deleted, still present: %s",
+				strings.Join(presentResources, ", "))
+		}
+		return err
+	}
+
+	logrus.Debug("Telemetry resources have been deleted successfully")
+	return nil
+}
+
+func validatePortworxConfigMapsDeleted(cluster *corev1.StorageCluster, timeout, interval time.Duration) error {
+	configMapList := []string{"px-attach-driveset-lock", fmt.Sprintf("px-bootstrap-%s", cluster.Name), "px-bringup-queue-lockdefault", fmt.Sprintf("px-cloud-drive-%s", cluster.Name)}
+
+	t := func() (interface{}, bool, error) {
+		var presentConfigMaps []string
+		for _, configMapName := range configMapList {
+			_, err := coreops.Instance().GetConfigMap(configMapName, "kube-system")
+			if err != nil {
+				if errors.IsNotFound(err) {
+					continue
+				}
+				return "", true, err
+			}
+			presentConfigMaps = append(presentConfigMaps, configMapName)
+		}
+		if len(presentConfigMaps) > 0 {
+			return "", true, fmt.Errorf("not all expected configMaps have been deleted, waiting for %s to be deleted", presentConfigMaps)
+		}
+		return "", false, nil
+	}
+
+	if _, err := doRetryWithTimeout(t, timeout, interval); err != nil {
+		return err
+	}
+
+	logrus.Debug("Portworx ConfigMaps have been deleted successfully")
+	return nil
+}
+
+type podTestFnType func(pod v1.Pod) bool
+
+// ValidateStorageClusterPodsWithThreshold validates that at least minReadyPercent of the expected
+// Portworx pods are ready and that all Portworx pods run on the expected nodes. This is useful to
+// validate a cluster during a disruption window, e.g. a rolling update, when some pods restart.
+func ValidateStorageClusterPodsWithThreshold(
+	clusterSpec *corev1.StorageCluster,
+	minReadyPercent int,
+	timeout, interval time.Duration,
+) error {
+	if minReadyPercent <= 0 || minReadyPercent > 100 {
+		return fmt.Errorf("invalid minimum ready percent %d, should be between 1 and 100", minReadyPercent)
+	}
+
+	// Get list of expected Portworx node names
+	expectedPxNodeNameList, err := GetExpectedPxNodeNameList(clusterSpec)
+	if err != nil {
+		return err
+	}
+
+	podTestFn := func(pod v1.Pod) bool {
+		return coreops.Instance().IsPodReady(pod)
+	}
+	return validateStorageClusterPodsWithThreshold(context.Background(), clusterSpec, expectedPxNodeNameList, timeout, interval, podTestFn, minReadyPercent)
+}
+
+func validateStorageClusterPods(
+	clusterSpec *corev1.StorageCluster,
+	expectedPxNodeNameList []string,
+	timeout, interval time.Duration,
+	podTestFn podTestFnType,
+) error {
+	return validateStorageClusterPodsWithThreshold(context.Background(), clusterSpec, expectedPxNodeNameList, timeout, interval, podTestFn, 100)
+}
+
+// validateStorageClusterPodsWithThreshold validates the Portworx pods of the given cluster. With
+// minReadyPercent of 100, there should be exactly one ready pod on each expected node. Otherwise,
+// at least minReadyPercent of the expected pods should pass the test function.
+func validateStorageClusterPodsWithThreshold(
+	ctx context.Context,
+	clusterSpec *corev1.StorageCluster,
+	expectedPxNodeNameList []string,
+	timeout, interval time.Duration,
+	podTestFn podTestFnType,
+	minReadyPercent int,
+) error {
+	minReadyPods := int(math.Ceil(float64(len(expectedPxNodeNameList)*minReadyPercent) / 100))
+
+	t := func() (interface{}, bool, error) {
+		cluster, err := operatorops.Instance().GetStorageCluster(clusterSpec.Name, clusterSpec.Namespace)
+		if err != nil {
+			return "", true, err
+		}
+
+		pods, err := coreops.Instance().GetPodsByOwner(cluster.UID, cluster.Namespace)
+		if err != nil || pods == nil {
+			return "", true, fmt.Errorf("failed to get pods for StorageCluster %s/%s. Err: %v",
 				cluster.Namespace, cluster.Name, err)
 		}
-
-		if len(pods) != len(expectedPxNodeNameList) {
-			return "", true, fmt.Errorf("expected pods: %v. actual pods: %v", len(expectedPxNodeNameList), len(pods))
+
+		if minReadyPercent >= 100 && len(pods) != len(expectedPxNodeNameList) {
+			return "", true, fmt.Errorf("expected pods: %v. actual pods: %v", len(expectedPxNodeNameList), len(pods))
+		}
+
+		var pxNodeNameList []string
+		var podsNotReady []string
+		var podsReady []string
+		for _, pod := range pods {
+			// if test-function fails, POD is considered as "not ready"
+			if !podTestFn(pod) {
+				podsNotReady = append(podsNotReady, pod.Name)
+			} else {
+				podsReady = append(podsReady, pod.Name)
+			}
+			pxNodeNameList = append(pxNodeNameList, pod.Spec.NodeName)
+		}
+
+		if minReadyPercent >= 100 {
+			if len(podsNotReady) > 0 {
+				return "", true, &ErrPodsNotReady{
+					Namespace: cluster.Namespace,
+					NotReady:  podsNotReady,
+					Ready:     len(podsReady),
+					Expected:  len(expectedPxNodeNameList),
+				}
+			}
+
+			if !assert.ElementsMatch(&testing.T{}, expectedPxNodeNameList, pxNodeNameList) {
+				return "", false, fmt.Errorf("expected Portworx nodes: %+v, got %+v", expectedPxNodeNameList, pxNodeNameList)
+			}
+
+			logrus.Debugf("All Portworx pods are ready: %s", podsReady)
+			return "", false, nil
+		}
+
+		if len(podsReady) < minReadyPods {
+			return "", true, fmt.Errorf("waiting for at least %d/%d (%d%%) Portworx pods to be ready, ready: %d, Err: %w",
+				minReadyPods, len(expectedPxNodeNameList), minReadyPercent, len(podsReady), &ErrPodsNotReady{
+					Namespace: cluster.Namespace,
+					NotReady:  podsNotReady,
+					Ready:     len(podsReady),
+					Expected:  len(expectedPxNodeNameList),
+				})
+		}
+
+		expectedNodes := make(map[string]bool)
+		for _, nodeName := range expectedPxNodeNameList {
+			expectedNodes[nodeName] = true
+		}
+		for _, nodeName := range pxNodeNameList {
+			if !expectedNodes[nodeName] {
+				return "", false, fmt.Errorf("expected Portworx nodes: %+v, found Portworx pod on node %s", expectedPxNodeNameList, nodeName)
+			}
+		}
+
+		logrus.Debugf("%d/%d Portworx pods are ready: %s, not ready: %s", len(podsReady), len(expectedPxNodeNameList), podsReady, podsNotReady)
+		return "", false, nil
+	}
+
+	if _, err := doRetryWithContext(ctx, t, timeout, interval); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("failed to validate Portworx pods, Err: %w", err)
+		}
+		// Re-check once more so the pods that are not ready are reported instead of the retry timeout
+		if _, retry, checkErr := t(); checkErr != nil && retry {
+			return fmt.Errorf("failed to validate Portworx pods, Err: %w", checkErr)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// ValidatePortworxRollingUpdateComplete validates that the rolling update of the Portworx pods
+// has converged, that is every pod of the StorageCluster is ready and runs the latest
+// ControllerRevision of the cluster. As the StorageCluster controller manages the Portworx pods
+// directly instead of through a DaemonSet, the revisions are fetched with the given client.
+func ValidatePortworxRollingUpdateComplete(k8sClient client.Client, cluster *corev1.StorageCluster, timeout, interval time.Duration) error {
+	t := func() (interface{}, bool, error) {
+		updated, desired, err := getPortworxRolloutProgress(k8sClient, cluster)
+		if err != nil {
+			return nil, true, err
+		}
+		if desired == 0 || updated != desired {
+			return nil, true, fmt.Errorf("waiting for rolling update of StorageCluster %s/%s to complete, updated: %d/%d",
+				cluster.Namespace, cluster.Name, updated, desired)
+		}
+		return nil, false, nil
+	}
+
+	if _, err := doRetryWithTimeout(t, timeout, interval); err != nil {
+		// Re-check once more so the rollout progress is reported instead of the retry timeout
+		updated, desired, progressErr := getPortworxRolloutProgress(k8sClient, cluster)
+		if progressErr != nil {
+			return fmt.Errorf("failed to validate rolling update of StorageCluster %s/%s, Err: %v",
+				cluster.Namespace, cluster.Name, progressErr)
+		}
+		return fmt.Errorf("failed to validate rolling update of StorageCluster %s/%s, updated: %d/%d",
+			cluster.Namespace, cluster.Name, updated, desired)
+	}
+
+	logrus.Debugf("Rolling update of StorageCluster %s/%s is complete", cluster.Namespace, cluster.Name)
+	return nil
+}
+
+// getPortworxRolloutProgress returns the number of ready Portworx pods running the latest
+// revision of the cluster, and the total number of Portworx pods of the cluster including
+// the ones being terminated
+func getPortworxRolloutProgress(k8sClient client.Client, cluster *corev1.StorageCluster) (int, int, error) {
+	revisions := &appsv1.ControllerRevisionList{}
+	if err := ListWithOptions(k8sClient, revisions, client.InNamespace(cluster.Namespace)); err != nil {
+		return 0, 0, fmt.Errorf("failed to list controller revisions, Err: %v", err)
+	}
+	var latest *appsv1.ControllerRevision
+	for i := range revisions.Items {
+		revision := &revisions.Items[i]
+		if !metav1.IsControlledBy(revision, cluster) {
+			continue
+		}
+		if latest == nil || revision.Revision > latest.Revision {
+			latest = revision
+		}
+	}
+	if latest == nil {
+		return 0, 0, fmt.Errorf("no controller revision found for StorageCluster %s/%s", cluster.Namespace, cluster.Name)
+	}
+	latestHash := latest.Labels[appsv1.ControllerRevisionHashLabelKey]
+	if latestHash == "" {
+		latestHash = latest.Name
+	}
+
+	pods := &v1.PodList{}
+	if err := ListWithOptions(k8sClient, pods, client.InNamespace(cluster.Namespace)); err != nil {
+		return 0, 0, fmt.Errorf("failed to list pods, Err: %v", err)
+	}
+	updated, desired := 0, 0
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !metav1.IsControlledBy(pod, cluster) {
+			continue
+		}
+		desired++
+		if pod.DeletionTimestamp == nil &&
+			pod.Labels[appsv1.ControllerRevisionHashLabelKey] == latestHash &&
+			podutil.IsPodReady(pod) {
+			updated++
+		}
+	}
+	return updated, desired, nil
+}
+
+// Set default Node Affinity rules as Portworx Operator would when deploying StorageCluster
+func defaultPxNodeAffinityRules(runOnMaster bool) *v1.NodeAffinity {
+	selectorRequirements := []v1.NodeSelectorRequirement{
+		{
+			Key:      pxEnabledLabel,
+			Operator: v1.NodeSelectorOpNotIn,
+			Values:   []string{"false"},
+		},
+	}
+
+	if !runOnMaster {
+		selectorRequirements = append(
+			selectorRequirements,
+			v1.NodeSelectorRequirement{
+				Key:      "node-role.kubernetes.io/master",
+				Operator: v1.NodeSelectorOpDoesNotExist,
+			},
+		)
+	}
+
+	nodeAffinity := &v1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{
+			NodeSelectorTerms: []v1.NodeSelectorTerm{
+				{
+					MatchExpressions: selectorRequirements,
+				},
+			},
+		},
+	}
+
+	return nodeAffinity
+}
+
+// ValidatePxNodeAffinity validates that the Portworx pods carry the node affinity from the
+// cluster placement spec. If the cluster has no node affinity set, the pods are expected to
+// carry the default node affinity rules applied by the operator.
+func ValidatePxNodeAffinity(cluster *corev1.StorageCluster) error {
+	pods, err := coreops.Instance().GetPods(cluster.Namespace, map[string]string{"name": "portworx"})
+	if err != nil {
+		return fmt.Errorf("failed to get Portworx pods, Err: %v", err)
+	}
+	if len(pods.Items) == 0 {
+		return fmt.Errorf("failed to find Portworx pods in %s", cluster.Namespace)
+	}
+
+	var podErrors []string
+	for _, pod := range pods.Items {
+		if err := validatePodNodeAffinity(pod, cluster); err != nil {
+			podErrors = append(podErrors, err.Error())
+		}
+	}
+	if len(podErrors) > 0 {
+		return fmt.Errorf("failed to validate node affinity on Portworx pods: %s",
+			strings.Join(podErrors, ", "))
+	}
+	return nil
+}
+
+func validatePodNodeAffinity(pod v1.Pod, cluster *corev1.StorageCluster) error {
+	var actualTerms []v1.NodeSelectorTerm
+	if pod.Spec.Affinity != nil && pod.Spec.Affinity.NodeAffinity != nil &&
+		pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil {
+		actualTerms = pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	}
+
+	if cluster.Spec.Placement == nil || cluster.Spec.Placement.NodeAffinity == nil {
+		// The operator may add more requirements or terms to the default rules (e.g. on
+		// OpenShift), so only make sure every default term is covered by a term on the pod
+		defaultAffinity := defaultPxNodeAffinityRules(IsK3sCluster())
+		for _, term := range defaultAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+			if !nodeSelectorTermCovered(term, actualTerms) {
+				return fmt.Errorf("pod [%s]: default node affinity term %v is missing, actual: %v",
+					pod.Name, term.MatchExpressions, actualTerms)
+			}
+		}
+		return nil
+	}
+
+	expectedAffinity := cluster.Spec.Placement.NodeAffinity
+	var expectedTerms []v1.NodeSelectorTerm
+	if expectedAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil {
+		expectedTerms = expectedAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	}
+	if len(expectedTerms) != len(actualTerms) {
+		return fmt.Errorf("pod [%s]: expected node selector terms: %v, actual: %v",
+			pod.Name, expectedTerms, actualTerms)
+	}
+	for i := range expectedTerms {
+		if !reflect.DeepEqual(expectedTerms[i], actualTerms[i]) {
+			return fmt.Errorf("pod [%s]: expected node selector term: %v, actual: %v",
+				pod.Name, expectedTerms[i], actualTerms[i])
+		}
+	}
+
+	var actualPreferred []v1.PreferredSchedulingTerm
+	if pod.Spec.Affinity != nil && pod.Spec.Affinity.NodeAffinity != nil {
+		actualPreferred = pod.Spec.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+	}
+	if len(expectedAffinity.PreferredDuringSchedulingIgnoredDuringExecution) != len(actualPreferred) {
+		return fmt.Errorf("pod [%s]: expected preferred scheduling terms: %v, actual: %v",
+			pod.Name, expectedAffinity.PreferredDuringSchedulingIgnoredDuringExecution, actualPreferred)
+	}
+	for i, term := range expectedAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+		if !reflect.DeepEqual(term, actualPreferred[i]) {
+			return fmt.Errorf("pod [%s]: expected preferred scheduling term: %v, actual: %v",
+				pod.Name, term, actualPreferred[i])
+		}
+	}
+	return nil
+}
+
+// nodeSelectorTermCovered returns true if any of the given terms contains
+// all the match expressions of the expected term
+func nodeSelectorTermCovered(expected v1.NodeSelectorTerm, terms []v1.NodeSelectorTerm) bool {
+	for _, term := range terms {
+		covered := true
+		for _, expectedReq := range expected.MatchExpressions {
+			found := false
+			for _, req := range term.MatchExpressions {
+				if reflect.DeepEqual(expectedReq, req) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				covered = false
+				break
+			}
+		}
+		if covered {
+			return true
+		}
+	}
+	return false
+}
+
+func validatePortworxNodes(cluster *corev1.StorageCluster, expectedNodes int) error {
+	conn, err := getSdkConnection(cluster)
+	if err != nil {
+		// CHECKME -- shouldn't we return err ?
+		return nil
+	}
+
+	nodeClient := api.NewOpenStorageNodeClient(conn)
+	nodeEnumerateResp, err := nodeClient.Enumerate(context.Background(), &api.SdkNodeEnumerateRequest{})
+	if err != nil {
+		return err
+	}
+
+	actualNodes := len(nodeEnumerateResp.GetNodeIds())
+	if actualNodes != expectedNodes {
+		return fmt.Errorf("expected nodes: %v. actual nodes: %v", expectedNodes, actualNodes)
+	}
+
+	// TODO: Validate Portworx is started with correct params. Check individual options
+	for _, n := range nodeEnumerateResp.GetNodeIds() {
+		nodeResp, err := nodeClient.Inspect(context.Background(), &api.SdkNodeInspectRequest{NodeId: n})
+		if err != nil {
+			return err
+		}
+		if nodeResp.Node.Status != api.Status_STATUS_OK {
+			return fmt.Errorf("node %s is not online. Current: %v", nodeResp.Node.SchedulerNodeName,
+				nodeResp.Node.Status)
+		}
+
+	}
+	return nil
+}
+
+// ValidateCloudStorageProvisioned validates through the SDK that the drives of every Portworx node
+// match the cloud storage device specs of the node, in count and size. The device specs of a node
+// given in spec.nodes take precedence over the cluster wide cloud storage device specs. Nodes
+// without data drives are only accepted as storageless once the other nodes reach the
+// maxStorageNodes or maxStorageNodesPerZone limit of the cluster.
+func ValidateCloudStorageProvisioned(cluster *corev1.StorageCluster) error {
+	conn, err := getSdkConnection(cluster)
+	if err != nil {
+		return fmt.Errorf("failed to connect to the SDK server, Err: %v", err)
+	}
+	defer conn.Close()
+	return validateCloudStorageProvisioned(cluster, api.NewOpenStorageNodeClient(conn))
+}
+
+func validateCloudStorageProvisioned(cluster *corev1.StorageCluster, nodeClient api.OpenStorageNodeClient) error {
+	nodes, err := inspectPortworxNodes(nodeClient)
+	if err != nil {
+		return err
+	}
+
+	var nodeErrors []string
+	for _, node := range nodes {
+		nodeSpec := getNodeCloudStorageSpec(cluster, node.GetSchedulerNodeName())
+		if nodeSpec == nil || nodeSpec.DeviceSpecs == nil {
+			continue
+		}
+		if err := validateNodeDrives(cluster, node, nodes, *nodeSpec.DeviceSpecs); err != nil {
+			nodeErrors = append(nodeErrors, err.Error())
+		}
+	}
+	if len(nodeErrors) > 0 {
+		return fmt.Errorf("failed to validate cloud storage drives: %s", strings.Join(nodeErrors, ", "))
+	}
+	return nil
+}
+
+// ValidateNodeStorageOverride validates that the effective cloud storage spec of the given node,
+// which is the spec given for the node in spec.nodes or the cluster wide cloud storage spec
+// otherwise, matches the expected one, and that the drives of the live Portworx node match it
+func ValidateNodeStorageOverride(cluster *corev1.StorageCluster, nodeName string, expected *corev1.CloudStorageNodeSpec) error {
+	conn, err := getSdkConnection(cluster)
+	if err != nil {
+		return fmt.Errorf("failed to connect to the SDK server, Err: %v", err)
+	}
+	defer conn.Close()
+	return validateNodeStorageOverride(cluster, nodeName, expected, api.NewOpenStorageNodeClient(conn))
+}
+
+func validateNodeStorageOverride(
+	cluster *corev1.StorageCluster,
+	nodeName string,
+	expected *corev1.CloudStorageNodeSpec,
+	nodeClient api.OpenStorageNodeClient,
+) error {
+	effective := getNodeCloudStorageSpec(cluster, nodeName)
+	if !reflect.DeepEqual(expected, effective) {
+		return fmt.Errorf("effective cloud storage spec of node %s doesn't match expected, expected: %s, actual: %s",
+			nodeName, cloudStorageNodeSpecString(expected), cloudStorageNodeSpecString(effective))
+	}
+	if effective == nil || effective.DeviceSpecs == nil {
+		return nil
+	}
+
+	nodes, err := inspectPortworxNodes(nodeClient)
+	if err != nil {
+		return err
+	}
+	for _, node := range nodes {
+		if node.GetSchedulerNodeName() == nodeName {
+			return validateNodeDrives(cluster, node, nodes, *effective.DeviceSpecs)
+		}
+	}
+	return fmt.Errorf("failed to find Portworx node %s", nodeName)
+}
+
+// inspectPortworxNodes returns all the Portworx nodes with their details
+func inspectPortworxNodes(nodeClient api.OpenStorageNodeClient) ([]*api.StorageNode, error) {
+	nodeEnumerateResp, err := nodeClient.Enumerate(context.Background(), &api.SdkNodeEnumerateRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to enumerate Portworx nodes, Err: %v", err)
+	}
+	var nodes []*api.StorageNode
+	for _, nodeID := range nodeEnumerateResp.GetNodeIds() {
+		nodeResp, err := nodeClient.Inspect(context.Background(), &api.SdkNodeInspectRequest{NodeId: nodeID})
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect Portworx node %s, Err: %v", nodeID, err)
+		}
+		nodes = append(nodes, nodeResp.GetNode())
+	}
+	return nodes, nil
+}
+
+// ValidateStorageNodePoolStatus waits until the last operation of every storage pool of the
+// Portworx nodes reaches the expected status. The StorageNode objects don't carry any pool
+// details, so the pools are read through the SDK. On timeout the error lists the lagging pools.
+func ValidateStorageNodePoolStatus(
+	cluster *corev1.StorageCluster,
+	expectedStatus api.SdkStoragePool_OperationStatus,
+	timeout, interval time.Duration,
+) error {
+	conn, err := getSdkConnection(cluster)
+	if err != nil {
+		return fmt.Errorf("failed to connect to the SDK server, Err: %v", err)
+	}
+	defer conn.Close()
+	return validateStorageNodePoolStatus(expectedStatus, api.NewOpenStorageNodeClient(conn), timeout, interval)
+}
+
+func validateStorageNodePoolStatus(
+	expectedStatus api.SdkStoragePool_OperationStatus,
+	nodeClient api.OpenStorageNodeClient,
+	timeout, interval time.Duration,
+) error {
+	t := func() (interface{}, bool, error) {
+		nodesResp, err := nodeClient.EnumerateWithFilters(context.Background(), &api.SdkNodeEnumerateWithFiltersRequest{})
+		if err != nil {
+			return nil, true, fmt.Errorf("failed to enumerate Portworx nodes, Err: %v", err)
+		}
+
+		var laggingPools []string
+		for _, node := range nodesResp.GetNodes() {
+			for _, pool := range node.GetPools() {
+				// A pool that never had an operation has nothing pending
+				status := api.SdkStoragePool_OPERATION_SUCCESSFUL
+				if pool.GetLastOperation() != nil {
+					status = pool.GetLastOperation().GetStatus()
+				}
+				if status != expectedStatus {
+					laggingPools = append(laggingPools, fmt.Sprintf("node [%s] pool [%s]: %v",
+						node.GetSchedulerNodeName(), pool.GetUuid(), status))
+				}
+			}
+		}
+		if len(laggingPools) > 0 {
+			return nil, true, fmt.Errorf("storage pools are not in status %v: %s",
+				expectedStatus, strings.Join(laggingPools, ", "))
+		}
+		return nil, false, nil
+	}
+
+	if _, err := doRetryWithTimeout(t, timeout, interval); err != nil {
+		// Report the last observed pool statuses instead of the retry timeout
+		if _, _, statusErr := t(); statusErr != nil {
+			err = statusErr
+		}
+		return fmt.Errorf("failed to wait for storage pools to be in status %v, Err: %v", expectedStatus, err)
+	}
+	return nil
+}
+
+// cloudStorageNodeSpecString returns the device specs of the given cloud storage spec in a
+// readable form, as the spec only holds pointers
+func cloudStorageNodeSpecString(spec *corev1.CloudStorageNodeSpec) string {
+	if spec == nil || spec.DeviceSpecs == nil {
+		return "deviceSpecs: []"
+	}
+	return fmt.Sprintf("deviceSpecs: %v", *spec.DeviceSpecs)
+}
+
+// getNodeCloudStorageSpec returns the cloud storage spec of the given node, which is the spec
+// given for the node in spec.nodes, or the cluster wide cloud storage spec otherwise
+func getNodeCloudStorageSpec(cluster *corev1.StorageCluster, nodeName string) *corev1.CloudStorageNodeSpec {
+	if nodeSpec := nodeSpecsToMaps(cluster.Spec.Nodes)[nodeName]; nodeSpec != nil {
+		return nodeSpec
+	}
+	if cluster.Spec.CloudStorage == nil {
+		return nil
+	}
+	return &corev1.CloudStorageNodeSpec{
+		CloudStorageCommon: cluster.Spec.CloudStorage.CloudStorageCommon,
+	}
+}
+
+// validateNodeDrives validates that the data drives of the node match the given device specs.
+// Metadata and cache drives are not compared. A node without data drives is only accepted as
+// storageless when the other nodes already reach the max storage nodes limits of the cluster.
+func validateNodeDrives(cluster *corev1.StorageCluster, node *api.StorageNode, nodes []*api.StorageNode, deviceSpecs []string) error {
+	actualSizes := getDataDriveSizes(node)
+	if len(actualSizes) == 0 && isStoragelessExpected(cluster, node, nodes) {
+		return nil
+	}
+	if len(actualSizes) != len(deviceSpecs) {
+		return fmt.Errorf("node [%s]: expected drives: %d, actual: %d",
+			node.GetSchedulerNodeName(), len(deviceSpecs), len(actualSizes))
+	}
+
+	var expectedSizes []uint64
+	for _, deviceSpec := range deviceSpecs {
+		size, ok := getDeviceSpecSize(deviceSpec)
+		if !ok {
+			// Without a size only the drive count can be validated
+			return nil
+		}
+		expectedSizes = append(expectedSizes, size)
+	}
+	sort.Slice(expectedSizes, func(i, j int) bool { return expectedSizes[i] < expectedSizes[j] })
+	sort.Slice(actualSizes, func(i, j int) bool { return actualSizes[i] < actualSizes[j] })
+	if !reflect.DeepEqual(expectedSizes, actualSizes) {
+		return fmt.Errorf("node [%s]: expected drive sizes (GiB): %v, actual: %v",
+			node.GetSchedulerNodeName(), expectedSizes, actualSizes)
+	}
+	return nil
+}
+
+// getDataDriveSizes returns the sizes in GiB of the data drives of the node
+func getDataDriveSizes(node *api.StorageNode) []uint64 {
+	var sizes []uint64
+	for _, disk := range node.GetDisks() {
+		if disk.Metadata || disk.Cache {
+			continue
+		}
+		sizes = append(sizes, disk.Size/(1024*1024*1024))
+	}
+	return sizes
+}
+
+// isStoragelessExpected returns true if the given node is expected to be storageless, because the
+// nodes with storage already reach the maxStorageNodes or maxStorageNodesPerZone limit. The zone
+// of a node is taken from its topology.kubernetes.io/zone label.
+func isStoragelessExpected(cluster *corev1.StorageCluster, node *api.StorageNode, nodes []*api.StorageNode) bool {
+	cloudStorage := cluster.Spec.CloudStorage
+	if cloudStorage == nil {
+		return false
+	}
+
+	zone := node.GetNodeLabels()[v1.LabelTopologyZone]
+	var storageNodes, zoneStorageNodes uint32
+	for _, n := range nodes {
+		if len(getDataDriveSizes(n)) == 0 {
+			continue
+		}
+		storageNodes++
+		if n.GetNodeLabels()[v1.LabelTopologyZone] == zone {
+			zoneStorageNodes++
+		}
+	}
+	return (cloudStorage.MaxStorageNodes != nil && storageNodes >= *cloudStorage.MaxStorageNodes) ||
+		(cloudStorage.MaxStorageNodesPerZone != nil && zoneStorageNodes >= *cloudStorage.MaxStorageNodesPerZone)
+}
+
+// getDeviceSpecSize returns the size in GiB from a cloud storage device spec such as
+// type=gp2,size=150
+func getDeviceSpecSize(deviceSpec string) (uint64, bool) {
+	for _, option := range strings.Split(deviceSpec, ",") {
+		kv := strings.SplitN(strings.TrimSpace(option), "=", 2)
+		if len(kv) != 2 || kv[0] != "size" {
+			continue
+		}
+		size, err := strconv.ParseUint(kv[1], 10, 64)
+		if err != nil {
+			return 0, false
+		}
+		return size, true
+	}
+	return 0, false
+}
+
+func validatePortworxService(namespace string) error {
+	pxServiceName := "portworx-service"
+	_, err := coreops.Instance().GetService(pxServiceName, namespace)
+	if err != nil {
+		return fmt.Errorf("failed to validate Service %s/%s, Err: %v", namespace, pxServiceName, err)
+	}
+	return nil
+}
+
+func validatePortworxAPIService(ctx context.Context, cluster *corev1.StorageCluster, timeout, interval time.Duration) error {
+	t := func() (interface{}, bool, error) {
+		pxAPIServiceName := "portworx-api"
+		service, err := coreops.Instance().GetService(pxAPIServiceName, cluster.Namespace)
+		if err != nil {
+			return nil, true, fmt.Errorf("failed to validate Service %s/%s, Err: %v", cluster.Namespace, pxAPIServiceName, err)
+		}
+		if cluster.Spec.Metadata != nil && cluster.Spec.Metadata.Labels != nil {
+			for k, expectedVal := range cluster.Spec.Metadata.Labels["service/portworx-api"] {
+				if actualVal, ok := service.Labels[k]; !ok || actualVal != expectedVal {
+					return nil, true, fmt.Errorf("failed to validate Service %s/%s custom labels, label %s doesn't exist or value doesn't match", cluster.Namespace, pxAPIServiceName, k)
+				}
+			}
+			for k := range service.Labels {
+				if k == "name" {
+					continue
+				}
+				if labels, ok := cluster.Spec.Metadata.Labels["service/portworx-api"]; ok {
+					if _, ok := labels[k]; !ok {
+						return nil, true, fmt.Errorf("failed to validate Service %s/%s custom labels, found unexpected label %s", cluster.Namespace, pxAPIServiceName, k)
+					}
+				} else {
+					return nil, true, fmt.Errorf("failed to validate Service %s/%s custom labels, found unexpected label %s", cluster.Namespace, pxAPIServiceName, k)
+				}
+			}
+		}
+		return nil, false, nil
+	}
+
+	if _, err := doRetryWithContext(ctx, t, timeout, interval); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ValidateStorageClusterImages validates that all Portworx pods run the expected oci-monitor
+// image. The image is taken from the PX_IMAGE env variable if set, otherwise from the image
+// list, and is prefixed with the custom image registry of the cluster if any.
+func ValidateStorageClusterImages(pxImageList map[string]string, cluster *corev1.StorageCluster) error {
+	ociMonImage := pxImageList["version"]
+	for _, env := range cluster.Spec.Env {
+		if env.Name == PxImageEnvVarName {
+			ociMonImage = env.Value
+			break
+		}
+	}
+	if ociMonImage == "" {
+		return fmt.Errorf("failed to find image for oci-monitor")
+	}
+	expectedImage := util.GetImageURN(cluster, ociMonImage)
+
+	pods, err := coreops.Instance().GetPods(cluster.Namespace, map[string]string{"name": "portworx"})
+	if err != nil {
+		return fmt.Errorf("failed to get Portworx pods, Err: %v", err)
+	}
+	if len(pods.Items) == 0 {
+		return fmt.Errorf("failed to find Portworx pods in %s", cluster.Namespace)
+	}
+
+	for _, pod := range pods.Items {
+		containerFound := false
+		for _, container := range pod.Spec.Containers {
+			if container.Name != "portworx" {
+				continue
+			}
+			containerFound = true
+			if container.Image != expectedImage {
+				return fmt.Errorf("failed to validate oci-monitor image on pod [%s], expected: %s, actual: %s",
+					pod.Name, expectedImage, container.Image)
+			}
+			break
+		}
+		if !containerFound {
+			return fmt.Errorf("failed to find portworx container in pod [%s]", pod.Name)
+		}
+	}
+
+	logrus.Debugf("All Portworx pods are running oci-monitor image %s", expectedImage)
+	return nil
+}
+
+// ValidatePxImageOverride validates that, when the PX_IMAGE env variable is set in the cluster
+// spec, all Portworx pods run the overridden oci-monitor image. Nothing is validated otherwise.
+func ValidatePxImageOverride(cluster *corev1.StorageCluster) error {
+	for _, env := range cluster.Spec.Env {
+		if env.Name == PxImageEnvVarName {
+			if env.Value == "" {
+				return fmt.Errorf("env variable %s is set to an empty image", PxImageEnvVarName)
+			}
+			return ValidateStorageClusterImages(nil, cluster)
+		}
+	}
+	logrus.Debugf("Env variable %s is not set, skipping image override validation", PxImageEnvVarName)
+	return nil
+}
+
+// ValidatePortworxHostPorts validates that every Portworx pod binds all the expected host ports.
+// Pods on the host network bind the container ports directly on the host, so a container port
+// matches if its host port is unset or equal to it. Other pods need an explicit host port.
+func ValidatePortworxHostPorts(cluster *corev1.StorageCluster, expectedPorts []int32) error {
+	pods, err := coreops.Instance().GetPods(cluster.Namespace, map[string]string{"name": "portworx"})
+	if err != nil {
+		return fmt.Errorf("failed to get Portworx pods, Err: %v", err)
+	}
+	if len(pods.Items) == 0 {
+		return fmt.Errorf("failed to find Portworx pods in %s", cluster.Namespace)
+	}
+
+	var podErrors []string
+	for _, pod := range pods.Items {
+		hostPorts := getPodHostPorts(pod)
+		var missingPorts []int32
+		for _, port := range expectedPorts {
+			if !hostPorts[port] {
+				missingPorts = append(missingPorts, port)
+			}
+		}
+		if len(missingPorts) > 0 {
+			podErrors = append(podErrors, fmt.Sprintf("pod [%s] (hostNetwork: %v) is missing host ports %v",
+				pod.Name, pod.Spec.HostNetwork, missingPorts))
+		}
+	}
+
+	if len(podErrors) > 0 {
+		return fmt.Errorf("failed to validate host ports %v on Portworx pods: %s",
+			expectedPorts, strings.Join(podErrors, ", "))
+	}
+	logrus.Debugf("All Portworx pods bind host ports %v", expectedPorts)
+	return nil
+}
+
+// getPodHostPorts returns the host ports bound by the containers of the given pod
+func getPodHostPorts(pod v1.Pod) map[int32]bool {
+	hostPorts := make(map[int32]bool)
+	for _, container := range pod.Spec.Containers {
+		for _, containerPort := range container.Ports {
+			if containerPort.HostPort != 0 {
+				hostPorts[containerPort.HostPort] = true
+			} else if pod.Spec.HostNetwork {
+				hostPorts[containerPort.ContainerPort] = true
+			}
+		}
+	}
+	return hostPorts
+}
+
+// ValidateStorageClusterAnnotationsPropagated validates that every Portworx pod carries the
+// expected annotations and labels. A key counts as missing if it is not set on the pod or set
+// to a different value.
+func ValidateStorageClusterAnnotationsPropagated(
+	cluster *corev1.StorageCluster,
+	expectedAnnotations map[string]string,
+	expectedLabels map[string]string,
+) error {
+	pods, err := coreops.Instance().GetPods(cluster.Namespace, map[string]string{"name": "portworx"})
+	if err != nil {
+		return fmt.Errorf("failed to get Portworx pods, Err: %v", err)
+	}
+	if len(pods.Items) == 0 {
+		return fmt.Errorf("failed to find Portworx pods in %s", cluster.Namespace)
+	}
+
+	var podErrors []string
+	for _, pod := range pods.Items {
+		missingAnnotations := getMissingKeyValues(pod.Annotations, expectedAnnotations)
+		if len(missingAnnotations) > 0 {
+			podErrors = append(podErrors, fmt.Sprintf("pod [%s] is missing annotations %v", pod.Name, missingAnnotations))
+		}
+		missingLabels := getMissingKeyValues(pod.Labels, expectedLabels)
+		if len(missingLabels) > 0 {
+			podErrors = append(podErrors, fmt.Sprintf("pod [%s] is missing labels %v", pod.Name, missingLabels))
+		}
+	}
+
+	if len(podErrors) > 0 {
+		return fmt.Errorf("failed to validate annotations and labels on Portworx pods: %s",
+			strings.Join(podErrors, ", "))
+	}
+	return nil
+}
+
+// getMissingKeyValues returns the sorted key=value pairs from expected that are not in actual
+func getMissingKeyValues(actual, expected map[string]string) []string {
+	var missing []string
+	for key, value := range expected {
+		if actualValue, ok := actual[key]; !ok || actualValue != value {
+			missing = append(missing, fmt.Sprintf("%s=%s", key, value))
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// ValidateMetadataPropagation validates that the custom labels and annotations configured in
+// the StorageCluster spec metadata are present on every object owned by the cluster in the
+// given object lists. Objects are matched to their spec metadata by kind/name, except for
+// Portworx pods which use the pod/storage key.
+func ValidateMetadataPropagation(
+	k8sClient client.Client,
+	cluster *corev1.StorageCluster,
+	kinds []client.ObjectList,
+) error {
+	var objErrors []string
+	for _, list := range kinds {
+		if err := ListWithOptions(k8sClient, list, client.InNamespace(cluster.Namespace)); err != nil {
+			return fmt.Errorf("failed to list %T in %s, Err: %v", list, cluster.Namespace, err)
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return fmt.Errorf("failed to extract items from %T, Err: %v", list, err)
 		}
 
-		var pxNodeNameList []string
-		var podsNotReady []string
-		var podsReady []string
-		for _, pod := range pods {
-			// if test-function fails, POD is considered as "not ready"
-			if !podTestFn(pod) {
-				podsNotReady = append(podsNotReady, pod.Name)
+		for _, item := range items {
+			obj, ok := item.(client.Object)
+			if !ok || ValidateOwnerReference(obj, cluster) != nil {
+				continue
+			}
+			gvk, err := apiutil.GVKForObject(obj, k8sClient.Scheme())
+			if err != nil {
+				return fmt.Errorf("failed to get kind of %s/%s, Err: %v", obj.GetNamespace(), obj.GetName(), err)
 			}
-			pxNodeNameList = append(pxNodeNameList, pod.Spec.NodeName)
-			podsReady = append(podsReady, pod.Name)
-		}
 
-		if len(podsNotReady) > 0 {
-			return "", true, fmt.Errorf("waiting for Portworx pods to be ready: %s", podsNotReady)
-		}
+			kind := strings.ToLower(gvk.Kind)
+			component := obj.GetName()
+			if kind == "pod" && obj.GetLabels()["name"] == "portworx" {
+				component = "storage"
+			}
+			key := fmt.Sprintf("%s/%s", kind, component)
 
-		if !assert.ElementsMatch(&testing.T{}, expectedPxNodeNameList, pxNodeNameList) {
-			return "", false, fmt.Errorf("expected Portworx nodes: %+v, got %+v", expectedPxNodeNameList, pxNodeNameList)
+			missingAnnotations := getMissingKeyValues(obj.GetAnnotations(), util.GetCustomAnnotations(cluster, kind, component))
+			if len(missingAnnotations) > 0 {
+				objErrors = append(objErrors, fmt.Sprintf("%s [%s] is missing annotations %v", key, obj.GetName(), missingAnnotations))
+			}
+			missingLabels := getMissingKeyValues(obj.GetLabels(), util.GetCustomLabels(cluster, kind, component))
+			if len(missingLabels) > 0 {
+				objErrors = append(objErrors, fmt.Sprintf("%s [%s] is missing labels %v", key, obj.GetName(), missingLabels))
+			}
 		}
+	}
 
-		logrus.Debugf("All Portworx pods are ready: %s", podsReady)
-		return "", false, nil
+	if len(objErrors) > 0 {
+		return fmt.Errorf("failed to validate metadata propagation: %s", strings.Join(objErrors, ", "))
 	}
+	return nil
+}
 
-	if _, err := task.DoRetryWithTimeout(t, timeout, interval); err != nil {
-		return err
+// ValidateReleaseManifestURL validates that the portworx (oci-monitor) container in every
+// Portworx pod has the PX_RELEASE_MANIFEST_URL env var set to the expected URL
+func ValidateReleaseManifestURL(cluster *corev1.StorageCluster, expectedURL string) error {
+	pods, err := coreops.Instance().GetPods(cluster.Namespace, map[string]string{"name": "portworx"})
+	if err != nil {
+		return fmt.Errorf("failed to get Portworx pods, Err: %v", err)
+	}
+	if len(pods.Items) == 0 {
+		return fmt.Errorf("failed to find Portworx pods in %s", cluster.Namespace)
 	}
 
+	var podErrors []string
+	for _, pod := range pods.Items {
+		if err := validatePodReleaseManifestURL(pod, expectedURL); err != nil {
+			podErrors = append(podErrors, err.Error())
+		}
+	}
+	if len(podErrors) > 0 {
+		return fmt.Errorf("failed to validate %s env var on Portworx pods: %s",
+			PxReleaseManifestURLEnvVarName, strings.Join(podErrors, ", "))
+	}
 	return nil
 }
 
-// Set default Node Affinity rules as Portworx Operator would when deploying StorageCluster
-func defaultPxNodeAffinityRules(runOnMaster bool) *v1.NodeAffinity {
-	selectorRequirements := []v1.NodeSelectorRequirement{
-		{
-			Key:      "px/enabled",
-			Operator: v1.NodeSelectorOpNotIn,
-			Values:   []string{"false"},
-		},
+func validatePodReleaseManifestURL(pod v1.Pod, expectedURL string) error {
+	for _, container := range pod.Spec.Containers {
+		if container.Name != "portworx" {
+			continue
+		}
+		for _, env := range container.Env {
+			if env.Name == PxReleaseManifestURLEnvVarName {
+				if env.Value != expectedURL {
+					return fmt.Errorf("pod [%s]: expected: %s, actual: %s", pod.Name, expectedURL, env.Value)
+				}
+				return nil
+			}
+		}
+		return fmt.Errorf("pod [%s]: env var is missing in container portworx", pod.Name)
 	}
+	return fmt.Errorf("pod [%s]: container portworx is missing", pod.Name)
+}
 
-	if !runOnMaster {
-		selectorRequirements = append(
-			selectorRequirements,
-			v1.NodeSelectorRequirement{
-				Key:      "node-role.kubernetes.io/master",
-				Operator: v1.NodeSelectorOpDoesNotExist,
-			},
-		)
+// ValidatePortworxEnv validates that the portworx container in every Portworx pod has the
+// expected env vars merged with the env vars always injected by the operator. An expected
+// env var with the same name as an operator default overrides the default.
+func ValidatePortworxEnv(cluster *corev1.StorageCluster, expectedEnv []v1.EnvVar) error {
+	pods, err := coreops.Instance().GetPods(cluster.Namespace, map[string]string{"name": "portworx"})
+	if err != nil {
+		return fmt.Errorf("failed to get Portworx pods, Err: %v", err)
+	}
+	if len(pods.Items) == 0 {
+		return fmt.Errorf("failed to find Portworx pods in %s", cluster.Namespace)
 	}
 
-	nodeAffinity := &v1.NodeAffinity{
-		RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{
-			NodeSelectorTerms: []v1.NodeSelectorTerm{
-				{
-					MatchExpressions: selectorRequirements,
+	envMap := map[string]v1.EnvVar{
+		"PX_NAMESPACE":         {Name: "PX_NAMESPACE", Value: cluster.Namespace},
+		"PX_SECRETS_NAMESPACE": {Name: "PX_SECRETS_NAMESPACE", Value: cluster.Namespace},
+		"NODE_NAME": {
+			Name: "NODE_NAME",
+			ValueFrom: &v1.EnvVarSource{
+				FieldRef: &v1.ObjectFieldSelector{
+					APIVersion: "v1",
+					FieldPath:  "spec.nodeName",
 				},
 			},
 		},
 	}
+	for _, env := range expectedEnv {
+		envMap[env.Name] = env
+	}
 
-	return nodeAffinity
+	var podErrors []string
+	for _, pod := range pods.Items {
+		if err := validatePodEnv(pod, envMap); err != nil {
+			podErrors = append(podErrors, err.Error())
+		}
+	}
+	if len(podErrors) > 0 {
+		return fmt.Errorf("failed to validate env vars on Portworx pods: %s", strings.Join(podErrors, ", "))
+	}
+	return nil
 }
 
-func validatePortworxNodes(cluster *corev1.StorageCluster, expectedNodes int) error {
-	conn, err := getSdkConnection(cluster)
-	if err != nil {
-		// CHECKME -- shouldn't we return err ?
+func validatePodEnv(pod v1.Pod, expectedEnv map[string]v1.EnvVar) error {
+	for _, container := range pod.Spec.Containers {
+		if container.Name != "portworx" {
+			continue
+		}
+		actualEnv := make(map[string]v1.EnvVar)
+		for _, env := range container.Env {
+			actualEnv[env.Name] = env
+		}
+
+		var envErrors []string
+		for name, expected := range expectedEnv {
+			actual, ok := actualEnv[name]
+			if !ok {
+				envErrors = append(envErrors, fmt.Sprintf("%s is missing", name))
+			} else if actual.Value != expected.Value || !reflect.DeepEqual(actual.ValueFrom, expected.ValueFrom) {
+				envErrors = append(envErrors, fmt.Sprintf("%s expected: %+v, actual: %+v", name, expected, actual))
+			}
+		}
+		if len(envErrors) > 0 {
+			sort.Strings(envErrors)
+			return fmt.Errorf("pod [%s]: %s", pod.Name, strings.Join(envErrors, "; "))
+		}
 		return nil
 	}
+	return fmt.Errorf("pod [%s]: container portworx is missing", pod.Name)
+}
 
-	nodeClient := api.NewOpenStorageNodeClient(conn)
-	nodeEnumerateResp, err := nodeClient.Enumerate(context.Background(), &api.SdkNodeEnumerateRequest{})
-	if err != nil {
-		return err
+// ValidateImagePullSecrets validates that the Portworx pods and the pods of the enabled components
+// (Stork, Stork scheduler, Autopilot and CSI) reference the expected image pull secret
+func ValidateImagePullSecrets(cluster *corev1.StorageCluster, expectedSecretName string) error {
+	if expectedSecretName == "" {
+		return fmt.Errorf("no image pull secret given to validate")
 	}
 
-	actualNodes := len(nodeEnumerateResp.GetNodeIds())
-	if actualNodes != expectedNodes {
-		return fmt.Errorf("expected nodes: %v. actual nodes: %v", expectedNodes, actualNodes)
+	pxPods, err := coreops.Instance().GetPods(cluster.Namespace, map[string]string{"name": "portworx"})
+	if err != nil {
+		return fmt.Errorf("failed to get Portworx pods, Err: %v", err)
 	}
+	if len(pxPods.Items) == 0 {
+		return fmt.Errorf("failed to find Portworx pods in %s", cluster.Namespace)
+	}
+	pods := pxPods.Items
 
-	// TODO: Validate Portworx is started with correct params. Check individual options
-	for _, n := range nodeEnumerateResp.GetNodeIds() {
-		nodeResp, err := nodeClient.Inspect(context.Background(), &api.SdkNodeInspectRequest{NodeId: n})
+	var deploymentNames []string
+	if cluster.Spec.Stork != nil && cluster.Spec.Stork.Enabled {
+		deploymentNames = append(deploymentNames, "stork", "stork-scheduler")
+	}
+	if cluster.Spec.Autopilot != nil && cluster.Spec.Autopilot.Enabled {
+		deploymentNames = append(deploymentNames, "autopilot")
+	}
+	if isCSIEnabled(cluster) {
+		deploymentNames = append(deploymentNames, "px-csi-ext")
+	}
+	for _, name := range deploymentNames {
+		deployment, err := appops.Instance().GetDeployment(name, cluster.Namespace)
 		if err != nil {
-			return err
+			return fmt.Errorf("failed to get deployment %s/%s, Err: %v", cluster.Namespace, name, err)
 		}
-		if nodeResp.Node.Status != api.Status_STATUS_OK {
-			return fmt.Errorf("node %s is not online. Current: %v", nodeResp.Node.SchedulerNodeName,
-				nodeResp.Node.Status)
+		deploymentPods, err := appops.Instance().GetDeploymentPods(deployment)
+		if err != nil {
+			return fmt.Errorf("failed to get pods of deployment %s/%s, Err: %v", cluster.Namespace, name, err)
 		}
+		pods = append(pods, deploymentPods...)
+	}
 
+	var podsMissingSecret []string
+	for _, pod := range pods {
+		if !hasImagePullSecret(pod, expectedSecretName) {
+			podsMissingSecret = append(podsMissingSecret, pod.Name)
+		}
+	}
+	if len(podsMissingSecret) > 0 {
+		return fmt.Errorf("failed to validate image pull secret %s, pods missing the secret: %v",
+			expectedSecretName, podsMissingSecret)
 	}
 	return nil
 }
 
-func validatePortworxService(namespace string) error {
-	pxServiceName := "portworx-service"
-	_, err := coreops.Instance().GetService(pxServiceName, namespace)
-	if err != nil {
-		return fmt.Errorf("failed to validate Service %s/%s, Err: %v", namespace, pxServiceName, err)
+func hasImagePullSecret(pod v1.Pod, secretName string) bool {
+	for _, secret := range pod.Spec.ImagePullSecrets {
+		if secret.Name == secretName {
+			return true
+		}
 	}
-	return nil
+	return false
+}
+
+// ValidateOwnerReference validates that the given object has an owner reference pointing
+// to the given StorageCluster, so it gets garbage collected when the cluster is deleted
+func ValidateOwnerReference(obj client.Object, cluster *corev1.StorageCluster) error {
+	for _, ownerRef := range obj.GetOwnerReferences() {
+		if ownerRef.UID == cluster.UID {
+			return nil
+		}
+	}
+	return fmt.Errorf("failed to find owner reference of StorageCluster %s/%s (UID: %s) on %s/%s, owner references: %+v",
+		cluster.Namespace, cluster.Name, cluster.UID, obj.GetNamespace(), obj.GetName(), obj.GetOwnerReferences())
 }
 
-func validatePortworxAPIService(cluster *corev1.StorageCluster, timeout, interval time.Duration) error {
+// ValidateOciMonitorFullCommand validates that the portworx container in every Portworx pod
+// runs with exactly the expected command, i.e. its Command followed by its Args
+func ValidateOciMonitorFullCommand(cluster *corev1.StorageCluster, expectedCommand []string, timeout, interval time.Duration) error {
+	listOptions := map[string]string{"name": "portworx"}
+
 	t := func() (interface{}, bool, error) {
-		pxAPIServiceName := "portworx-api"
-		service, err := coreops.Instance().GetService(pxAPIServiceName, cluster.Namespace)
+		pods, err := coreops.Instance().GetPods(cluster.Namespace, listOptions)
 		if err != nil {
-			return nil, true, fmt.Errorf("failed to validate Service %s/%s, Err: %v", cluster.Namespace, pxAPIServiceName, err)
+			return nil, true, fmt.Errorf("failed to get Portworx pods, Err: %v", err)
 		}
-		if cluster.Spec.Metadata != nil && cluster.Spec.Metadata.Labels != nil {
-			for k, expectedVal := range cluster.Spec.Metadata.Labels["service/portworx-api"] {
-				if actualVal, ok := service.Labels[k]; !ok || actualVal != expectedVal {
-					return nil, true, fmt.Errorf("failed to validate Service %s/%s custom labels, label %s doesn't exist or value doesn't match", cluster.Namespace, pxAPIServiceName, k)
-				}
-			}
-			for k := range service.Labels {
-				if k == "name" {
+
+		if len(pods.Items) == 0 {
+			return nil, true, fmt.Errorf("waiting for Portworx pods to be created in %s", cluster.Namespace)
+		}
+
+		for _, pod := range pods.Items {
+			containerFound := false
+			for _, container := range pod.Spec.Containers {
+				if container.Name != "portworx" {
 					continue
 				}
-				if labels, ok := cluster.Spec.Metadata.Labels["service/portworx-api"]; ok {
-					if _, ok := labels[k]; !ok {
-						return nil, true, fmt.Errorf("failed to validate Service %s/%s custom labels, found unexpected label %s", cluster.Namespace, pxAPIServiceName, k)
-					}
-				} else {
-					return nil, true, fmt.Errorf("failed to validate Service %s/%s custom labels, found unexpected label %s", cluster.Namespace, pxAPIServiceName, k)
+				containerFound = true
+				actualCommand := append(append([]string{}, container.Command...), container.Args...)
+				if diff := diffStringSlices(expectedCommand, actualCommand); diff != "" {
+					return nil, true, fmt.Errorf("failed to validate command of portworx container in pod [%s]: %s", pod.Name, diff)
 				}
+				break
+			}
+			if !containerFound {
+				return nil, true, fmt.Errorf("failed to find portworx container in pod [%s]", pod.Name)
 			}
 		}
 		return nil, false, nil
 	}
 
-	if _, err := task.DoRetryWithTimeout(t, timeout, interval); err != nil {
+	if _, err := doRetryWithTimeout(t, timeout, interval); err != nil {
 		return err
 	}
 
+	logrus.Debugf("Portworx pods are running with expected command %v", expectedCommand)
 	return nil
 }
 
+// diffStringSlices returns a human readable description of the differences between
+// the expected and actual string slices, or an empty string if they are equal
+func diffStringSlices(expected, actual []string) string {
+	var diffs []string
+	for i := 0; i < len(expected) || i < len(actual); i++ {
+		switch {
+		case i >= len(actual):
+			diffs = append(diffs, fmt.Sprintf("[%d] missing %q", i, expected[i]))
+		case i >= len(expected):
+			diffs = append(diffs, fmt.Sprintf("[%d] unexpected %q", i, actual[i]))
+		case expected[i] != actual[i]:
+			diffs = append(diffs, fmt.Sprintf("[%d] expected %q, got %q", i, expected[i], actual[i]))
+		}
+	}
+	return strings.Join(diffs, ", ")
+}
+
 // GetExpectedPxNodeNameList will get the list of node names that should be included
 // in the given Portworx cluster, by seeing if each non-master node matches the given
-// node selectors and affinities.
+// node selectors, affinities and tolerations. Nodes labeled px/enabled=false are excluded
+// by the default node affinity only, as a custom node affinity replaces it in the operator.
 func GetExpectedPxNodeNameList(cluster *corev1.StorageCluster) ([]string, error) {
 	var nodeNameListWithPxPods []string
+	// The operator does not schedule any Portworx pods if storage is disabled on the cluster
+	if disabled, err := strconv.ParseBool(cluster.Annotations[constants.AnnotationDisableStorage]); err == nil && disabled {
+		return nodeNameListWithPxPods, nil
+	}
+
 	nodeList, err := coreops.Instance().GetNodes()
 	if err != nil {
 		return nodeNameListWithPxPods, err
 	}
 
 	dummyPod := &v1.Pod{}
+	if cluster.Spec.Placement != nil {
+		for _, t := range cluster.Spec.Placement.Tolerations {
+			dummyPod.Spec.Tolerations = append(dummyPod.Spec.Tolerations, *(t.DeepCopy()))
+		}
+	}
+	dummyPod.Spec.Tolerations = append(dummyPod.Spec.Tolerations, defaultPxPodTolerations()...)
+
 	if cluster.Spec.Placement != nil && cluster.Spec.Placement.NodeAffinity != nil {
 		dummyPod.Spec.Affinity = &v1.Affinity{
 			NodeAffinity: cluster.Spec.Placement.NodeAffinity.DeepCopy(),
@@ -1006,7 +3328,14 @@ func GetExpectedPxNodeNameList(cluster *corev1.StorageCluster) ([]string, error)
 			continue
 		}
 
-		if pluginhelper.PodMatchesNodeSelectorAndAffinityTerms(dummyPod, &node) {
+		if !pluginhelper.PodMatchesNodeSelectorAndAffinityTerms(dummyPod, &node) {
+			continue
+		}
+
+		fitsTaints := v1helper.TolerationsTolerateTaintsWithFilter(dummyPod.Spec.Tolerations, node.Spec.Taints, func(t *v1.Taint) bool {
+			return t.Effect == v1.TaintEffectNoExecute || t.Effect == v1.TaintEffectNoSchedule
+		})
+		if fitsTaints {
 			nodeNameListWithPxPods = append(nodeNameListWithPxPods, node.Name)
 		}
 	}
@@ -1014,6 +3343,48 @@ func GetExpectedPxNodeNameList(cluster *corev1.StorageCluster) ([]string, error)
 	return nodeNameListWithPxPods, nil
 }
 
+// defaultPxPodTolerations returns the tolerations for the built-in node taints that
+// the operator adds to every Portworx pod
+func defaultPxPodTolerations() []v1.Toleration {
+	return []v1.Toleration{
+		{
+			Key:      v1.TaintNodeNotReady,
+			Operator: v1.TolerationOpExists,
+			Effect:   v1.TaintEffectNoExecute,
+		},
+		{
+			Key:      v1.TaintNodeUnreachable,
+			Operator: v1.TolerationOpExists,
+			Effect:   v1.TaintEffectNoExecute,
+		},
+		{
+			Key:      v1.TaintNodeDiskPressure,
+			Operator: v1.TolerationOpExists,
+			Effect:   v1.TaintEffectNoSchedule,
+		},
+		{
+			Key:      v1.TaintNodeMemoryPressure,
+			Operator: v1.TolerationOpExists,
+			Effect:   v1.TaintEffectNoSchedule,
+		},
+		{
+			Key:      v1.TaintNodePIDPressure,
+			Operator: v1.TolerationOpExists,
+			Effect:   v1.TaintEffectNoSchedule,
+		},
+		{
+			Key:      v1.TaintNodeUnschedulable,
+			Operator: v1.TolerationOpExists,
+			Effect:   v1.TaintEffectNoSchedule,
+		},
+		{
+			Key:      v1.TaintNodeNetworkUnavailable,
+			Operator: v1.TolerationOpExists,
+			Effect:   v1.TaintEffectNoSchedule,
+		},
+	}
+}
+
 // GetFullVersion returns the full kubernetes server version
 func GetFullVersion() (*version.Version, string, error) {
 	k8sVersion, err := coreops.Instance().GetVersion()
@@ -1045,7 +3416,7 @@ func IsK3sCluster() bool {
 	return false
 }
 
-func validateComponents(pxImageList map[string]string, cluster *corev1.StorageCluster, timeout, interval time.Duration) error {
+func validateComponents(pxImageList map[string]string, cluster *corev1.StorageCluster, csiNamespace string, timeout, interval time.Duration) error {
 	k8sVersion, err := GetK8SVersion()
 	if err != nil {
 		return err
@@ -1067,7 +3438,7 @@ func validateComponents(pxImageList map[string]string, cluster *corev1.StorageCl
 	}
 
 	// Validate CSI components and images
-	if validateCSI(pxImageList, cluster, timeout, interval); err != nil {
+	if err := validateCSI(pxImageList, cluster, csiNamespace, timeout, interval); err != nil {
 		return err
 	}
 
@@ -1108,7 +3479,7 @@ func ValidateKvdb(cluster *corev1.StorageCluster, timeout, interval time.Duratio
 				return nil, true, fmt.Errorf("failed to get KVDB pods, Err: %v", err)
 			}
 
-			desiredKvdbPodCount := 3
+			desiredKvdbPodCount := internalKvdbClusterSize
 			if len(podList.Items) != desiredKvdbPodCount {
 				return nil, true, fmt.Errorf("failed to validate KVDB pod count, expected: %d, actual: %d", desiredKvdbPodCount, len(podList.Items))
 			}
@@ -1126,7 +3497,7 @@ func ValidateKvdb(cluster *corev1.StorageCluster, timeout, interval time.Duratio
 			return nil, false, nil
 		}
 
-		if _, err := task.DoRetryWithTimeout(t, timeout, interval); err != nil {
+		if _, err := doRetryWithTimeout(t, timeout, interval); err != nil {
 			return err
 		}
 
@@ -1134,8 +3505,174 @@ func ValidateKvdb(cluster *corev1.StorageCluster, timeout, interval time.Duratio
 	} else {
 		logrus.Debug("Internal KVDB is Disabled")
 	}
-
-	return nil
+
+	return nil
+}
+
+// ValidateKvdbBackend validates that Portworx runs with the kvdb configured in the StorageCluster.
+// For external kvdb the kvdb endpoints passed to the portworx container of every Portworx pod must
+// match the spec. For internal kvdb the pods must run with internal kvdb enabled, and the number of
+// StorageNodes that are online members of the internal kvdb cluster is validated instead.
+func ValidateKvdbBackend(cluster *corev1.StorageCluster) error {
+	internal := cluster.Spec.Kvdb == nil || cluster.Spec.Kvdb.Internal
+	var endpoints []string
+	if cluster.Spec.Kvdb != nil {
+		endpoints = cluster.Spec.Kvdb.Endpoints
+	}
+
+	pods, err := coreops.Instance().GetPods(cluster.Namespace, map[string]string{"name": "portworx"})
+	if err != nil {
+		return fmt.Errorf("failed to get Portworx pods, Err: %v", err)
+	}
+	if len(pods.Items) == 0 {
+		return fmt.Errorf("failed to find Portworx pods in %s", cluster.Namespace)
+	}
+
+	var podErrors []string
+	for _, pod := range pods.Items {
+		if err := validatePodKvdbBackend(pod, internal, endpoints); err != nil {
+			podErrors = append(podErrors, err.Error())
+		}
+	}
+	if len(podErrors) > 0 {
+		return fmt.Errorf("failed to validate kvdb of Portworx pods: %s", strings.Join(podErrors, ", "))
+	}
+
+	if !internal {
+		return nil
+	}
+
+	if err := validateInternalKvdbMembers(cluster.Namespace, internalKvdbClusterSize); err != nil {
+		return fmt.Errorf("failed to validate internal kvdb members, Err: %v", err)
+	}
+	return nil
+}
+
+func validatePodKvdbBackend(pod v1.Pod, internal bool, endpoints []string) error {
+	for _, container := range pod.Spec.Containers {
+		if container.Name != "portworx" {
+			continue
+		}
+		_, internalEnabled := getContainerFlagValue(container, "-b")
+		if internalEnabled != internal {
+			return fmt.Errorf("pod [%s]: expected internal kvdb: %v, actual: %v", pod.Name, internal, internalEnabled)
+		}
+		expectedEndpoints := strings.Join(endpoints, ",")
+		if actualEndpoints, _ := getContainerFlagValue(container, "-k"); actualEndpoints != expectedEndpoints {
+			return fmt.Errorf("pod [%s]: expected kvdb endpoints: [%s], actual: [%s]", pod.Name, expectedEndpoints, actualEndpoints)
+		}
+		return nil
+	}
+	return fmt.Errorf("pod [%s]: container portworx is missing", pod.Name)
+}
+
+// ValidateInternalKvdbMembers waits until the expected number of StorageNodes are healthy
+// members of the internal kvdb cluster, as reported by their KVDB condition. If expectedMembers
+// is not positive, the default internal kvdb cluster size of 3 is expected.
+func ValidateInternalKvdbMembers(cluster *corev1.StorageCluster, expectedMembers int, timeout, interval time.Duration) error {
+	if cluster.Spec.Kvdb != nil && !cluster.Spec.Kvdb.Internal {
+		return fmt.Errorf("internal kvdb is not enabled in StorageCluster %s/%s", cluster.Namespace, cluster.Name)
+	}
+	if expectedMembers <= 0 {
+		expectedMembers = internalKvdbClusterSize
+	}
+
+	t := func() (interface{}, bool, error) {
+		if err := validateInternalKvdbMembers(cluster.Namespace, expectedMembers); err != nil {
+			return nil, true, err
+		}
+		return nil, false, nil
+	}
+
+	if _, err := doRetryWithTimeout(t, timeout, interval); err != nil {
+		// Report the last observed kvdb members instead of the retry timeout
+		if _, _, membersErr := t(); membersErr != nil {
+			err = membersErr
+		}
+		return fmt.Errorf("failed to validate internal kvdb members, Err: %v", err)
+	}
+	logrus.Debugf("Found %d healthy internal kvdb members", expectedMembers)
+	return nil
+}
+
+// validateInternalKvdbMembers checks that the expected number of StorageNodes report an online
+// KVDB condition, and lists the healthy and unhealthy internal kvdb members otherwise
+func validateInternalKvdbMembers(namespace string, expectedMembers int) error {
+	storageNodeList, err := operatorops.Instance().ListStorageNodes(namespace)
+	if err != nil {
+		return fmt.Errorf("failed to get StorageNodes, Err: %v", err)
+	}
+
+	var healthyMembers, unhealthyMembers []string
+	for _, storageNode := range storageNodeList.Items {
+		for _, condition := range storageNode.Status.Conditions {
+			if condition.Type != corev1.NodeKVDBCondition {
+				continue
+			}
+			if condition.Status == corev1.NodeOnlineStatus {
+				healthyMembers = append(healthyMembers, storageNode.Name)
+			} else {
+				unhealthyMembers = append(unhealthyMembers, fmt.Sprintf("%s (%s)", storageNode.Name, condition.Status))
+			}
+		}
+	}
+
+	if len(healthyMembers) != expectedMembers {
+		return fmt.Errorf("expected %d healthy internal kvdb members, healthy: %v, unhealthy: %v",
+			expectedMembers, healthyMembers, unhealthyMembers)
+	}
+	return nil
+}
+
+// ValidateClusterID validates that the Portworx cluster ID, taken from the cluster-id annotation
+// or the StorageCluster name, is reported in the cluster status along with a cluster UID, and
+// that the portworx container of every Portworx pod is started with the same cluster ID.
+func ValidateClusterID(cluster *corev1.StorageCluster) error {
+	expectedID := cluster.Name
+	if id := cluster.Annotations["portworx.io/cluster-id"]; id != "" {
+		expectedID = id
+	}
+
+	if cluster.Status.ClusterName != expectedID {
+		return fmt.Errorf("StorageCluster %s/%s status has cluster name %q, expected: %q",
+			cluster.Namespace, cluster.Name, cluster.Status.ClusterName, expectedID)
+	}
+	if cluster.Status.ClusterUID == "" {
+		return fmt.Errorf("StorageCluster %s/%s status is missing the cluster UID", cluster.Namespace, cluster.Name)
+	}
+
+	pods, err := coreops.Instance().GetPods(cluster.Namespace, map[string]string{"name": "portworx"})
+	if err != nil {
+		return fmt.Errorf("failed to get Portworx pods, Err: %v", err)
+	}
+	if len(pods.Items) == 0 {
+		return fmt.Errorf("failed to find Portworx pods in %s", cluster.Namespace)
+	}
+
+	var podErrors []string
+	for _, pod := range pods.Items {
+		if err := validatePodClusterID(pod, expectedID); err != nil {
+			podErrors = append(podErrors, err.Error())
+		}
+	}
+	if len(podErrors) > 0 {
+		return fmt.Errorf("failed to validate cluster ID of Portworx pods: %s", strings.Join(podErrors, ", "))
+	}
+	logrus.Debugf("All Portworx pods run with cluster ID %s (UID: %s)", expectedID, cluster.Status.ClusterUID)
+	return nil
+}
+
+func validatePodClusterID(pod v1.Pod, expectedID string) error {
+	for _, container := range pod.Spec.Containers {
+		if container.Name != "portworx" {
+			continue
+		}
+		if actualID, _ := getContainerFlagValue(container, "-c"); actualID != expectedID {
+			return fmt.Errorf("pod [%s]: expected cluster ID: %q, actual: %q", pod.Name, expectedID, actualID)
+		}
+		return nil
+	}
+	return fmt.Errorf("pod [%s]: container portworx is missing", pod.Name)
 }
 
 // ValidatePvcController validates PVC Controller components and images
@@ -1256,21 +3793,13 @@ func ValidateStork(pxImageList map[string]string, cluster *corev1.StorageCluster
 			return err
 		}
 
-		K8sVer1_22, _ := version.NewVersion("1.22")
-		kubeVersion, _, err := GetFullVersion()
-		if err != nil {
+		if err := validateStorkSchedulerImage(cluster); err != nil {
 			return err
 		}
 
-		if kubeVersion != nil && kubeVersion.GreaterThanOrEqual(K8sVer1_22) {
-			// TODO Image tag for stork-scheduler is hardcoded to v1.21.4 for clusters 1.22 and up
-			if err = validateImageTag("v1.21.4", cluster.Namespace, map[string]string{"name": "stork-scheduler"}); err != nil {
-				return err
-			}
-		} else {
-			if err = validateImageTag(k8sVersion, cluster.Namespace, map[string]string{"name": "stork-scheduler"}); err != nil {
-				return err
-			}
+		// Validate stork-scheduler is configured to use the Stork extender
+		if err := ValidateStorkSchedulerConfig(cluster.Namespace); err != nil {
+			return err
 		}
 
 		// Validate webhook-controller arguments
@@ -1283,6 +3812,16 @@ func ValidateStork(pxImageList map[string]string, cluster *corev1.StorageCluster
 			return err
 		}
 
+		// Validate stork pod resources
+		if err := validateStorkResources(cluster, storkDp, timeout, interval); err != nil {
+			return err
+		}
+
+		// Validate stork pod tolerations
+		if err := validateStorkTolerations(cluster, storkDp, timeout, interval); err != nil {
+			return err
+		}
+
 		// Validate stork deployment pod topology spread constraints
 		if err := validatePodTopologySpreadConstraints(storkDp, timeout, interval); err != nil {
 			return err
@@ -1476,42 +4015,69 @@ func validateStorkWebhookController(webhookControllerArgs map[string]string, sto
 			return nil, false, err
 		}
 
-		// Go through every Stork pod and look for --weebhook-controller command in every stork container and match it to the webhook-controller arg passed in spec
+		// Go through every Stork pod and match the --webhook-controller flag of every stork container to the webhook-controller arg passed in spec
 		for _, pod := range pods {
-			webhookExist := false
-			for _, container := range pod.Spec.Containers {
-				if container.Name == "stork" {
-					if len(container.Command) > 0 {
-						for _, containerCommand := range container.Command {
-							if strings.Contains(containerCommand, "--webhook-controller") {
-								if len(webhookControllerArgs["webhook-controller"]) == 0 {
-									return nil, true, fmt.Errorf("failed to validate webhook-controller, webhook-controller is missing from Stork args in the StorageCluster, but is found in the Stork pod [%s]", pod.Name)
-								} else if webhookControllerArgs["webhook-controller"] != strings.Split(containerCommand, "=")[1] {
-									return nil, true, fmt.Errorf("failed to validate webhook-controller, wrong --webhook-controller value in the command in Stork pod [%s]: expected: %s, got: %s", pod.Name, webhookControllerArgs["webhook-controller"], strings.Split(containerCommand, "=")[1])
-								}
-								logrus.Debugf("Value for webhook-controller inside Stork pod [%s] command args: expected %s, got %s", pod.Name, webhookControllerArgs["webhook-controller"], strings.Split(containerCommand, "=")[1])
-								webhookExist = true
-								continue
-							}
-						}
-					}
-					// Validate that if webhook-controller arg is missing from StorageCluster, it is also not found in pods
-					if len(webhookControllerArgs["webhook-controller"]) != 0 && !webhookExist {
-						return nil, true, fmt.Errorf("failed to validate webhook-controller, webhook-controller is found in Stork args in the StorageCluster, but missing from Stork pod [%s]", pod.Name)
-					}
-				}
+			if err := validateStorkPodWebhookController(pod, webhookControllerArgs["webhook-controller"]); err != nil {
+				return nil, true, err
 			}
 		}
 		return nil, false, nil
 	}
 
-	if _, err := task.DoRetryWithTimeout(t, timeout, interval); err != nil {
+	if _, err := doRetryWithTimeout(t, timeout, interval); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+func validateStorkPodWebhookController(pod v1.Pod, expectedValue string) error {
+	for _, container := range pod.Spec.Containers {
+		if container.Name != "stork" {
+			continue
+		}
+		value, found := getContainerFlagValue(container, "--webhook-controller")
+		if !found {
+			// Validate that if webhook-controller arg is missing from StorageCluster, it is also not found in pods
+			if len(expectedValue) != 0 {
+				return fmt.Errorf("failed to validate webhook-controller, webhook-controller is found in Stork args in the StorageCluster, but missing from Stork pod [%s]", pod.Name)
+			}
+			continue
+		}
+		if len(expectedValue) == 0 {
+			return fmt.Errorf("failed to validate webhook-controller, webhook-controller is missing from Stork args in the StorageCluster, but is found in the Stork pod [%s]", pod.Name)
+		} else if expectedValue != value {
+			return fmt.Errorf("failed to validate webhook-controller, wrong --webhook-controller value in the command in Stork pod [%s]: expected: %s, got: %s", pod.Name, expectedValue, value)
+		}
+		logrus.Debugf("Value for webhook-controller inside Stork pod [%s] command args: expected %s, got %s", pod.Name, expectedValue, value)
+	}
+	return nil
+}
+
+// getContainerFlagValue looks for the given flag in the container command and args. Both the
+// "--flag=value" and "--flag value" forms are supported, and a flag without a value is
+// treated as a boolean flag set to true.
+func getContainerFlagValue(container v1.Container, flag string) (string, bool) {
+	var args []string
+	for _, arg := range append(append([]string{}, container.Command...), container.Args...) {
+		args = append(args, strings.Fields(arg)...)
+	}
+
+	for i, arg := range args {
+		if value := strings.TrimPrefix(arg, flag+"="); value != arg {
+			return value, true
+		}
+		if arg != flag {
+			continue
+		}
+		if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+			return args[i+1], true
+		}
+		return "true", true
+	}
+	return "", false
+}
+
 func validateStorkHostNetwork(hostNetwork *bool, storkDeployment *appsv1.Deployment, timeout, interval time.Duration) error {
 	logrus.Debug("Validate Stork hostNetwork")
 
@@ -1538,13 +4104,205 @@ func validateStorkHostNetwork(hostNetwork *bool, storkDeployment *appsv1.Deploym
 		return nil, false, nil
 	}
 
-	if _, err := task.DoRetryWithTimeout(t, timeout, interval); err != nil {
+	if _, err := doRetryWithTimeout(t, timeout, interval); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateStorkSchedulerImage validates the stork-scheduler image. The operator does not take this
+// image from the release manifest, it uses the kube-scheduler image of the Kubernetes version, with
+// the tag pinned to v1.21.4 on Kubernetes 1.22 and up.
+func validateStorkSchedulerImage(cluster *corev1.StorageCluster) error {
+	kubeVersion, _, err := GetFullVersion()
+	if err != nil {
+		return err
+	}
+
+	kubeSchedImage := "gcr.io/google_containers/kube-scheduler-amd64"
+	if isNewKubernetesRegistry(kubeVersion) {
+		kubeSchedImage = "k8s.gcr.io/kube-scheduler-amd64"
+	}
+
+	// TODO Image tag for stork-scheduler is hardcoded to v1.21.4 for clusters 1.22 and up
+	K8sVer1_22, _ := version.NewVersion("1.22")
+	if kubeVersion.GreaterThanOrEqual(K8sVer1_22) {
+		kubeSchedImage = kubeSchedImage + ":v1.21.4"
+	} else {
+		kubeSchedImage = kubeSchedImage + ":v" + kubeVersion.String()
+	}
+	return validateImageOnPods(util.GetImageURN(cluster, kubeSchedImage), cluster.Namespace, map[string]string{"name": "stork-scheduler"})
+}
+
+// isNewKubernetesRegistry mirrors k8sutil.IsNewKubernetesRegistry, which cannot be imported here
+// because the tests of pkg/util/k8s use this package
+func isNewKubernetesRegistry(k8sVersion *version.Version) bool {
+	ver1_16_13, _ := version.NewVersion("1.16.13")
+	ver1_17, _ := version.NewVersion("1.17")
+	ver1_17_9, _ := version.NewVersion("1.17.9")
+	ver1_18, _ := version.NewVersion("1.18")
+	ver1_18_6, _ := version.NewVersion("1.18.6")
+
+	return k8sVersion.GreaterThan(ver1_18_6) ||
+		(k8sVersion.GreaterThan(ver1_16_13) && k8sVersion.LessThan(ver1_17)) ||
+		(k8sVersion.GreaterThan(ver1_17_9) && k8sVersion.LessThan(ver1_18))
+}
+
+// storkSchedulerConfig is the part of the scheduler Policy and KubeSchedulerConfiguration that
+// holds the scheduler extenders. Both forms keep the extenders at the top level.
+type storkSchedulerConfig struct {
+	Kind      string `json:"kind"`
+	Extenders []struct {
+		URLPrefix  string `json:"urlPrefix"`
+		FilterVerb string `json:"filterVerb"`
+	} `json:"extenders"`
+}
+
+// ValidateStorkSchedulerConfig validates that the stork-config config map configures the Stork
+// scheduler extender. Both the scheduler policy (policy.cfg) used by older Kubernetes versions
+// and the KubeSchedulerConfiguration (stork-config.yaml) used by newer ones are supported.
+func ValidateStorkSchedulerConfig(namespace string) error {
+	configMap, err := coreops.Instance().GetConfigMap("stork-config", namespace)
+	if err != nil {
+		return fmt.Errorf("failed to get config map %s/stork-config, Err: %v", namespace, err)
+	}
+
+	var key, expectedKind string
+	if _, ok := configMap.Data["policy.cfg"]; ok {
+		key, expectedKind = "policy.cfg", "Policy"
+	} else if _, ok := configMap.Data["stork-config.yaml"]; ok {
+		key, expectedKind = "stork-config.yaml", "KubeSchedulerConfiguration"
+	} else {
+		return fmt.Errorf("missing policy.cfg or stork-config.yaml in config map %s/%s", configMap.Namespace, configMap.Name)
+	}
+
+	if err := validateStorkSchedulerExtender(configMap.Data[key], expectedKind, namespace); err != nil {
+		return fmt.Errorf("failed to validate %s in config map %s/%s, Err: %v", key, configMap.Namespace, configMap.Name, err)
+	}
+	return nil
+}
+
+func validateStorkSchedulerExtender(config, expectedKind, namespace string) error {
+	schedulerConfig := &storkSchedulerConfig{}
+	if err := yaml.Unmarshal([]byte(config), schedulerConfig); err != nil {
+		return fmt.Errorf("failed to parse scheduler config, Err: %v", err)
+	}
+	if schedulerConfig.Kind != expectedKind {
+		return fmt.Errorf("kind: expected: %s, actual: %s", expectedKind, schedulerConfig.Kind)
+	}
+
+	expectedURLPrefix := fmt.Sprintf("http://stork-service.%s:8099", namespace)
+	for _, extender := range schedulerConfig.Extenders {
+		if extender.URLPrefix != expectedURLPrefix {
+			continue
+		}
+		if extender.FilterVerb != "filter" {
+			return fmt.Errorf("stork extender filterVerb: expected: filter, actual: %s", extender.FilterVerb)
+		}
+		return nil
+	}
+	return fmt.Errorf("no extender found with urlPrefix %s", expectedURLPrefix)
+}
+
+func validateStorkResources(cluster *corev1.StorageCluster, storkDeployment *appsv1.Deployment, timeout, interval time.Duration) error {
+	logrus.Debug("Validate Stork resources")
+
+	expectedCPU := "0.1"
+	if cpu, ok := cluster.Annotations["operator.libopenstorage.org/stork-cpu"]; ok {
+		expectedCPU = cpu
+	}
+	expectedCPUQuantity, err := resource.ParseQuantity(expectedCPU)
+	if err != nil {
+		return fmt.Errorf("failed to parse Stork cpu %s, Err: %v", expectedCPU, err)
+	}
+
+	t := func() (interface{}, bool, error) {
+		pods, err := appops.Instance().GetDeploymentPods(storkDeployment)
+		if err != nil {
+			return nil, false, err
+		}
+
+		for _, pod := range pods {
+			if err := validateStorkPodResources(pod, expectedCPUQuantity); err != nil {
+				return nil, true, err
+			}
+			logrus.Debugf("Value for cpu request inside Stork pod [%s]: expected: %s", pod.Name, expectedCPUQuantity.String())
+		}
+		return nil, false, nil
+	}
+
+	if _, err := doRetryWithTimeout(t, timeout, interval); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func validateStorkPodResources(pod v1.Pod, expectedCPUQuantity resource.Quantity) error {
+	for _, container := range pod.Spec.Containers {
+		if container.Name != "stork" {
+			continue
+		}
+		actualCPUQuantity := container.Resources.Requests[v1.ResourceCPU]
+		if actualCPUQuantity.Cmp(expectedCPUQuantity) != 0 {
+			return fmt.Errorf("failed to validate Stork resources inside Stork pod [%s]: expected cpu request: %s, actual: %s",
+				pod.Name, expectedCPUQuantity.String(), actualCPUQuantity.String())
+		}
+		return nil
+	}
+	return fmt.Errorf("failed to find stork container in Stork pod [%s]", pod.Name)
+}
+
+func validateStorkTolerations(cluster *corev1.StorageCluster, storkDeployment *appsv1.Deployment, timeout, interval time.Duration) error {
+	logrus.Debug("Validate Stork tolerations")
+
+	var expectedTolerations []v1.Toleration
+	if cluster.Spec.Placement != nil {
+		expectedTolerations = cluster.Spec.Placement.Tolerations
+	}
+
+	t := func() (interface{}, bool, error) {
+		pods, err := appops.Instance().GetDeploymentPods(storkDeployment)
+		if err != nil {
+			return nil, false, err
+		}
+
+		for _, pod := range pods {
+			if err := validateStorkPodTolerations(pod, expectedTolerations); err != nil {
+				return nil, true, err
+			}
+			logrus.Debugf("Found expected tolerations inside Stork pod [%s]: %+v", pod.Name, expectedTolerations)
+		}
+		return nil, false, nil
+	}
+
+	if _, err := doRetryWithTimeout(t, timeout, interval); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+// validateStorkPodTolerations checks that the pod has all the expected tolerations. Kubernetes
+// adds default tolerations to the pods, so the pod is allowed to have other tolerations too.
+func validateStorkPodTolerations(pod v1.Pod, expectedTolerations []v1.Toleration) error {
+	for _, expectedToleration := range expectedTolerations {
+		tolerationFound := false
+		for _, toleration := range pod.Spec.Tolerations {
+			if reflect.DeepEqual(toleration, expectedToleration) {
+				tolerationFound = true
+				break
+			}
+		}
+		if !tolerationFound {
+			return fmt.Errorf("failed to validate Stork tolerations inside Stork pod [%s]: expected toleration: %+v, actual: %+v",
+				pod.Name, expectedToleration, pod.Spec.Tolerations)
+		}
+	}
+	return nil
+}
+
 func validateStorkNamespaceEnvVar(namespace string, storkDeployment *appsv1.Deployment, timeout, interval time.Duration) error {
 	logrus.Debug("Validate Stork STORK-NAMESPACE env")
 
@@ -1573,18 +4331,69 @@ func validateStorkNamespaceEnvVar(namespace string, storkDeployment *appsv1.Depl
 		return nil, false, nil
 	}
 
-	if _, err := task.DoRetryWithTimeout(t, timeout, interval); err != nil {
+	if _, err := doRetryWithTimeout(t, timeout, interval); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// isCSIEnabled returns true if CSI is enabled in the StorageCluster. The spec.csi field takes
+// precedence, and the legacy CSI feature gate is used only when spec.csi is not set.
+func isCSIEnabled(cluster *corev1.StorageCluster) bool {
+	if cluster.Spec.CSI != nil {
+		return cluster.Spec.CSI.Enabled
+	}
+	enabled, err := getFeatureGate(cluster, "CSI")
+	return err == nil && enabled
+}
+
+// ValidateFeatureGate validates that the given feature gate of the StorageCluster is parsed as
+// expected, then runs the validators of the components driven by the gate. The validators are
+// expected to check the components are running if the gate is enabled, or terminated otherwise.
+func ValidateFeatureGate(cluster *corev1.StorageCluster, gate string, expectEnabled bool, validators ...func() error) error {
+	enabled, err := getFeatureGate(cluster, gate)
+	if err != nil {
 		return err
 	}
+	if enabled != expectEnabled {
+		return fmt.Errorf("failed to validate feature gate %s, expected enabled: %v, actual: %v",
+			gate, expectEnabled, enabled)
+	}
 
+	for _, validate := range validators {
+		if err := validate(); err != nil {
+			return fmt.Errorf("failed to validate components of feature gate %s (enabled: %v), Err: %w",
+				gate, enabled, err)
+		}
+	}
 	return nil
 }
 
-func validateCSI(pxImageList map[string]string, cluster *corev1.StorageCluster, timeout, interval time.Duration) error {
-	csi := cluster.Spec.CSI.Enabled
+// getFeatureGate returns the value of the given feature gate of the StorageCluster. A gate
+// that is not set is disabled.
+func getFeatureGate(cluster *corev1.StorageCluster, gate string) (bool, error) {
+	value, ok := cluster.Spec.FeatureGates[gate]
+	if !ok {
+		return false, nil
+	}
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse feature gate %s value %q, Err: %v", gate, value, err)
+	}
+	return enabled, nil
+}
+
+// validateCSI validates the CSI components of the given StorageCluster. The px-csi-ext deployment
+// is expected in csiNamespace, or in the cluster namespace if it is empty.
+func validateCSI(pxImageList map[string]string, cluster *corev1.StorageCluster, csiNamespace string, timeout, interval time.Duration) error {
+	if csiNamespace == "" {
+		csiNamespace = cluster.Namespace
+	}
+	csi := isCSIEnabled(cluster)
 	pxCsiDp := &appsv1.Deployment{}
 	pxCsiDp.Name = "px-csi-ext"
-	pxCsiDp.Namespace = cluster.Namespace
+	pxCsiDp.Namespace = csiNamespace
 
 	if csi {
 		logrus.Debug("CSI is enabled in StorageCluster")
@@ -1603,7 +4412,12 @@ func validateCSI(pxImageList map[string]string, cluster *corev1.StorageCluster,
 		}
 
 		// Validate CSI container images inside px-csi-ext pods
-		if err := validateCsiExtImages(cluster, pxImageList); err != nil {
+		if err := validateCsiExtImages(cluster, csiNamespace, pxImageList); err != nil {
+			return err
+		}
+
+		// Validate CSI snapshot controller and snapshot CRDs
+		if err := validateCSISnapshotController(cluster, pxImageList); err != nil {
 			return err
 		}
 
@@ -1613,7 +4427,11 @@ func validateCSI(pxImageList map[string]string, cluster *corev1.StorageCluster,
 		}
 
 		// Validate CSI topology specs
-		if err := validateCSITopologySpecs(cluster.Namespace, cluster.Spec.CSI.Topology, timeout, interval); err != nil {
+		var topologySpec *corev1.CSITopologySpec
+		if cluster.Spec.CSI != nil {
+			topologySpec = cluster.Spec.CSI.Topology
+		}
+		if err := validateCSITopologySpecs(csiNamespace, topologySpec, timeout, interval); err != nil {
 			return err
 		}
 	} else {
@@ -1630,6 +4448,102 @@ func validateCSI(pxImageList map[string]string, cluster *corev1.StorageCluster,
 	return nil
 }
 
+// ValidateCSIDriver validates that the portworx CSIDriver object is registered and that its
+// spec matches what the operator creates for the given StorageCluster and Kubernetes version.
+// Only the GA CSIDriver, used by the operator on Kubernetes 1.18 and up, is validated.
+func ValidateCSIDriver(k8sClient client.Client, cluster *corev1.StorageCluster, k8sVersion *version.Version) error {
+	expected := expectedCSIDriver(cluster, k8sVersion)
+	csiDriver := &storagev1.CSIDriver{}
+	if err := Get(k8sClient, csiDriver, expected.Name, ""); err != nil {
+		return fmt.Errorf("failed to get CSIDriver %s: %v", expected.Name, err)
+	}
+
+	var mismatches []string
+	if !reflect.DeepEqual(csiDriver.Spec.AttachRequired, expected.Spec.AttachRequired) {
+		mismatches = append(mismatches, fmt.Sprintf("attachRequired: expected: %v, actual: %v",
+			boolValue(expected.Spec.AttachRequired), boolValue(csiDriver.Spec.AttachRequired)))
+	}
+	if !reflect.DeepEqual(csiDriver.Spec.PodInfoOnMount, expected.Spec.PodInfoOnMount) {
+		mismatches = append(mismatches, fmt.Sprintf("podInfoOnMount: expected: %v, actual: %v",
+			boolValue(expected.Spec.PodInfoOnMount), boolValue(csiDriver.Spec.PodInfoOnMount)))
+	}
+	if !reflect.DeepEqual(csiDriver.Spec.VolumeLifecycleModes, expected.Spec.VolumeLifecycleModes) {
+		mismatches = append(mismatches, fmt.Sprintf("volumeLifecycleModes: expected: %v, actual: %v",
+			expected.Spec.VolumeLifecycleModes, csiDriver.Spec.VolumeLifecycleModes))
+	}
+	if !reflect.DeepEqual(csiDriver.Spec.FSGroupPolicy, expected.Spec.FSGroupPolicy) {
+		mismatches = append(mismatches, fmt.Sprintf("fsGroupPolicy: expected: %v, actual: %v",
+			fsGroupPolicyValue(expected.Spec.FSGroupPolicy), fsGroupPolicyValue(csiDriver.Spec.FSGroupPolicy)))
+	}
+	if len(mismatches) > 0 {
+		return fmt.Errorf("failed to validate CSIDriver %s: %s", csiDriver.Name, strings.Join(mismatches, ", "))
+	}
+	return nil
+}
+
+// expectedCSIDriver returns the CSIDriver the operator is expected to create. The Portworx
+// version is taken from the StorageCluster image, and the latest version is assumed when
+// the image tag cannot be parsed.
+func expectedCSIDriver(cluster *corev1.StorageCluster, k8sVersion *version.Version) *storagev1.CSIDriver {
+	pxVer2_2, _ := version.NewVersion("2.2")
+	pxVer2_5, _ := version.NewVersion("2.5")
+	k8sVer1_16, _ := version.NewVersion("1.16")
+	k8sVer1_20, _ := version.NewVersion("1.20")
+	pxVersion, _ := version.NewVersion(getImageTag(cluster.Spec.Image))
+
+	driverName := "pxd.portworx.com"
+	useDeprecatedDriverName := false
+	for _, env := range cluster.Spec.Env {
+		if env.Name == "PORTWORX_USEDEPRECATED_CSIDRIVERNAME" {
+			useDeprecatedDriverName, _ = strconv.ParseBool(env.Value)
+			break
+		}
+	}
+	if useDeprecatedDriverName || (pxVersion != nil && pxVersion.LessThan(pxVer2_2)) {
+		driverName = "com.openstorage.pxd"
+	}
+
+	attachRequired := false
+	podInfoOnMount := true
+	csiDriver := &storagev1.CSIDriver{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: driverName,
+		},
+		Spec: storagev1.CSIDriverSpec{
+			AttachRequired:       &attachRequired,
+			PodInfoOnMount:       &podInfoOnMount,
+			VolumeLifecycleModes: []storagev1.VolumeLifecycleMode{storagev1.VolumeLifecyclePersistent},
+		},
+	}
+
+	// Ephemeral volumes are only added when CSI is enabled, as a basic CSI
+	// configuration is used otherwise
+	if isCSIEnabled(cluster) &&
+		(pxVersion == nil || pxVersion.GreaterThanOrEqual(pxVer2_5)) &&
+		k8sVersion.GreaterThanOrEqual(k8sVer1_16) {
+		csiDriver.Spec.VolumeLifecycleModes = append(csiDriver.Spec.VolumeLifecycleModes, storagev1.VolumeLifecycleEphemeral)
+	}
+	if k8sVersion.GreaterThanOrEqual(k8sVer1_20) {
+		fsGroupPolicy := storagev1.FileFSGroupPolicy
+		csiDriver.Spec.FSGroupPolicy = &fsGroupPolicy
+	}
+	return csiDriver
+}
+
+func boolValue(b *bool) string {
+	if b == nil {
+		return "nil"
+	}
+	return strconv.FormatBool(*b)
+}
+
+func fsGroupPolicyValue(policy *storagev1.FSGroupPolicy) string {
+	if policy == nil {
+		return "nil"
+	}
+	return string(*policy)
+}
+
 func validateCsiContainerInPxPods(namespace string, csi bool, timeout, interval time.Duration) error {
 	logrus.Debug("Validating CSI container inside Portworx OCI Monitor pods")
 	listOptions := map[string]string{"name": "portworx"}
@@ -1682,7 +4596,7 @@ func validateCsiContainerInPxPods(namespace string, csi bool, timeout, interval
 		return nil, false, nil
 	}
 
-	if _, err := task.DoRetryWithTimeout(t, timeout, interval); err != nil {
+	if _, err := doRetryWithTimeout(t, timeout, interval); err != nil {
 		return err
 	}
 
@@ -1740,7 +4654,7 @@ func validatePvcControllerPorts(annotations map[string]string, pvcControllerDepl
 		return nil, false, nil
 	}
 
-	if _, err := task.DoRetryWithTimeout(t, timeout, interval); err != nil {
+	if _, err := doRetryWithTimeout(t, timeout, interval); err != nil {
 		return err
 	}
 
@@ -1826,7 +4740,7 @@ func validateCSITopologySpecs(namespace string, topologySpec *corev1.CSITopology
 		return nil, false, nil
 	}
 
-	if _, err := task.DoRetryWithTimeout(t, timeout, interval); err != nil {
+	if _, err := doRetryWithTimeout(t, timeout, interval); err != nil {
 		return err
 	}
 	return nil
@@ -1844,47 +4758,147 @@ func validateCSITopologyFeatureGate(pod v1.Pod, topologyEnabled bool) error {
 					}
 				}
 			}
-			if topologyEnabled && !featureGateEnabled {
-				return fmt.Errorf("csi topology is enabled but cannot find the enabled feature gate in container args")
+			if topologyEnabled && !featureGateEnabled {
+				return fmt.Errorf("csi topology is enabled but cannot find the enabled feature gate in container args")
+			}
+		}
+	}
+	return nil
+}
+
+// ExtractPxVersion returns the expected PX version for the given cluster. The version is
+// taken from the oci-monitor image tag in the image list, unless the tag contains an
+// underscore, in which case it is parsed from the PX_RELEASE_MANIFEST_URL env variable
+func ExtractPxVersion(pxImageList map[string]string, cluster *corev1.StorageCluster) (string, error) {
+	var pxVersion string
+
+	// Construct PX Version string used to match to deployed expected PX version
+	if strings.Contains(pxImageList["version"], "_") {
+		manifestURL := ""
+		for _, env := range cluster.Spec.Env {
+			if env.Name == PxReleaseManifestURLEnvVarName {
+				manifestURL = env.Value
+				break
+			}
+		}
+		if manifestURL == "" {
+			return "", fmt.Errorf("failed to get PX version from image %s, %s env var is not set",
+				pxImageList["version"], PxReleaseManifestURLEnvVarName)
+		}
+
+		u, err := url.Parse(manifestURL)
+		if err != nil || u.Host == "" || !strings.HasSuffix(u.Path, "/version") {
+			return "", fmt.Errorf("failed to get PX version, malformed %s env var value [%s]",
+				PxReleaseManifestURLEnvVarName, manifestURL)
+		}
+
+		// Looking for clear PX version before /version in the URL
+		ver := regexp.MustCompile(`\S+\/(\d.\S+)\/version`).FindStringSubmatch(manifestURL)
+		if len(ver) > 1 {
+			pxVersion = ver[1]
+		} else {
+			// If the above regex found nothing, assuming it was a master version URL
+			pxVersion = PxMasterVersion
+		}
+	} else {
+		ver := regexp.MustCompile(`:(\S+)`).FindStringSubmatch(pxImageList["version"])
+		if len(ver) < 2 {
+			return "", fmt.Errorf("failed to get PX version, malformed image [%s]", pxImageList["version"])
+		}
+		pxVersion = strings.TrimSpace(ver[1])
+	}
+
+	if pxVersion == "" {
+		return "", fmt.Errorf("failed to get PX version from image [%s]", pxImageList["version"])
+	}
+
+	return pxVersion, nil
+}
+
+// validateCSISnapshotController validates that the VolumeSnapshotClass CRD is registered and
+// that the snapshot controller is running when spec.csi.installSnapshotController is enabled.
+// The operator does not add its own snapshot controller if one was already installed in the
+// cluster, so a snapshot-controller container in any pod is accepted in that case.
+func validateCSISnapshotController(cluster *corev1.StorageCluster, pxImageList map[string]string) error {
+	if cluster.Spec.CSI == nil || cluster.Spec.CSI.InstallSnapshotController == nil ||
+		!*cluster.Spec.CSI.InstallSnapshotController {
+		logrus.Debug("CSI snapshot controller is not enabled in StorageCluster")
+		return nil
+	}
+
+	logrus.Debug("Validating CSI snapshot controller")
+	crdName := "volumesnapshotclasses.snapshot.storage.k8s.io"
+	if _, err := apiextensionsops.Instance().GetCRD(crdName, metav1.GetOptions{}); err != nil {
+		return fmt.Errorf("failed to get CRD %s: %v", crdName, err)
+	}
+
+	csiSnapshotControllerImage, ok := pxImageList["csiSnapshotController"]
+	if !ok {
+		return fmt.Errorf("failed to find image for csiSnapshotController")
+	}
+
+	deployment, err := appops.Instance().GetDeployment("px-csi-ext", cluster.Namespace)
+	if err != nil {
+		return err
+	}
+	pods, err := appops.Instance().GetDeploymentPods(deployment)
+	if err != nil {
+		return err
+	}
+
+	foundContainer := false
+	for _, pod := range pods {
+		for _, container := range pod.Spec.Containers {
+			if container.Name != "csi-snapshot-controller" {
+				continue
+			}
+			foundContainer = true
+			if container.Image != csiSnapshotControllerImage {
+				return fmt.Errorf("found container %s, expected image: %s, actual image: %s", container.Name, csiSnapshotControllerImage, container.Image)
 			}
 		}
 	}
-	return nil
-}
-
-func getPxVersion(pxImageList map[string]string, cluster *corev1.StorageCluster) string {
-	var pxVersion string
+	if foundContainer {
+		return nil
+	}
 
-	// Construct PX Version string used to match to deployed expected PX version
-	if strings.Contains(pxImageList["version"], "_") {
-		if cluster.Spec.Env != nil {
-			for _, env := range cluster.Spec.Env {
-				if env.Name == PxReleaseManifestURLEnvVarName {
-					// Looking for clear PX version before /version in the URL
-					ver := regexp.MustCompile(`\S+\/(\d.\S+)\/version`).FindStringSubmatch(env.Value)
-					if ver != nil {
-						pxVersion = ver[1]
-					} else {
-						// If the above regex found nothing, assuming it was a master version URL
-						pxVersion = PxMasterVersion
-					}
-					break
-				}
+	allPods, err := coreops.Instance().GetPods("", nil)
+	if err != nil {
+		return err
+	}
+	for _, pod := range allPods.Items {
+		for _, container := range pod.Spec.Containers {
+			if strings.Contains(container.Image, "/snapshot-controller:") {
+				logrus.Debugf("Found pre-installed CSI snapshot controller in pod %s/%s", pod.Namespace, pod.Name)
+				return nil
 			}
 		}
-	} else {
-		pxVersion = strings.TrimSpace(regexp.MustCompile(`:(\S+)`).FindStringSubmatch(pxImageList["version"])[1])
 	}
+	return fmt.Errorf("failed to find CSI snapshot controller, container csi-snapshot-controller is not running in px-csi-ext pods")
+}
 
-	if pxVersion == "" {
-		logrus.Error("failed to get PX version")
-		return ""
+// ValidateCSIExtReplicas validates that all the desired px-csi-ext replicas are ready. The operator
+// does not scale px-csi-ext with the cluster size, so the desired count is taken from the deployment
+// spec instead of being derived from the number of nodes.
+func ValidateCSIExtReplicas(cluster *corev1.StorageCluster, namespace string) error {
+	deployment, err := appops.Instance().GetDeployment("px-csi-ext", namespace)
+	if err != nil {
+		return fmt.Errorf("failed to get deployment %s/px-csi-ext, Err: %v", namespace, err)
 	}
 
-	return pxVersion
+	// Kubernetes defaults the replicas of a deployment to 1
+	desiredReplicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		desiredReplicas = *deployment.Spec.Replicas
+	}
+	if deployment.Status.ReadyReplicas != desiredReplicas {
+		return fmt.Errorf("failed to validate px-csi-ext replicas of StorageCluster %s/%s, expected ready replicas: %d, actual: %d",
+			cluster.Namespace, cluster.Name, desiredReplicas, deployment.Status.ReadyReplicas)
+	}
+	return nil
 }
 
-func validateCsiExtImages(cluster *corev1.StorageCluster, pxImageList map[string]string) error {
+func validateCsiExtImages(cluster *corev1.StorageCluster, namespace string, pxImageList map[string]string) error {
 	var csiProvisionerImage string
 	var csiSnapshotterImage string
 	var csiResizerImage string
@@ -1892,7 +4906,7 @@ func validateCsiExtImages(cluster *corev1.StorageCluster, pxImageList map[string
 
 	logrus.Debug("Validating CSI container images inside px-csi-ext pods")
 
-	deployment, err := appops.Instance().GetDeployment("px-csi-ext", cluster.Namespace)
+	deployment, err := appops.Instance().GetDeployment("px-csi-ext", namespace)
 	if err != nil {
 		return err
 	}
@@ -1922,7 +4936,14 @@ func validateCsiExtImages(cluster *corev1.StorageCluster, pxImageList map[string
 	}
 
 	pxVer2_10, _ := version.NewVersion("2.10")
-	pxVersion, _ := version.NewVersion(getPxVersion(pxImageList, cluster))
+	pxVersionStr, err := ExtractPxVersion(pxImageList, cluster)
+	if err != nil {
+		return err
+	}
+	pxVersion, err := version.NewVersion(pxVersionStr)
+	if err != nil {
+		return fmt.Errorf("failed to parse PX version %s, Err: %v", pxVersionStr, err)
+	}
 	if pxVersion.GreaterThanOrEqual(pxVer2_10) {
 		if value, ok := pxImageList["csiHealthMonitorController"]; ok {
 			csiHealthMonitorControllerImage = value
@@ -1956,26 +4977,50 @@ func validateCsiExtImages(cluster *corev1.StorageCluster, pxImageList map[string
 	return nil
 }
 
-func validateImageOnPods(image, namespace string, listOptions map[string]string) error {
+// validateImageOnPods validates that every pod matching the given labels runs the given image
+// in one of its containers or init containers. If a container name is passed, only the
+// container (or init container) with that name is expected to carry the image.
+func validateImageOnPods(image, namespace string, listOptions map[string]string, containerName ...string) error {
 	pods, err := coreops.Instance().GetPods(namespace, listOptions)
 	if err != nil {
 		return err
 	}
+	name := ""
+	if len(containerName) > 0 {
+		name = containerName[0]
+	}
 	for _, pod := range pods.Items {
-		foundImage := false
-		for _, container := range pod.Spec.Containers {
+		if err := validateImageOnPod(pod, image, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateImageOnPod(pod v1.Pod, image, containerName string) error {
+	containers := append([]v1.Container{}, pod.Spec.InitContainers...)
+	containers = append(containers, pod.Spec.Containers...)
+
+	if containerName == "" {
+		for _, container := range containers {
 			if container.Image == image {
-				foundImage = true
-				break
+				return nil
 			}
 		}
+		return &ErrImageMismatch{Pod: pod.Name, Expected: image}
+	}
 
-		if !foundImage {
-			return fmt.Errorf("failed to validate image %s on pod: %v",
-				image, pod)
+	for _, container := range containers {
+		if container.Name != containerName {
+			continue
+		}
+		if container.Image != image {
+			return &ErrImageMismatch{Pod: pod.Name, Container: containerName, Expected: image, Actual: container.Image}
 		}
+		return nil
 	}
-	return nil
+	return fmt.Errorf("failed to validate image %s on pod %s, expected container %s was not found",
+		image, pod.Name, containerName)
 }
 
 func validateImageTag(tag, namespace string, listOptions map[string]string) error {
@@ -1985,11 +5030,7 @@ func validateImageTag(tag, namespace string, listOptions map[string]string) erro
 	}
 	for _, pod := range pods.Items {
 		for _, container := range pod.Spec.Containers {
-			imageSplit := strings.Split(container.Image, ":")
-			imageTag := ""
-			if len(imageSplit) == 2 {
-				imageTag = imageSplit[1]
-			}
+			imageTag := getImageTag(container.Image)
 			if imageTag != tag {
 				return fmt.Errorf("failed to validate image tag on pod %s container %s, Expected: %s Got: %s",
 					pod.Name, container.Name, tag, imageTag)
@@ -1999,6 +5040,21 @@ func validateImageTag(tag, namespace string, listOptions map[string]string) erro
 	return nil
 }
 
+// getImageTag returns the tag of the given image reference. A colon in the registry host,
+// such as registry.local:5000/px/stork:2.9, is not treated as a tag separator. For
+// references pinned only by digest (repo@sha256:...), the digest is returned instead.
+func getImageTag(image string) string {
+	name, digest := image, ""
+	if i := strings.Index(image, "@"); i >= 0 {
+		name, digest = image[:i], image[i+1:]
+	}
+	lastSegment := name[strings.LastIndex(name, "/")+1:]
+	if i := strings.LastIndex(lastSegment, ":"); i >= 0 {
+		return lastSegment[i+1:]
+	}
+	return digest
+}
+
 // ValidateSecurity validates all PX Security components
 func ValidateSecurity(cluster *corev1.StorageCluster, previouslyEnabled bool, timeout, interval time.Duration) error {
 	if cluster.Spec.Security != nil &&
@@ -2050,7 +5106,7 @@ func ValidateSecurityEnabled(cluster *corev1.StorageCluster, timeout, interval t
 		return "", false, nil
 	}
 
-	if _, err := task.DoRetryWithTimeout(t, timeout, interval); err != nil {
+	if _, err := doRetryWithTimeout(t, timeout, interval); err != nil {
 		return err
 	}
 
@@ -2111,13 +5167,269 @@ func ValidateSecurityDisabled(cluster *corev1.StorageCluster, previouslyEnabled
 		return "", false, nil
 	}
 
-	if _, err := task.DoRetryWithTimeout(t, timeout, interval); err != nil {
+	if _, err := doRetryWithTimeout(t, timeout, interval); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+// isAuthEnabled returns true if PX Security auth is enabled in the StorageCluster. Auth is enabled
+// along with security, unless it is explicitly disabled in spec.security.auth.
+func isAuthEnabled(cluster *corev1.StorageCluster) bool {
+	if cluster.Spec.Security == nil || !cluster.Spec.Security.Enabled {
+		return false
+	}
+	if cluster.Spec.Security.Auth != nil && cluster.Spec.Security.Auth.Enabled != nil {
+		return *cluster.Spec.Security.Auth.Enabled
+	}
+	return true
+}
+
+// getSharedSecretName returns the name of the secret holding the PX Security shared secret,
+// taken from spec.security.auth.selfSigned.sharedSecret and defaulting to px-shared-secret
+func getSharedSecretName(cluster *corev1.StorageCluster) string {
+	if cluster.Spec.Security != nil && cluster.Spec.Security.Auth != nil &&
+		cluster.Spec.Security.Auth.SelfSigned != nil &&
+		cluster.Spec.Security.Auth.SelfSigned.SharedSecret != nil &&
+		*cluster.Spec.Security.Auth.SelfSigned.SharedSecret != "" {
+		return *cluster.Spec.Security.Auth.SelfSigned.SharedSecret
+	}
+	return "px-shared-secret"
+}
+
+// GenerateAuthToken signs a token for the given claims with the PX Security shared secret of
+// the cluster, so it can be used to call the SDK server when auth is enabled. Unset issuer,
+// subject and email claims are filled in the same way as the tokens minted by the operator,
+// and the token expires after the token lifetime in the spec, or 24h by default.
+func GenerateAuthToken(cluster *corev1.StorageCluster, claims *auth.Claims) (string, error) {
+	if !isAuthEnabled(cluster) {
+		return "", fmt.Errorf("PX Security auth is not enabled in StorageCluster %s/%s", cluster.Namespace, cluster.Name)
+	}
+
+	sharedSecretName := getSharedSecretName(cluster)
+	secret, err := coreops.Instance().GetSecret(sharedSecretName, cluster.Namespace)
+	if err != nil {
+		return "", fmt.Errorf("failed to get secret %s/%s, Err: %v", cluster.Namespace, sharedSecretName, err)
+	}
+	sharedSecret := string(secret.Data["shared-secret"])
+	if sharedSecret == "" {
+		return "", fmt.Errorf("secret %s/%s has an empty or missing key shared-secret", cluster.Namespace, sharedSecretName)
+	}
+
+	issuer := "operator.portworx.io"
+	lifetime := 24 * time.Hour
+	if authSpec := cluster.Spec.Security.Auth; authSpec != nil && authSpec.SelfSigned != nil {
+		if authSpec.SelfSigned.Issuer != nil && *authSpec.SelfSigned.Issuer != "" {
+			issuer = *authSpec.SelfSigned.Issuer
+		}
+		if authSpec.SelfSigned.TokenLifetime != nil && *authSpec.SelfSigned.TokenLifetime != "" {
+			if lifetime, err = auth.ParseToDuration(*authSpec.SelfSigned.TokenLifetime); err != nil {
+				return "", fmt.Errorf("failed to parse token lifetime, Err: %v", err)
+			}
+		}
+	}
+
+	tokenClaims := *claims
+	if tokenClaims.Issuer == "" {
+		tokenClaims.Issuer = issuer
+	}
+	if tokenClaims.Subject == "" {
+		tokenClaims.Subject = fmt.Sprintf("%s@%s", tokenClaims.Name, tokenClaims.Issuer)
+	}
+	if tokenClaims.Email == "" {
+		tokenClaims.Email = fmt.Sprintf("%s@%s", tokenClaims.Name, tokenClaims.Issuer)
+	}
+
+	signature, err := auth.NewSignatureSharedSecret(sharedSecret)
+	if err != nil {
+		return "", fmt.Errorf("failed to create token signature, Err: %v", err)
+	}
+	token, err := auth.Token(&tokenClaims, signature, &auth.Options{
+		Expiration: time.Now().Add(lifetime).Unix(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to generate token, Err: %v", err)
+	}
+	return token, nil
+}
+
+// ValidateSecuritySecrets validates that, when PX Security auth is enabled, the shared secret
+// and the system secrets exist in the cluster namespace with non-empty keys
+func ValidateSecuritySecrets(cluster *corev1.StorageCluster) error {
+	if !isAuthEnabled(cluster) {
+		return fmt.Errorf("PX Security auth is not enabled in StorageCluster %s/%s", cluster.Namespace, cluster.Name)
+	}
+
+	expectedSecrets := []struct {
+		name string
+		key  string
+	}{
+		{getSharedSecretName(cluster), "shared-secret"},
+		{"px-system-secrets", "system-secret"},
+	}
+	for _, expected := range expectedSecrets {
+		secret, err := coreops.Instance().GetSecret(expected.name, cluster.Namespace)
+		if err != nil {
+			return fmt.Errorf("failed to find secret %s/%s, Err: %v", cluster.Namespace, expected.name, err)
+		}
+		if len(secret.Data[expected.key]) == 0 && secret.StringData[expected.key] == "" {
+			return fmt.Errorf("secret %s/%s has an empty or missing key %s", cluster.Namespace, expected.name, expected.key)
+		}
+	}
+	return nil
+}
+
+// isTLSEnabled returns true if TLS is enabled in the StorageCluster. TLS is disabled by default
+// and is enabled only if both spec.security.enabled and spec.security.tls.enabled are true.
+func isTLSEnabled(cluster *corev1.StorageCluster) bool {
+	return cluster.Spec.Security != nil && cluster.Spec.Security.Enabled &&
+		cluster.Spec.Security.TLS != nil && cluster.Spec.Security.TLS.Enabled != nil &&
+		*cluster.Spec.Security.TLS.Enabled
+}
+
+// loadCertLocation reads the PEM data referenced by a TLS cert location. Like the operator, the
+// secret reference takes precedence over the file name. The secret is read from the cluster namespace,
+// while the file name is resolved on the local filesystem of the test runner, not on the Portworx nodes.
+func loadCertLocation(cluster *corev1.StorageCluster, location *corev1.CertLocation) ([]byte, error) {
+	if location.SecretRef != nil && location.SecretRef.SecretName != "" && location.SecretRef.SecretKey != "" {
+		secretName, secretKey := location.SecretRef.SecretName, location.SecretRef.SecretKey
+		secret, err := coreops.Instance().GetSecret(secretName, cluster.Namespace)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get secret %s/%s, Err: %v", cluster.Namespace, secretName, err)
+		}
+		data := secret.Data[secretKey]
+		if len(data) == 0 {
+			data = []byte(secret.StringData[secretKey])
+		}
+		if len(data) == 0 {
+			return nil, fmt.Errorf("secret %s/%s has an empty or missing key %s", cluster.Namespace, secretName, secretKey)
+		}
+		return data, nil
+	}
+	if location.FileName != nil && *location.FileName != "" {
+		data, err := ioutil.ReadFile(*location.FileName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read local file %s, Err: %v", *location.FileName, err)
+		}
+		return data, nil
+	}
+	return nil, fmt.Errorf("neither a secret reference nor a file name is set")
+}
+
+// parseCertificates parses all the PEM encoded certificates in the given data
+func parseCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate, Err: %v", err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no PEM encoded certificate found")
+	}
+	return certs, nil
+}
+
+// validateCertificatesTime returns an error if any of the given certificates is expired or not yet valid
+func validateCertificatesTime(name string, certs []*x509.Certificate, now time.Time) error {
+	for _, cert := range certs {
+		logrus.Debugf("%s certificate %q is valid from %v until %v", name, cert.Subject.CommonName, cert.NotBefore, cert.NotAfter)
+		if now.After(cert.NotAfter) {
+			return fmt.Errorf("%s certificate %q expired on %v", name, cert.Subject.CommonName, cert.NotAfter)
+		}
+		if now.Before(cert.NotBefore) {
+			return fmt.Errorf("%s certificate %q is not valid until %v", name, cert.Subject.CommonName, cert.NotBefore)
+		}
+	}
+	return nil
+}
+
+// ValidateTLSCerts validates that, when TLS is enabled, the root CA, server certificate and server key
+// configured in spec.security.tls can be loaded, either from a secret or from a local file, and are usable.
+// The certificates must parse and be within their validity period, the server key must match the
+// server certificate and, if a root CA is configured, the server certificate must chain up to it.
+// All problems found are reported together in the returned error.
+func ValidateTLSCerts(cluster *corev1.StorageCluster) error {
+	if !isTLSEnabled(cluster) {
+		return fmt.Errorf("TLS is not enabled in StorageCluster %s/%s", cluster.Namespace, cluster.Name)
+	}
+
+	tlsSpec := cluster.Spec.Security.TLS
+	if tlsSpec.ServerCert == nil {
+		return fmt.Errorf("server certificate is not configured in StorageCluster %s/%s", cluster.Namespace, cluster.Name)
+	}
+	if tlsSpec.ServerKey == nil {
+		return fmt.Errorf("server key is not configured in StorageCluster %s/%s", cluster.Namespace, cluster.Name)
+	}
+
+	var certErrors []string
+	now := time.Now()
+
+	var rootCAs *x509.CertPool
+	if tlsSpec.RootCA != nil {
+		if caData, err := loadCertLocation(cluster, tlsSpec.RootCA); err != nil {
+			certErrors = append(certErrors, fmt.Sprintf("failed to load root CA: %v", err))
+		} else if caCerts, err := parseCertificates(caData); err != nil {
+			certErrors = append(certErrors, fmt.Sprintf("invalid root CA: %v", err))
+		} else {
+			if err := validateCertificatesTime("root CA", caCerts, now); err != nil {
+				certErrors = append(certErrors, err.Error())
+			}
+			rootCAs = x509.NewCertPool()
+			for _, caCert := range caCerts {
+				rootCAs.AddCert(caCert)
+			}
+		}
+	}
+
+	certData, err := loadCertLocation(cluster, tlsSpec.ServerCert)
+	if err != nil {
+		certErrors = append(certErrors, fmt.Sprintf("failed to load server certificate: %v", err))
+	}
+	keyData, err := loadCertLocation(cluster, tlsSpec.ServerKey)
+	if err != nil {
+		certErrors = append(certErrors, fmt.Sprintf("failed to load server key: %v", err))
+	}
+
+	if len(certData) > 0 {
+		if serverCerts, err := parseCertificates(certData); err != nil {
+			certErrors = append(certErrors, fmt.Sprintf("invalid server certificate: %v", err))
+		} else {
+			if err := validateCertificatesTime("server", serverCerts, now); err != nil {
+				certErrors = append(certErrors, err.Error())
+			} else if rootCAs != nil {
+				intermediates := x509.NewCertPool()
+				for _, cert := range serverCerts[1:] {
+					intermediates.AddCert(cert)
+				}
+				if _, err := serverCerts[0].Verify(x509.VerifyOptions{
+					Roots:         rootCAs,
+					Intermediates: intermediates,
+					CurrentTime:   now,
+				}); err != nil {
+					certErrors = append(certErrors, fmt.Sprintf("server certificate is not signed by the root CA: %v", err))
+				}
+			}
+			if len(keyData) > 0 {
+				if _, err := tls.X509KeyPair(certData, keyData); err != nil {
+					certErrors = append(certErrors, fmt.Sprintf("server key does not match the server certificate: %v", err))
+				}
+			}
+		}
+	}
+
+	if len(certErrors) > 0 {
+		return fmt.Errorf("failed to validate TLS certificates: %s", strings.Join(certErrors, ", "))
+	}
+	return nil
+}
+
 func validateStorkSecurityEnvVar(cluster *corev1.StorageCluster, storkDeployment *appsv1.Deployment, timeout, interval time.Duration) error {
 	logrus.Debug("Validate Stork Security ENV vars")
 	var securityEnabled bool
@@ -2189,7 +5501,7 @@ func validateStorkSecurityEnvVar(cluster *corev1.StorageCluster, storkDeployment
 		return nil, false, nil
 	}
 
-	if _, err := task.DoRetryWithTimeout(t, timeout, interval); err != nil {
+	if _, err := doRetryWithTimeout(t, timeout, interval); err != nil {
 		return err
 	}
 
@@ -2206,108 +5518,285 @@ func ValidateMonitoring(pxImageList map[string]string, cluster *corev1.StorageCl
 		return err
 	}
 
-	if err := ValidateAlertManager(pxImageList, cluster, timeout, interval); err != nil {
-		return err
+	if err := ValidateAlertManager(pxImageList, cluster, timeout, interval); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ValidatePrometheus validates all Prometheus components
+func ValidatePrometheus(pxImageList map[string]string, cluster *corev1.StorageCluster, timeout, interval time.Duration) error {
+	if cluster.Spec.Monitoring != nil &&
+		((cluster.Spec.Monitoring.EnableMetrics != nil && *cluster.Spec.Monitoring.EnableMetrics) ||
+			(cluster.Spec.Monitoring.Prometheus != nil && cluster.Spec.Monitoring.Prometheus.ExportMetrics)) {
+		if cluster.Spec.Monitoring.Prometheus != nil && cluster.Spec.Monitoring.Prometheus.Enabled {
+			dep := appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "px-prometheus-operator",
+					Namespace: cluster.Namespace,
+				},
+			}
+			if err := appops.Instance().ValidateDeployment(&dep, timeout, interval); err != nil {
+				return err
+			}
+
+			st := appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "prometheus-px-prometheus",
+					Namespace: cluster.Namespace,
+				},
+			}
+			if err := appops.Instance().ValidateStatefulSet(&st, timeout); err != nil {
+				return err
+			}
+		}
+
+		t := func() (interface{}, bool, error) {
+			_, err := prometheusops.Instance().GetPrometheusRule("portworx", cluster.Namespace)
+			if err != nil {
+				return nil, true, err
+			}
+			return nil, false, nil
+		}
+		if _, err := doRetryWithTimeout(t, timeout, interval); err != nil {
+			return err
+		}
+
+		t = func() (interface{}, bool, error) {
+			// The operator does not configure TLS on the scrape endpoints, so the px-api
+			// endpoint is scraped over http even when security is enabled
+			if err := ValidateServiceMonitorEndpoints(cluster.Namespace, "px-api", "http"); err != nil {
+				return nil, true, err
+			}
+			return nil, false, nil
+		}
+		if _, err := doRetryWithTimeout(t, timeout, interval); err != nil {
+			return err
+		}
+
+		if cluster.Spec.Monitoring.Prometheus != nil && cluster.Spec.Monitoring.Prometheus.Enabled {
+			if err := ValidatePrometheusSelectsServiceMonitor(cluster.Namespace); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// ValidateServiceMonitorEndpoints validates that the portworx ServiceMonitor has an endpoint
+// for the given port name that is scraped with the given scheme. The scheme of an endpoint
+// defaults to http when it is not set. An https endpoint also needs a TLS config.
+func ValidateServiceMonitorEndpoints(namespace, expectedPort, expectedScheme string) error {
+	serviceMonitor, err := prometheusops.Instance().GetServiceMonitor("portworx", namespace)
+	if err != nil {
+		return fmt.Errorf("failed to get ServiceMonitor %s/portworx, Err: %v", namespace, err)
+	}
+	return validateServiceMonitorEndpoint(serviceMonitor, expectedPort, expectedScheme)
+}
+
+func validateServiceMonitorEndpoint(serviceMonitor *monitoringv1.ServiceMonitor, expectedPort, expectedScheme string) error {
+	if expectedScheme == "" {
+		expectedScheme = "http"
+	}
+
+	var ports []string
+	for _, endpoint := range serviceMonitor.Spec.Endpoints {
+		if endpoint.Port != expectedPort {
+			ports = append(ports, endpoint.Port)
+			continue
+		}
+		scheme := endpoint.Scheme
+		if scheme == "" {
+			scheme = "http"
+		}
+		if scheme != expectedScheme {
+			return fmt.Errorf("failed to validate ServiceMonitor %s/%s endpoint for port %s: expected scheme: %s, actual: %s",
+				serviceMonitor.Namespace, serviceMonitor.Name, expectedPort, expectedScheme, scheme)
+		}
+		if scheme == "https" && endpoint.TLSConfig == nil {
+			return fmt.Errorf("failed to validate ServiceMonitor %s/%s endpoint for port %s: https endpoint has no TLS config",
+				serviceMonitor.Namespace, serviceMonitor.Name, expectedPort)
+		}
+		return nil
+	}
+	return fmt.Errorf("failed to validate ServiceMonitor %s/%s: no endpoint for port %s, found ports %v",
+		serviceMonitor.Namespace, serviceMonitor.Name, expectedPort, ports)
+}
+
+// ValidatePrometheusSelectsServiceMonitor validates that the serviceMonitorSelector of the
+// px-prometheus instance matches the labels of the portworx ServiceMonitor, so that Prometheus
+// scrapes the Portworx metrics. A Prometheus instance without a selector selects nothing.
+func ValidatePrometheusSelectsServiceMonitor(namespace string) error {
+	prometheus, err := prometheusops.Instance().GetPrometheus("px-prometheus", namespace)
+	if err != nil {
+		return fmt.Errorf("failed to get Prometheus %s/px-prometheus, Err: %v", namespace, err)
+	}
+	serviceMonitor, err := prometheusops.Instance().GetServiceMonitor("portworx", namespace)
+	if err != nil {
+		return fmt.Errorf("failed to get ServiceMonitor %s/portworx, Err: %v", namespace, err)
+	}
+
+	if prometheus.Spec.ServiceMonitorSelector == nil {
+		return fmt.Errorf("Prometheus %s/%s has no serviceMonitorSelector, ServiceMonitor %s/%s is not selected",
+			prometheus.Namespace, prometheus.Name, serviceMonitor.Namespace, serviceMonitor.Name)
+	}
+	selector, err := metav1.LabelSelectorAsSelector(prometheus.Spec.ServiceMonitorSelector)
+	if err != nil {
+		return fmt.Errorf("failed to parse serviceMonitorSelector of Prometheus %s/%s, Err: %v",
+			prometheus.Namespace, prometheus.Name, err)
+	}
+	if !selector.Matches(labels.Set(serviceMonitor.Labels)) {
+		return fmt.Errorf("serviceMonitorSelector [%s] of Prometheus %s/%s does not match labels %v of ServiceMonitor %s/%s",
+			selector.String(), prometheus.Namespace, prometheus.Name, serviceMonitor.Labels, serviceMonitor.Namespace, serviceMonitor.Name)
+	}
+	return nil
+}
+
+// ValidatePrometheusConfig validates that the px-prometheus instance is configured as derived
+// from the monitoring spec of the cluster. The spec has no retention or storage settings, so the
+// operator always runs a single replica with the default retention and no persistent storage,
+// while remote write and alerting follow the Prometheus spec. The first mismatched field is reported.
+func ValidatePrometheusConfig(cluster *corev1.StorageCluster, namespace string) error {
+	if cluster.Spec.Monitoring == nil || cluster.Spec.Monitoring.Prometheus == nil ||
+		!cluster.Spec.Monitoring.Prometheus.Enabled {
+		return fmt.Errorf("Prometheus is not enabled in StorageCluster %s/%s", cluster.Namespace, cluster.Name)
+	}
+	prometheus, err := prometheusops.Instance().GetPrometheus("px-prometheus", namespace)
+	if err != nil {
+		return fmt.Errorf("failed to get Prometheus %s/px-prometheus, Err: %v", namespace, err)
+	}
+	return validatePrometheusConfig(cluster.Spec.Monitoring.Prometheus, prometheus)
+}
+
+func validatePrometheusConfig(spec *corev1.PrometheusSpec, prometheus *monitoringv1.Prometheus) error {
+	name := prometheus.Namespace + "/" + prometheus.Name
+	if prometheus.Spec.Replicas == nil || *prometheus.Spec.Replicas != 1 {
+		return fmt.Errorf("Prometheus %s has wrong replicas, expected: 1, actual: %v", name, int32PtrString(prometheus.Spec.Replicas))
+	}
+	if prometheus.Spec.Retention != "" {
+		return fmt.Errorf("Prometheus %s has wrong retention, expected the default retention, actual: %s",
+			name, prometheus.Spec.Retention)
+	}
+	if prometheus.Spec.Storage != nil {
+		return fmt.Errorf("Prometheus %s has unexpected storage %+v", name, *prometheus.Spec.Storage)
+	}
+
+	var remoteWriteURLs []string
+	for _, remoteWrite := range prometheus.Spec.RemoteWrite {
+		remoteWriteURLs = append(remoteWriteURLs, remoteWrite.URL)
+	}
+	var expectedRemoteWriteURLs []string
+	if spec.RemoteWriteEndpoint != "" {
+		expectedRemoteWriteURLs = []string{spec.RemoteWriteEndpoint + "/api/prom/push"}
+	}
+	if !reflect.DeepEqual(remoteWriteURLs, expectedRemoteWriteURLs) {
+		return fmt.Errorf("Prometheus %s has wrong remote write URLs, expected: %v, actual: %v",
+			name, expectedRemoteWriteURLs, remoteWriteURLs)
+	}
+
+	alertManagerEnabled := spec.AlertManager != nil && spec.AlertManager.Enabled
+	alertingConfigured := prometheus.Spec.Alerting != nil && len(prometheus.Spec.Alerting.Alertmanagers) > 0
+	if alertManagerEnabled != alertingConfigured {
+		return fmt.Errorf("Prometheus %s has wrong alerting, expected alertmanagers configured: %v, actual: %v",
+			name, alertManagerEnabled, alertingConfigured)
+	}
+	return nil
+}
+
+// int32PtrString returns the value of the given pointer as a string, or <nil> if it is not set
+func int32PtrString(value *int32) string {
+	if value == nil {
+		return "<nil>"
 	}
+	return strconv.Itoa(int(*value))
+}
 
+// ValidatePrometheusRuleContains validates that the portworx PrometheusRule contains the given
+// rules. A rule name matches a rule group name, or the alert or record name of a rule.
+func ValidatePrometheusRuleContains(namespace string, ruleNames []string) error {
+	prometheusRule, err := prometheusops.Instance().GetPrometheusRule("portworx", namespace)
+	if err != nil {
+		return fmt.Errorf("failed to get PrometheusRule %s/portworx, Err: %v", namespace, err)
+	}
+	if missingRules := getMissingPrometheusRules(prometheusRule, ruleNames); len(missingRules) > 0 {
+		return fmt.Errorf("PrometheusRule %s/%s is missing rules %v",
+			prometheusRule.Namespace, prometheusRule.Name, missingRules)
+	}
 	return nil
 }
 
-// ValidatePrometheus validates all Prometheus components
-func ValidatePrometheus(pxImageList map[string]string, cluster *corev1.StorageCluster, timeout, interval time.Duration) error {
-	if cluster.Spec.Monitoring != nil &&
-		((cluster.Spec.Monitoring.EnableMetrics != nil && *cluster.Spec.Monitoring.EnableMetrics) ||
-			(cluster.Spec.Monitoring.Prometheus != nil && cluster.Spec.Monitoring.Prometheus.ExportMetrics)) {
-		if cluster.Spec.Monitoring.Prometheus != nil && cluster.Spec.Monitoring.Prometheus.Enabled {
-			dep := appsv1.Deployment{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      "px-prometheus-operator",
-					Namespace: cluster.Namespace,
-				},
-			}
-			if err := appops.Instance().ValidateDeployment(&dep, timeout, interval); err != nil {
-				return err
-			}
-
-			st := appsv1.StatefulSet{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      "prometheus-px-prometheus",
-					Namespace: cluster.Namespace,
-				},
+// getMissingPrometheusRules returns the rule names that are not present in the given PrometheusRule
+func getMissingPrometheusRules(prometheusRule *monitoringv1.PrometheusRule, ruleNames []string) []string {
+	presentRules := make(map[string]bool)
+	for _, group := range prometheusRule.Spec.Groups {
+		presentRules[group.Name] = true
+		for _, rule := range group.Rules {
+			if rule.Alert != "" {
+				presentRules[rule.Alert] = true
 			}
-			if err := appops.Instance().ValidateStatefulSet(&st, timeout); err != nil {
-				return err
-			}
-		}
-
-		t := func() (interface{}, bool, error) {
-			_, err := prometheusops.Instance().GetPrometheusRule("portworx", cluster.Namespace)
-			if err != nil {
-				return nil, true, err
+			if rule.Record != "" {
+				presentRules[rule.Record] = true
 			}
-			return nil, false, nil
-		}
-		if _, err := task.DoRetryWithTimeout(t, timeout, interval); err != nil {
-			return err
 		}
+	}
 
-		t = func() (interface{}, bool, error) {
-			_, err := prometheusops.Instance().GetServiceMonitor("portworx", cluster.Namespace)
-			if err != nil {
-				return nil, true, err
-			}
-			return nil, false, nil
-		}
-		if _, err := task.DoRetryWithTimeout(t, timeout, interval); err != nil {
-			return err
+	var missingRules []string
+	for _, ruleName := range ruleNames {
+		if !presentRules[ruleName] {
+			missingRules = append(missingRules, ruleName)
 		}
 	}
-
-	return nil
+	return missingRules
 }
 
 // ValidateTelemetryUninstalled validates telemetry component is uninstalled as expected
 func ValidateTelemetryUninstalled(pxImageList map[string]string, cluster *corev1.StorageCluster, timeout, interval time.Duration) error {
-	t := func() (interface{}, bool, error) {
+	getPresentResources := func() ([]string, error) {
+		var presentResources []string
 		_, err := appops.Instance().GetDeployment("px-metrics-collector", cluster.Namespace)
-		if !errors.IsNotFound(err) {
-			return "", true, fmt.Errorf("wait for deletion, err %v", err)
-		}
-
-		_, err = rbacops.Instance().GetRole("px-metrics-collector", cluster.Name)
-		if !errors.IsNotFound(err) {
-			return "", true, fmt.Errorf("wait for deletion, err %v", err)
+		if err == nil {
+			presentResources = append(presentResources, fmt.Sprintf("Deployment %s/px-metrics-collector", cluster.Namespace))
+		} else if !errors.IsNotFound(err) {
+			return nil, err
 		}
-
-		_, err = rbacops.Instance().GetRoleBinding("px-metrics-collector", cluster.Name)
-		if !errors.IsNotFound(err) {
-			return "", true, fmt.Errorf("wait for deletion, err %v", err)
+		_, err = rbacops.Instance().GetRole("px-metrics-collector", cluster.Namespace)
+		if err == nil {
+			presentResources = append(presentResources, fmt.Sprintf("Role %s/px-metrics-collector", cluster.Namespace))
+		} else if !errors.IsNotFound(err) {
+			return nil, err
 		}
-
-		_, err = coreops.Instance().GetConfigMap("px-telemetry-config", cluster.Namespace)
-		if !errors.IsNotFound(err) {
-			return "", true, fmt.Errorf("wait for deletion, err %v", err)
+		_, err = rbacops.Instance().GetRoleBinding("px-metrics-collector", cluster.Namespace)
+		if err == nil {
+			presentResources = append(presentResources, fmt.Sprintf("RoleBinding %s/px-metrics-collector", cluster.Namespace))
+		} else if !errors.IsNotFound(err) {
+			return nil, err
 		}
+		return presentResources, nil
+	}
 
-		_, err = coreops.Instance().GetConfigMap("px-collector-config", cluster.Namespace)
-		if !errors.IsNotFound(err) {
-			return "", true, fmt.Errorf("wait for deletion, err %v", err)
+	t := func() (interface{}, bool, error) {
+		presentResources, err := getPresentResources()
+		if err != nil {
+			return "", true, err
 		}
-
-		_, err = coreops.Instance().GetConfigMap("px-collector-proxy-config", cluster.Namespace)
-		if !errors.IsNotFound(err) {
-			return "", true, fmt.Errorf("wait for deletion, err %v", err)
+		if len(presentResources) > 0 {
+			return "", true, fmt.Errorf("waiting for telemetry resources %s to be deleted", presentResources)
 		}
+		return "", false, nil
+	}
 
-		_, err = coreops.Instance().GetServiceAccount("px-metrics-collector", cluster.Namespace)
-		if !errors.IsNotFound(err) {
-			return "", true, fmt.Errorf("wait for deletion, err %v", err)
+	if _, err := doRetryWithTimeout(t, timeout, interval); err != nil {
+		if presentResources, _ := getPresentResources(); len(presentResources) > 0 {
+			return fmt.Errorf("failed to validate telemetry is disabled, still present: %s",
+				strings.Join(presentResources, ", "))
 		}
-
-		return "", false, nil
+		return err
 	}
 
-	if _, err := task.DoRetryWithTimeout(t, timeout, interval); err != nil {
+	// Config maps and service account of the metrics collector
+	if err := validateTelemetryResourcesDeleted(cluster, timeout, interval); err != nil {
 		return err
 	}
 
@@ -2433,107 +5922,549 @@ func ValidateAlertManagerDisabled(pxImageList map[string]string, cluster *corev1
 			return "", true, fmt.Errorf("wait for service alertmanager-operated deletion, err %v", err)
 		}
 
-		return "", false, nil
+		return "", false, nil
+	}
+
+	if _, err := doRetryWithTimeout(t, timeout, interval); err != nil {
+		return err
+	}
+
+	logrus.Infof("Alert manager components do not exist")
+	return nil
+}
+
+// ValidateTelemetryInstalled validates telemetry component is running as expected
+func ValidateTelemetryInstalled(pxImageList map[string]string, cluster *corev1.StorageCluster, timeout, interval time.Duration) error {
+	// Wait for the deployment to become online
+	dep := appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-metrics-collector",
+			Namespace: cluster.Namespace,
+		},
+	}
+	if err := appops.Instance().ValidateDeployment(&dep, timeout, interval); err != nil {
+		return err
+	}
+
+	/* TODO: We need to make this work for spawn
+	expectedDeployment := GetExpectedDeployment(&testing.T{}, "metricsCollectorDeployment.yaml")
+	*/
+
+	deployment, err := appops.Instance().GetDeployment(dep.Name, dep.Namespace)
+	if err != nil {
+		return err
+	}
+
+	/* TODO: We need to make this work for spawn
+	if equal, err := util.DeploymentDeepEqual(expectedDeployment, deployment); !equal {
+		return err
+	}
+	*/
+
+	_, err = rbacops.Instance().GetRole("px-metrics-collector", cluster.Namespace)
+	if err != nil {
+		return err
+	}
+
+	_, err = rbacops.Instance().GetRoleBinding("px-metrics-collector", cluster.Namespace)
+	if err != nil {
+		return err
+	}
+
+	// Verify telemetry config map
+	_, err = coreops.Instance().GetConfigMap("px-telemetry-config", cluster.Namespace)
+	if err != nil {
+		return err
+	}
+
+	// Verify collector config map
+	_, err = coreops.Instance().GetConfigMap("px-collector-config", cluster.Namespace)
+	if err != nil {
+		return err
+	}
+
+	// Verify collector proxy config map
+	if err := ValidateTelemetryProxyConfig(cluster.Namespace, GetExpectedTelemetryProxyEndpoint(cluster)); err != nil {
+		return err
+	}
+
+	// Verify collector service account
+	_, err = coreops.Instance().GetServiceAccount("px-metrics-collector", cluster.Namespace)
+	if err != nil {
+		return err
+	}
+
+	// Verify metrics collector image
+	imageName, ok := pxImageList["metricsCollector"]
+	if !ok {
+		return fmt.Errorf("failed to find image for metrics collector")
+	}
+
+	imageName = util.GetImageURN(cluster, imageName)
+
+	if deployment.Spec.Template.Spec.Containers[0].Image != imageName {
+		return fmt.Errorf("collector image mismatch, image: %s, expected: %s",
+			deployment.Spec.Template.Spec.Containers[0].Image,
+			imageName)
+	}
+
+	// Verify metrics collector proxy image
+	imageName, ok = pxImageList["metricsCollectorProxy"]
+	if !ok {
+		return fmt.Errorf("failed to find image for metrics collector proxy")
+	}
+
+	imageName = util.GetImageURN(cluster, imageName)
+	if deployment.Spec.Template.Spec.Containers[1].Image != imageName {
+		return fmt.Errorf("collector proxy image mismatch, image: %s, expected: %s",
+			deployment.Spec.Template.Spec.Containers[1].Image,
+			imageName)
+	}
+
+	// Verify metrics collector pods do not run as root
+	if err := ValidateTelemetrySecurityContext(cluster.Namespace); err != nil {
+		return err
+	}
+
+	logrus.Infof("Telemetry is enabled")
+	return nil
+}
+
+// ValidateComponentMetricsEndpoints validates that every given component deployment exposes
+// its metrics port and is annotated to be scraped by Prometheus. The componentPorts map
+// contains the deployment name of the component and its expected metrics port
+func ValidateComponentMetricsEndpoints(cluster *corev1.StorageCluster, componentPorts map[string]int32, timeout, interval time.Duration) error {
+	var componentNames []string
+	for name := range componentPorts {
+		componentNames = append(componentNames, name)
+	}
+	sort.Strings(componentNames)
+
+	for _, name := range componentNames {
+		port := componentPorts[name]
+		t := func() (interface{}, bool, error) {
+			deployment, err := appops.Instance().GetDeployment(name, cluster.Namespace)
+			if err != nil {
+				return nil, true, fmt.Errorf("failed to get deployment %s/%s, Err: %v", cluster.Namespace, name, err)
+			}
+
+			portFound := false
+			for _, container := range deployment.Spec.Template.Spec.Containers {
+				for _, containerPort := range container.Ports {
+					if containerPort.ContainerPort == port {
+						portFound = true
+						break
+					}
+				}
+			}
+			if !portFound {
+				return nil, true, fmt.Errorf("missing metrics endpoint %s/%s:%d, port is not exposed by any container", cluster.Namespace, name, port)
+			}
+
+			annotations := deployment.Spec.Template.Annotations
+			if annotations[PrometheusScrapeAnnotation] != "true" {
+				return nil, true, fmt.Errorf("missing metrics endpoint %s/%s:%d, expected annotation %s=true, got: %q",
+					cluster.Namespace, name, port, PrometheusScrapeAnnotation, annotations[PrometheusScrapeAnnotation])
+			}
+			if val, ok := annotations[PrometheusPortAnnotation]; ok && val != strconv.Itoa(int(port)) {
+				return nil, true, fmt.Errorf("missing metrics endpoint %s/%s:%d, annotation %s points to port %s",
+					cluster.Namespace, name, port, PrometheusPortAnnotation, val)
+			}
+			return nil, false, nil
+		}
+
+		if _, err := doRetryWithTimeout(t, timeout, interval); err != nil {
+			return fmt.Errorf("failed to validate metrics endpoint %s/%s:%d, Err: %v", cluster.Namespace, name, port, err)
+		}
+		logrus.Debugf("Validated metrics endpoint %s/%s:%d", cluster.Namespace, name, port)
+	}
+
+	return nil
+}
+
+// componentDisableAnnotations maps the components that can be disabled using a
+// StorageCluster annotation to the annotation and the value that disables them
+var componentDisableAnnotations = map[string]struct {
+	annotation    string
+	disabledValue bool
+}{
+	ComponentPVCController: {"portworx.io/pvc-controller", false},
+	ComponentPortworxProxy: {"portworx.io/portworx-proxy", false},
+	ComponentStorageClass:  {"portworx.io/disable-storage-class", true},
+}
+
+// componentResource is a Kubernetes object created for a component
+type componentResource struct {
+	kind      string
+	namespace string
+	name      string
+	get       func() error
+}
+
+func (r componentResource) String() string {
+	if r.namespace == "" {
+		return fmt.Sprintf("%s %s", r.kind, r.name)
+	}
+	return fmt.Sprintf("%s %s/%s", r.kind, r.namespace, r.name)
+}
+
+// getComponentResources returns the Kubernetes objects the operator creates for the given component
+func getComponentResources(cluster *corev1.StorageCluster, componentName string) ([]componentResource, error) {
+	var resources []componentResource
+	switch componentName {
+	case ComponentPVCController:
+		name := "portworx-pvc-controller"
+		resources = []componentResource{
+			{"Deployment", cluster.Namespace, name, func() error {
+				_, err := appops.Instance().GetDeployment(name, cluster.Namespace)
+				return err
+			}},
+			{"ServiceAccount", cluster.Namespace, name, func() error {
+				_, err := coreops.Instance().GetServiceAccount(name, cluster.Namespace)
+				return err
+			}},
+			{"ClusterRole", "", name, func() error {
+				_, err := rbacops.Instance().GetClusterRole(name)
+				return err
+			}},
+			{"ClusterRoleBinding", "", name, func() error {
+				_, err := rbacops.Instance().GetClusterRoleBinding(name)
+				return err
+			}},
+		}
+	case ComponentPortworxProxy:
+		name := "portworx-proxy"
+		resources = []componentResource{
+			{"DaemonSet", "kube-system", name, func() error {
+				_, err := appops.Instance().GetDaemonSet(name, "kube-system")
+				return err
+			}},
+			{"ServiceAccount", "kube-system", name, func() error {
+				_, err := coreops.Instance().GetServiceAccount(name, "kube-system")
+				return err
+			}},
+			{"ClusterRoleBinding", "", name, func() error {
+				_, err := rbacops.Instance().GetClusterRoleBinding(name)
+				return err
+			}},
+		}
+		// Portworx service in kube-system is the actual service if cluster is deployed there
+		if cluster.Namespace != "kube-system" {
+			resources = append(resources, componentResource{"Service", "kube-system", "portworx-service", func() error {
+				_, err := coreops.Instance().GetService("portworx-service", "kube-system")
+				return err
+			}})
+		}
+	case ComponentStorageClass:
+		for _, name := range []string{
+			"px-db", "px-replicated", "px-db-local-snapshot", "px-db-cloud-snapshot",
+			"px-db-encrypted", "px-replicated-encrypted", "px-db-local-snapshot-encrypted", "px-db-cloud-snapshot-encrypted",
+			"px-csi-db", "px-csi-replicated", "px-csi-db-local-snapshot", "px-csi-db-cloud-snapshot",
+			"px-csi-db-encrypted", "px-csi-replicated-encrypted", "px-csi-db-local-snapshot-encrypted", "px-csi-db-cloud-snapshot-encrypted",
+		} {
+			scName := name
+			resources = append(resources, componentResource{"StorageClass", "", scName, func() error {
+				_, err := storageops.Instance().GetStorageClass(scName)
+				return err
+			}})
+		}
+	default:
+		return nil, fmt.Errorf("component %s cannot be disabled using an annotation", componentName)
+	}
+	return resources, nil
+}
+
+// ValidateComponentDisabledByAnnotation validates that the given component is disabled using
+// its annotation on the StorageCluster and that all its resources have been removed
+func ValidateComponentDisabledByAnnotation(cluster *corev1.StorageCluster, componentName string, timeout, interval time.Duration) error {
+	disableAnnotation, ok := componentDisableAnnotations[componentName]
+	if !ok {
+		return fmt.Errorf("component %s cannot be disabled using an annotation", componentName)
+	}
+
+	value, err := strconv.ParseBool(cluster.Annotations[disableAnnotation.annotation])
+	if err != nil || value != disableAnnotation.disabledValue {
+		return fmt.Errorf("component %s is not disabled, expected annotation %s=%v, got: %q",
+			componentName, disableAnnotation.annotation, disableAnnotation.disabledValue, cluster.Annotations[disableAnnotation.annotation])
+	}
+
+	resources, err := getComponentResources(cluster, componentName)
+	if err != nil {
+		return err
+	}
+
+	// getLingeringResource returns the first resource of the component that still exists
+	getLingeringResource := func() (*componentResource, error) {
+		for i, resource := range resources {
+			err := resource.get()
+			if errors.IsNotFound(err) {
+				continue
+			} else if err != nil {
+				return &resources[i], fmt.Errorf("failed to get %s, Err: %v", resource, err)
+			}
+			return &resources[i], nil
+		}
+		return nil, nil
+	}
+
+	t := func() (interface{}, bool, error) {
+		resource, err := getLingeringResource()
+		if err != nil {
+			return nil, true, err
+		} else if resource != nil {
+			return nil, true, fmt.Errorf("waiting for %s to be removed", resource)
+		}
+		return nil, false, nil
+	}
+
+	if _, err := doRetryWithTimeout(t, timeout, interval); err != nil {
+		if resource, _ := getLingeringResource(); resource != nil {
+			return fmt.Errorf("failed to validate component %s is disabled, %s is still present", componentName, resource)
+		}
+		return fmt.Errorf("failed to validate component %s is disabled, Err: %v", componentName, err)
+	}
+
+	logrus.Debugf("Component %s is disabled and all its resources are removed", componentName)
+	return nil
+}
+
+// ValidateTelemetryTrustBundle validates that the telemetry collector proxy is configured with the
+// telemetry certificate the operator sets up: the pure-telemetry-certs secret is mounted into the
+// envoy container and the envoy config reads the certificate and key from that mount
+func ValidateTelemetryTrustBundle(cluster *corev1.StorageCluster, timeout, interval time.Duration) error {
+	var lastErr error
+	t := func() (interface{}, bool, error) {
+		lastErr = validateTelemetryTrustBundle(cluster.Namespace)
+		return nil, lastErr != nil, lastErr
 	}
 
-	if _, err := task.DoRetryWithTimeout(t, timeout, interval); err != nil {
-		return err
+	if _, err := doRetryWithTimeout(t, timeout, interval); err != nil {
+		// Report the last problem found instead of the retry timeout
+		if lastErr != nil {
+			err = lastErr
+		}
+		return fmt.Errorf("failed to validate telemetry trust bundle, Err: %v", err)
 	}
 
-	logrus.Infof("Alert manager components do not exist")
+	logrus.Debugf("Telemetry certificate %s is mounted in the collector proxy at %s", TelemetryCertSecretName, TelemetryCertMountPath)
 	return nil
 }
 
-// ValidateTelemetryInstalled validates telemetry component is running as expected
-func ValidateTelemetryInstalled(pxImageList map[string]string, cluster *corev1.StorageCluster, timeout, interval time.Duration) error {
-	// Wait for the deployment to become online
-	dep := appsv1.Deployment{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      "px-metrics-collector",
-			Namespace: cluster.Namespace,
-		},
+func validateTelemetryTrustBundle(namespace string) error {
+	deployment, err := appops.Instance().GetDeployment("px-metrics-collector", namespace)
+	if err != nil {
+		return fmt.Errorf("failed to get deployment %s/px-metrics-collector, Err: %v", namespace, err)
 	}
-	if err := appops.Instance().ValidateDeployment(&dep, timeout, interval); err != nil {
+	if err := validateTrustBundleMount(deployment); err != nil {
 		return err
 	}
-
-	/* TODO: We need to make this work for spawn
-	expectedDeployment := GetExpectedDeployment(&testing.T{}, "metricsCollectorDeployment.yaml")
-	*/
-
-	deployment, err := appops.Instance().GetDeployment(dep.Name, dep.Namespace)
+	configMap, err := coreops.Instance().GetConfigMap("px-collector-proxy-config", namespace)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to get config map %s/px-collector-proxy-config, Err: %v", namespace, err)
 	}
+	return validateTrustBundleConfig(configMap)
+}
 
-	/* TODO: We need to make this work for spawn
-	if equal, err := util.DeploymentDeepEqual(expectedDeployment, deployment); !equal {
-		return err
+func validateTrustBundleMount(deployment *appsv1.Deployment) error {
+	var volumeName string
+	for _, volume := range deployment.Spec.Template.Spec.Volumes {
+		if volume.Secret != nil && volume.Secret.SecretName == TelemetryCertSecretName {
+			volumeName = volume.Name
+			break
+		}
+	}
+	if volumeName == "" {
+		return fmt.Errorf("missing volume for secret %s in deployment %s/%s",
+			TelemetryCertSecretName, deployment.Namespace, deployment.Name)
 	}
-	*/
 
-	_, err = rbacops.Instance().GetRole("px-metrics-collector", cluster.Namespace)
-	if err != nil {
-		return err
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		if container.Name != "envoy" {
+			continue
+		}
+		for _, mount := range container.VolumeMounts {
+			if mount.Name == volumeName && mount.MountPath == TelemetryCertMountPath {
+				return nil
+			}
+		}
+		return fmt.Errorf("missing mount of volume %s (secret %s) at %s in container %s of deployment %s/%s",
+			volumeName, TelemetryCertSecretName, TelemetryCertMountPath, container.Name, deployment.Namespace, deployment.Name)
 	}
+	return fmt.Errorf("missing envoy proxy container in deployment %s/%s", deployment.Namespace, deployment.Name)
+}
 
-	_, err = rbacops.Instance().GetRoleBinding("px-metrics-collector", cluster.Namespace)
-	if err != nil {
-		return err
+func validateTrustBundleConfig(configMap *v1.ConfigMap) error {
+	config := configMap.Data["envoy-config.yaml"]
+	for _, file := range []string{"cert", "private_key"} {
+		filePath := path.Join(TelemetryCertMountPath, file)
+		if !strings.Contains(config, filePath) {
+			return fmt.Errorf("envoy config in config map %s/%s does not reference %s",
+				configMap.Namespace, configMap.Name, filePath)
+		}
 	}
+	return nil
+}
 
-	// Verify telemetry config map
-	_, err = coreops.Instance().GetConfigMap("px-telemetry-config", cluster.Namespace)
+// ValidateTelemetrySecurityContext validates that the px-metrics-collector pods run as the
+// non-root telemetry user and, if an fsGroup is set, that it is not the root group
+func ValidateTelemetrySecurityContext(namespace string) error {
+	deployment, err := appops.Instance().GetDeployment("px-metrics-collector", namespace)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to get deployment %s/px-metrics-collector, Err: %v", namespace, err)
 	}
-
-	// Verify collector config map
-	_, err = coreops.Instance().GetConfigMap("px-collector-config", cluster.Namespace)
+	pods, err := appops.Instance().GetDeploymentPods(deployment)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to get pods of deployment %s/%s, Err: %v", deployment.Namespace, deployment.Name, err)
+	}
+	for _, pod := range pods {
+		if err := validateTelemetryPodSecurityContext(&pod); err != nil {
+			return fmt.Errorf("failed to validate security context of pod %s/%s, Err: %v", pod.Namespace, pod.Name, err)
+		}
 	}
+	return nil
+}
 
-	// Verify collector proxy config map
-	_, err = coreops.Instance().GetConfigMap("px-collector-proxy-config", cluster.Namespace)
-	if err != nil {
-		return err
+func validateTelemetryPodSecurityContext(pod *v1.Pod) error {
+	var podRunAsUser *int64
+	var podRunAsNonRoot *bool
+	if podSecurityContext := pod.Spec.SecurityContext; podSecurityContext != nil {
+		podRunAsUser = podSecurityContext.RunAsUser
+		podRunAsNonRoot = podSecurityContext.RunAsNonRoot
+		if fsGroup := podSecurityContext.FSGroup; fsGroup != nil && *fsGroup == 0 {
+			return fmt.Errorf("pod fsGroup is the root group")
+		}
 	}
 
-	// Verify collector service account
-	_, err = coreops.Instance().GetServiceAccount("px-metrics-collector", cluster.Namespace)
-	if err != nil {
-		return err
+	for _, container := range pod.Spec.Containers {
+		// Container level settings take precedence over the pod level ones
+		runAsUser, runAsNonRoot := podRunAsUser, podRunAsNonRoot
+		if container.SecurityContext != nil {
+			if container.SecurityContext.RunAsUser != nil {
+				runAsUser = container.SecurityContext.RunAsUser
+			}
+			if container.SecurityContext.RunAsNonRoot != nil {
+				runAsNonRoot = container.SecurityContext.RunAsNonRoot
+			}
+		}
+		if runAsNonRoot != nil && !*runAsNonRoot {
+			return fmt.Errorf("container %s is allowed to run as root", container.Name)
+		}
+		if runAsUser == nil {
+			return fmt.Errorf("container %s has no runAsUser, expected: %d", container.Name, TelemetryCollectorRunAsUser)
+		}
+		if *runAsUser != TelemetryCollectorRunAsUser {
+			return fmt.Errorf("container %s runAsUser: expected: %d, actual: %d", container.Name, TelemetryCollectorRunAsUser, *runAsUser)
+		}
 	}
+	return nil
+}
 
-	// Verify metrics collector image
-	imageName, ok := pxImageList["metricsCollector"]
+// GetExpectedTelemetryProxyEndpoint returns the Arcus endpoint the telemetry proxy is expected to
+// upload to. The cloud endpoint is used when the arcus-location annotation is empty or set to
+// external, the staging endpoint when it is set to internal, and any other value is used as the
+// endpoint itself.
+func GetExpectedTelemetryProxyEndpoint(cluster *corev1.StorageCluster) string {
+	arcusLocation := cluster.Annotations["portworx.io/arcus-location"]
+	switch strings.ToLower(arcusLocation) {
+	case "", "external":
+		return TelemetryExternalArcusEndpoint
+	case "internal":
+		return TelemetryInternalArcusEndpoint
+	}
+	return arcusLocation
+}
+
+// ValidateTelemetryProxyConfig validates that the envoy config in the px-collector-proxy-config
+// config map routes the telemetry uploads to the expected endpoint
+func ValidateTelemetryProxyConfig(namespace, expectedEndpoint string) error {
+	configMap, err := coreops.Instance().GetConfigMap("px-collector-proxy-config", namespace)
+	if err != nil {
+		return fmt.Errorf("failed to get config map %s/px-collector-proxy-config, Err: %v", namespace, err)
+	}
+	config, ok := configMap.Data["envoy-config.yaml"]
 	if !ok {
-		return fmt.Errorf("failed to find image for metrics collector")
+		return fmt.Errorf("missing envoy-config.yaml in config map %s/%s", configMap.Namespace, configMap.Name)
 	}
+	if err := validateTelemetryProxyEndpoint(config, expectedEndpoint); err != nil {
+		return fmt.Errorf("failed to validate config map %s/%s, Err: %v", configMap.Namespace, configMap.Name, err)
+	}
+	return nil
+}
 
-	imageName = util.GetImageURN(cluster, imageName)
+// telemetryProxyConfig is the part of the envoy config that holds the telemetry upload endpoint
+type telemetryProxyConfig struct {
+	StaticResources struct {
+		Listeners []struct {
+			FilterChains []struct {
+				Filters []struct {
+					TypedConfig struct {
+						RouteConfig struct {
+							VirtualHosts []struct {
+								Routes []struct {
+									Route struct {
+										HostRewriteLiteral string `json:"host_rewrite_literal"`
+									} `json:"route"`
+								} `json:"routes"`
+							} `json:"virtual_hosts"`
+						} `json:"route_config"`
+					} `json:"typed_config"`
+				} `json:"filters"`
+			} `json:"filter_chains"`
+		} `json:"listeners"`
+		Clusters []struct {
+			LoadAssignment struct {
+				Endpoints []struct {
+					LbEndpoints []struct {
+						Endpoint struct {
+							Address struct {
+								SocketAddress struct {
+									Address string `json:"address"`
+								} `json:"socket_address"`
+							} `json:"address"`
+						} `json:"endpoint"`
+					} `json:"lb_endpoints"`
+				} `json:"endpoints"`
+			} `json:"load_assignment"`
+		} `json:"clusters"`
+	} `json:"static_resources"`
+}
 
-	if deployment.Spec.Template.Spec.Containers[0].Image != imageName {
-		return fmt.Errorf("collector image mismatch, image: %s, expected: %s",
-			deployment.Spec.Template.Spec.Containers[0].Image,
-			imageName)
+func validateTelemetryProxyEndpoint(config, expectedEndpoint string) error {
+	proxyConfig := &telemetryProxyConfig{}
+	if err := yaml.Unmarshal([]byte(config), proxyConfig); err != nil {
+		return fmt.Errorf("failed to parse envoy config, Err: %v", err)
 	}
 
-	// Verify metrics collector proxy image
-	imageName, ok = pxImageList["metricsCollectorProxy"]
-	if !ok {
-		return fmt.Errorf("failed to find image for metrics collector proxy")
+	var addresses []string
+	for _, cluster := range proxyConfig.StaticResources.Clusters {
+		for _, endpoint := range cluster.LoadAssignment.Endpoints {
+			for _, lbEndpoint := range endpoint.LbEndpoints {
+				addresses = append(addresses, lbEndpoint.Endpoint.Address.SocketAddress.Address)
+			}
+		}
 	}
-
-	imageName = util.GetImageURN(cluster, imageName)
-	if deployment.Spec.Template.Spec.Containers[1].Image != imageName {
-		return fmt.Errorf("collector proxy image mismatch, image: %s, expected: %s",
-			deployment.Spec.Template.Spec.Containers[1].Image,
-			imageName)
+	if len(addresses) == 0 {
+		return fmt.Errorf("no upstream endpoint found in envoy config, expected %s", expectedEndpoint)
+	}
+	for _, address := range addresses {
+		if address != expectedEndpoint {
+			return fmt.Errorf("upstream endpoint: expected: %s, actual: %s", expectedEndpoint, address)
+		}
 	}
 
-	logrus.Infof("Telemetry is enabled")
+	for _, listener := range proxyConfig.StaticResources.Listeners {
+		for _, filterChain := range listener.FilterChains {
+			for _, filter := range filterChain.Filters {
+				for _, virtualHost := range filter.TypedConfig.RouteConfig.VirtualHosts {
+					for _, route := range virtualHost.Routes {
+						if host := route.Route.HostRewriteLiteral; host != "" && host != expectedEndpoint {
+							return fmt.Errorf("route host rewrite: expected: %s, actual: %s", expectedEndpoint, host)
+						}
+					}
+				}
+			}
+		}
+	}
 	return nil
 }
 
@@ -2562,7 +6493,7 @@ func validatePodTopologySpreadConstraints(deployment *appsv1.Deployment, timeout
 	}
 
 	logrus.Infof("validating deployment %s/%s pod topology spread constraints", deployment.Namespace, deployment.Name)
-	if _, err := task.DoRetryWithTimeout(t, timeout, interval); err != nil {
+	if _, err := doRetryWithTimeout(t, timeout, interval); err != nil {
 		return err
 	}
 	return nil
@@ -2638,6 +6569,129 @@ func startPort(cluster *corev1.StorageCluster) int {
 	return startPort
 }
 
+// ValidateOpenShiftSCC validates that the SecurityContextConstraints required by Portworx exist
+// on OpenShift and grant privileged containers, host path volumes and host networking. The
+// SecurityContextConstraints are not validated if the cluster is not running on OpenShift.
+func ValidateOpenShiftSCC(k8sClient client.Client, cluster *corev1.StorageCluster) error {
+	if !isOpenshift(cluster) {
+		logrus.Debug("StorageCluster is not running on OpenShift, skipping SecurityContextConstraints validation")
+		return nil
+	}
+
+	scc := &ocp_secv1.SecurityContextConstraints{}
+	if err := Get(k8sClient, scc, portworxSCCName, ""); err != nil {
+		return fmt.Errorf("failed to get SecurityContextConstraints %s, Err: %v", portworxSCCName, err)
+	}
+
+	var missing []string
+	if !scc.AllowPrivilegedContainer {
+		missing = append(missing, "allowPrivilegedContainer")
+	}
+	if !scc.AllowHostDirVolumePlugin {
+		missing = append(missing, "allowHostDirVolumePlugin")
+	}
+	if !scc.AllowHostNetwork {
+		missing = append(missing, "allowHostNetwork")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("failed to validate SecurityContextConstraints %s, missing privileges: %v",
+			scc.Name, missing)
+	}
+	return nil
+}
+
+// ValidatePSP validates that the expected PodSecurityPolicies exist and match the live ones when
+// PSPs are enabled in the StorageCluster, and that the privileged PSP is bound to Portworx through
+// the portworx ClusterRole. PSPs are not validated on Kubernetes 1.25 and up, where they are removed.
+func ValidatePSP(
+	k8sClient client.Client,
+	cluster *corev1.StorageCluster,
+	k8sVersion *version.Version,
+	expectedPSPs ...*policyv1beta1.PodSecurityPolicy,
+) error {
+	if !isPSPEnabled(cluster) {
+		logrus.Debug("PodSecurityPolicy is not enabled in StorageCluster, skipping PodSecurityPolicy validation")
+		return nil
+	}
+	if !util.VersionSupportsPSP(k8sVersion) {
+		logrus.Debugf("PodSecurityPolicy is removed in Kubernetes %s, skipping PodSecurityPolicy validation", k8sVersion)
+		return nil
+	}
+
+	for _, expected := range expectedPSPs {
+		psp := &policyv1beta1.PodSecurityPolicy{}
+		if err := Get(k8sClient, psp, expected.Name, ""); err != nil {
+			return fmt.Errorf("failed to get PodSecurityPolicy %s, Err: %v", expected.Name, err)
+		}
+		if !reflect.DeepEqual(expected.Spec, psp.Spec) {
+			return fmt.Errorf("PodSecurityPolicy %s doesn't match expected, expected: %+v, actual: %+v",
+				expected.Name, expected.Spec, psp.Spec)
+		}
+	}
+
+	clusterRole := &rbacv1.ClusterRole{}
+	if err := Get(k8sClient, clusterRole, portworxClusterRoleName, ""); err != nil {
+		return fmt.Errorf("failed to get ClusterRole %s, Err: %v", portworxClusterRoleName, err)
+	}
+	if !clusterRoleCanUsePSP(clusterRole, constants.PrivilegedPSPName) {
+		return fmt.Errorf("ClusterRole %s doesn't grant use of PodSecurityPolicy %s",
+			clusterRole.Name, constants.PrivilegedPSPName)
+	}
+
+	clusterRoleBinding := &rbacv1.ClusterRoleBinding{}
+	if err := Get(k8sClient, clusterRoleBinding, portworxClusterRoleName, ""); err != nil {
+		return fmt.Errorf("failed to get ClusterRoleBinding %s, Err: %v", portworxClusterRoleName, err)
+	}
+	if clusterRoleBinding.RoleRef.Kind != "ClusterRole" || clusterRoleBinding.RoleRef.Name != clusterRole.Name {
+		return fmt.Errorf("ClusterRoleBinding %s doesn't bind ClusterRole %s, roleRef: %+v",
+			clusterRoleBinding.Name, clusterRole.Name, clusterRoleBinding.RoleRef)
+	}
+	return nil
+}
+
+// ValidatePodSecurity validates the pod security mechanism used for the given Kubernetes version.
+// PodSecurityPolicies are validated on versions that support them. On newer versions the
+// StorageCluster namespace must not enforce a PodSecurity admission level other than privileged,
+// as Portworx pods require privileged containers, host networking and host path volumes.
+func ValidatePodSecurity(
+	k8sClient client.Client,
+	cluster *corev1.StorageCluster,
+	k8sVersion *version.Version,
+	expectedPSPs ...*policyv1beta1.PodSecurityPolicy,
+) error {
+	if util.VersionSupportsPSP(k8sVersion) {
+		return ValidatePSP(k8sClient, cluster, k8sVersion, expectedPSPs...)
+	}
+
+	namespace, err := coreops.Instance().GetNamespace(cluster.Namespace)
+	if err != nil {
+		return fmt.Errorf("failed to get namespace %s, Err: %v", cluster.Namespace, err)
+	}
+	if level, ok := namespace.Labels[podSecurityEnforceLabel]; ok && level != podSecurityLevelPrivileged {
+		return fmt.Errorf("namespace %s enforces PodSecurity level %s, expected: %s",
+			namespace.Name, level, podSecurityLevelPrivileged)
+	}
+	return nil
+}
+
+// clusterRoleCanUsePSP returns true if the ClusterRole grants use of the given PodSecurityPolicy
+func clusterRoleCanUsePSP(clusterRole *rbacv1.ClusterRole, pspName string) bool {
+	for _, rule := range clusterRole.Rules {
+		if sets.NewString(rule.APIGroups...).Has("policy") &&
+			sets.NewString(rule.Resources...).Has("podsecuritypolicies") &&
+			sets.NewString(rule.ResourceNames...).Has(pspName) &&
+			sets.NewString(rule.Verbs...).Has("use") {
+			return true
+		}
+	}
+	return false
+}
+
+func isPSPEnabled(cluster *corev1.StorageCluster) bool {
+	enabled, err := strconv.ParseBool(cluster.Annotations["portworx.io/pod-security-policy"])
+	return err == nil && enabled
+}
+
 // GetK8SVersion gets and return K8S server version
 func GetK8SVersion() (string, error) {
 	kbVerRegex := regexp.MustCompile(`^(v\d+\.\d+\.\d+).*`)
@@ -2652,12 +6706,27 @@ func GetK8SVersion() (string, error) {
 	return matches[1], nil
 }
 
+// GetK8SVersionFull gets and returns the unparsed K8S server GitVersion, including any vendor
+// suffix such as -gke.2300 or -eks-49a6c0, along with its parsed version. The vendor suffix is
+// kept in the prerelease part of the parsed version.
+func GetK8SVersionFull() (string, *version.Version, error) {
+	k8sVersion, err := coreops.Instance().GetVersion()
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to get kubernetes version: %v", err)
+	}
+	ver, err := version.NewVersion(k8sVersion.GitVersion)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid kubernetes version received: %v", k8sVersion.GitVersion)
+	}
+	return k8sVersion.GitVersion, ver, nil
+}
+
 // GetImagesFromVersionURL gets images from version URL
 func GetImagesFromVersionURL(url, k8sVersion string) (map[string]string, error) {
 	imageListMap := make(map[string]string)
 
 	// Construct PX version URL
-	pxVersionURL, err := ConstructVersionURL(url, k8sVersion)
+	pxVersionURL, err := ConstructVersionURL(url, k8sVersion, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -2690,15 +6759,19 @@ func GetImagesFromVersionURL(url, k8sVersion string) (map[string]string, error)
 	return imageListMap, nil
 }
 
-// ConstructVersionURL constructs Portworx version URL that contains component images
-func ConstructVersionURL(specGenURL, k8sVersion string) (string, error) {
-	u, err := url.Parse(specGenURL)
+// ConstructVersionURL constructs Portworx version URL that contains component images.
+// The given query params are added to the URL, and kbver is set to the given
+// Kubernetes version unless it is passed in the query params.
+func ConstructVersionURL(specGenURL, k8sVersion string, queryParams map[string]string) (string, error) {
+	u, err := specGenVersionURL(specGenURL)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse URL [%s], Err: %v", specGenURL, err)
+		return "", err
 	}
 	q := u.Query()
 	q.Set("kbver", k8sVersion)
-	u.Path = path.Join(u.Path, "version")
+	for key, value := range queryParams {
+		q.Set(key, value)
+	}
 	u.RawQuery = q.Encode()
 
 	return u.String(), nil
@@ -2706,29 +6779,64 @@ func ConstructVersionURL(specGenURL, k8sVersion string) (string, error) {
 
 // ConstructPxReleaseManifestURL constructs Portworx install URL
 func ConstructPxReleaseManifestURL(specGenURL string) (string, error) {
-	u, err := url.Parse(specGenURL)
+	u, err := specGenVersionURL(specGenURL)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse URL [%s], Err: %v", specGenURL, err)
+		return "", err
 	}
-
-	u.Path = path.Join(u.Path, "version")
 	return u.String(), nil
 }
 
-func validateStorageClusterInState(cluster *corev1.StorageCluster, status corev1.ClusterConditionStatus) func() (interface{}, bool, error) {
+// specGenVersionURL parses the given spec gen URL and appends the version endpoint to its path
+// only, so that the scheme, host, port and query string of the URL are left intact
+func specGenVersionURL(specGenURL string) (*url.URL, error) {
+	u, err := url.Parse(specGenURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse URL [%s], Err: %v", specGenURL, err)
+	}
+	u.Path = path.Join("/", u.Path, "version")
+	u.RawPath = ""
+	return u, nil
+}
+
+func validateStorageClusterInState(cluster *corev1.StorageCluster, statuses ...corev1.ClusterConditionStatus) func() (interface{}, bool, error) {
 	return func() (interface{}, bool, error) {
 		cluster, err := operatorops.Instance().GetStorageCluster(cluster.Name, cluster.Namespace)
 		if err != nil {
 			return nil, true, fmt.Errorf("failed to get StorageCluster %s in %s, Err: %v", cluster.Name, cluster.Namespace, err)
 		}
-		if cluster.Status.Phase != string(status) {
-			if cluster.Status.Phase == "" {
-				return nil, true, fmt.Errorf("failed to get cluster status")
+		for _, status := range statuses {
+			if cluster.Status.Phase == string(status) {
+				return cluster, false, nil
 			}
-			return nil, true, fmt.Errorf("cluster state: %s", cluster.Status.Phase)
 		}
-		return cluster, false, nil
+		return nil, true, &ErrClusterNotInPhase{
+			Namespace:      cluster.Namespace,
+			Name:           cluster.Name,
+			Phase:          cluster.Status.Phase,
+			ExpectedPhases: statuses,
+		}
+	}
+}
+
+// WaitForStorageClusterPhase waits for the StorageCluster to be in any of the given phases
+// and returns the live cluster
+func WaitForStorageClusterPhase(
+	cluster *corev1.StorageCluster,
+	timeout, interval time.Duration,
+	phases ...corev1.ClusterConditionStatus,
+) (*corev1.StorageCluster, error) {
+	if len(phases) == 0 {
+		return nil, fmt.Errorf("no phases given to wait for StorageCluster %s/%s", cluster.Namespace, cluster.Name)
+	}
+	out, err := doRetryWithTimeout(validateStorageClusterInState(cluster, phases...), timeout, interval)
+	if err != nil {
+		// Report the last observed state instead of the retry timeout
+		if _, _, stateErr := validateStorageClusterInState(cluster, phases...)(); stateErr != nil {
+			err = stateErr
+		}
+		return nil, fmt.Errorf("failed to wait for StorageCluster to be in phase %v, Err: %w", phases, err)
 	}
+	return out.(*corev1.StorageCluster), nil
 }
 
 func validateAllStorageNodesInState(namespace string, status corev1.NodeConditionStatus) func() (interface{}, bool, error) {
@@ -2751,25 +6859,116 @@ func validateAllStorageNodesInState(namespace string, status corev1.NodeConditio
 
 // ValidateStorageClusterIsOnline wait for storage cluster to become online.
 func ValidateStorageClusterIsOnline(cluster *corev1.StorageCluster, timeout, interval time.Duration) (*corev1.StorageCluster, error) {
-	out, err := task.DoRetryWithTimeout(validateStorageClusterInState(cluster, corev1.ClusterOnline), timeout, interval)
-	if err != nil {
-		return nil, fmt.Errorf("failed to wait for StorageCluster to be ready, Err: %v", err)
+	return validateStorageClusterIsOnline(context.Background(), cluster, timeout, interval)
+}
+
+func validateStorageClusterIsOnline(ctx context.Context, cluster *corev1.StorageCluster, timeout, interval time.Duration) (*corev1.StorageCluster, error) {
+	out, err := doRetryWithContext(ctx, validateStorageClusterInState(cluster, corev1.ClusterOnline), timeout, interval)
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("failed to wait for StorageCluster to be ready, Err: %w", ctx.Err())
+	} else if err != nil {
+		// Report the last observed state instead of the retry timeout
+		if _, _, stateErr := validateStorageClusterInState(cluster, corev1.ClusterOnline)(); stateErr != nil {
+			err = stateErr
+		}
+		return nil, fmt.Errorf("failed to wait for StorageCluster to be ready, Err: %w", err)
 	}
 	cluster = out.(*corev1.StorageCluster)
 
 	return cluster, nil
 }
 
-func validateStorageClusterIsFailed(cluster *corev1.StorageCluster, timeout, interval time.Duration) error {
-	_, err := task.DoRetryWithTimeout(validateAllStorageNodesInState(cluster.Namespace, corev1.NodeFailedStatus), timeout, interval)
+// ValidateStorageClusterCondition waits until the StorageCluster condition of the given type
+// reaches the expected status. On timeout the error contains the current cluster conditions.
+func ValidateStorageClusterCondition(
+	cluster *corev1.StorageCluster,
+	conditionType corev1.ClusterConditionType,
+	expectedStatus corev1.ClusterConditionStatus,
+	timeout, interval time.Duration,
+) error {
+	t := func() (interface{}, bool, error) {
+		liveCluster, err := operatorops.Instance().GetStorageCluster(cluster.Name, cluster.Namespace)
+		if err != nil {
+			return nil, true, fmt.Errorf("failed to get StorageCluster %s in %s, Err: %v", cluster.Name, cluster.Namespace, err)
+		}
+		for _, condition := range liveCluster.Status.Conditions {
+			if condition.Type == conditionType && condition.Status == expectedStatus {
+				return nil, false, nil
+			}
+		}
+		return nil, true, fmt.Errorf("waiting for condition %s to be %s, current conditions: %s",
+			conditionType, expectedStatus, formatClusterConditions(liveCluster.Status.Conditions))
+	}
+
+	if _, err := doRetryWithTimeout(t, timeout, interval); err != nil {
+		liveCluster, getErr := operatorops.Instance().GetStorageCluster(cluster.Name, cluster.Namespace)
+		if getErr != nil {
+			return fmt.Errorf("failed to wait for StorageCluster condition %s to be %s, Err: %v", conditionType, expectedStatus, err)
+		}
+		return fmt.Errorf("failed to wait for StorageCluster condition %s to be %s, current conditions: %s",
+			conditionType, expectedStatus, formatClusterConditions(liveCluster.Status.Conditions))
+	}
+	return nil
+}
+
+func formatClusterConditions(conditions []corev1.ClusterCondition) string {
+	if len(conditions) == 0 {
+		return "[]"
+	}
+	formatted := make([]string, 0, len(conditions))
+	for _, condition := range conditions {
+		formatted = append(formatted, fmt.Sprintf("%s=%s", condition.Type, condition.Status))
+	}
+	return "[" + strings.Join(formatted, ", ") + "]"
+}
+
+// ValidateStorageClusterIsDegraded waits for the StorageCluster to be degraded. The operator has no
+// degraded cluster phase: a cluster with degraded storage stays in the Online phase, while the
+// affected StorageNodes move to the Degraded phase. So this waits for the cluster to be Online
+// with at least one StorageNode in the Degraded phase, and returns the live cluster.
+func ValidateStorageClusterIsDegraded(cluster *corev1.StorageCluster, timeout, interval time.Duration) (*corev1.StorageCluster, error) {
+	t := func() (interface{}, bool, error) {
+		liveCluster, _, err := validateStorageClusterInState(cluster, corev1.ClusterOnline)()
+		if err != nil {
+			return nil, true, err
+		}
+
+		storageNodeList, err := operatorops.Instance().ListStorageNodes(cluster.Namespace)
+		if err != nil {
+			return nil, true, fmt.Errorf("failed to list StorageNodes in %s, Err: %v", cluster.Namespace, err)
+		}
+		var nodePhases []string
+		for _, node := range storageNodeList.Items {
+			if node.Status.Phase == string(corev1.NodeDegradedStatus) {
+				return liveCluster, false, nil
+			}
+			nodePhases = append(nodePhases, fmt.Sprintf("%s=%s", node.Name, node.Status.Phase))
+		}
+		return nil, true, fmt.Errorf("no StorageNode is in phase %s, StorageNode phases: [%s]",
+			corev1.NodeDegradedStatus, strings.Join(nodePhases, ", "))
+	}
+
+	out, err := doRetryWithTimeout(t, timeout, interval)
+	if err != nil {
+		// Report the last observed state instead of the retry timeout
+		if _, _, stateErr := t(); stateErr != nil {
+			err = stateErr
+		}
+		return nil, fmt.Errorf("failed to wait for StorageCluster to be degraded, Err: %w", err)
+	}
+	return out.(*corev1.StorageCluster), nil
+}
+
+func validateStorageClusterIsFailed(ctx context.Context, cluster *corev1.StorageCluster, timeout, interval time.Duration) error {
+	_, err := doRetryWithContext(ctx, validateAllStorageNodesInState(cluster.Namespace, corev1.NodeFailedStatus), timeout, interval)
 	if err != nil {
-		return fmt.Errorf("failed to wait for StorageNodes to be failed, Err: %v", err)
+		return fmt.Errorf("failed to wait for StorageNodes to be failed, Err: %w", err)
 	}
 	return nil
 }
 
 func validateStorageClusterIsInitializing(cluster *corev1.StorageCluster, timeout, interval time.Duration) error {
-	_, err := task.DoRetryWithTimeout(validateAllStorageNodesInState(cluster.Namespace, corev1.NodeInitStatus), timeout, interval)
+	_, err := doRetryWithTimeout(validateAllStorageNodesInState(cluster.Namespace, corev1.NodeInitStatus), timeout, interval)
 	if err != nil {
 		return fmt.Errorf("failed to wait for StorageNodes to be initializing, Err: %v", err)
 	}
@@ -2866,7 +7065,7 @@ func validateK8Events(
 		}
 		return "", false, nil
 	}
-	if _, err := task.DoRetryWithTimeout(t, timeout, interval); err != nil {
+	if _, err := doRetryWithTimeout(t, timeout, interval); err != nil {
 		return err
 	}
 
@@ -2917,6 +7116,47 @@ func CreateClusterWithTLS(caCertFileName, serverCertFileName, serverKeyFileName
 	return cluster
 }
 
+// CreateClusterWithMonitoring is a helper method that returns a cluster with the
+// Prometheus, AlertManager and Telemetry monitoring specs populated
+func CreateClusterWithMonitoring(enablePrometheus, enableAlertmanager, enableTelemetry bool) *corev1.StorageCluster {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-system",
+		},
+		Spec: corev1.StorageClusterSpec{
+			Monitoring: &corev1.MonitoringSpec{
+				Prometheus: &corev1.PrometheusSpec{
+					Enabled:       enablePrometheus,
+					ExportMetrics: enablePrometheus,
+					AlertManager: &corev1.AlertManagerSpec{
+						Enabled: enableAlertmanager,
+					},
+				},
+				Telemetry: &corev1.TelemetrySpec{
+					Enabled: enableTelemetry,
+				},
+			},
+		},
+	}
+	return cluster
+}
+
+// CreateClusterWithCustomRegistry is a helper method that returns a cluster
+// with the given custom image registry
+func CreateClusterWithCustomRegistry(registry string) *corev1.StorageCluster {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-system",
+		},
+		Spec: corev1.StorageClusterSpec{
+			CustomImageRegistry: registry,
+		},
+	}
+	return cluster
+}
+
 // BoolPtr returns a pointer to provided bool value
 func BoolPtr(val bool) *bool {
 	return &val
@@ -0,0 +1,5443 @@
+package test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/hashicorp/go-version"
+	"github.com/libopenstorage/openstorage/api"
+	"github.com/libopenstorage/openstorage/pkg/auth"
+	ocp_secv1 "github.com/openshift/api/security/v1"
+	apiextensionsops "github.com/portworx/sched-ops/k8s/apiextensions"
+	appops "github.com/portworx/sched-ops/k8s/apps"
+	coreops "github.com/portworx/sched-ops/k8s/core"
+	operatorops "github.com/portworx/sched-ops/k8s/operator"
+	prometheusops "github.com/portworx/sched-ops/k8s/prometheus"
+	rbacops "github.com/portworx/sched-ops/k8s/rbac"
+	storageops "github.com/portworx/sched-ops/k8s/storage"
+	"github.com/portworx/sched-ops/task"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	fakeextclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	k8sversion "k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	fakek8sclient "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1 "github.com/libopenstorage/operator/pkg/apis/core/v1"
+	fakeoperatorclient "github.com/libopenstorage/operator/pkg/client/clientset/versioned/fake"
+	"github.com/libopenstorage/operator/pkg/constants"
+	"github.com/libopenstorage/operator/pkg/mock"
+	"github.com/libopenstorage/operator/pkg/util"
+)
+
+func TestExtractPxVersion(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+	}
+
+	// TestCase: Version is taken from the image tag
+	pxImageList := map[string]string{"version": "portworx/oci-monitor:2.10.1"}
+	pxVersion, err := ExtractPxVersion(pxImageList, cluster)
+	require.NoError(t, err)
+	require.Equal(t, "2.10.1", pxVersion)
+
+	// TestCase: Version with underscore is taken from the release manifest URL
+	pxImageList = map[string]string{"version": "portworx/oci-monitor:2.10.1_abcdef"}
+	cluster.Spec.Env = []v1.EnvVar{
+		{
+			Name:  PxReleaseManifestURLEnvVarName,
+			Value: "https://edge-install.portworx.com/2.10.2/version",
+		},
+	}
+	pxVersion, err = ExtractPxVersion(pxImageList, cluster)
+	require.NoError(t, err)
+	require.Equal(t, "2.10.2", pxVersion)
+
+	// TestCase: Version with underscore and a master release manifest URL
+	cluster.Spec.Env[0].Value = "https://edge-install.portworx.com/master/version"
+	pxVersion, err = ExtractPxVersion(pxImageList, cluster)
+	require.NoError(t, err)
+	require.Equal(t, PxMasterVersion, pxVersion)
+
+	// TestCase: Version with underscore and malformed release manifest URLs
+	for _, manifestURL := range []string{
+		"https://edge-install.portworx.com/2.10.2/verison",
+		"edge-install.portworx.com/2.10.2/version",
+		"https://edge-install.portworx.com/%zz/version",
+		"2.10.2",
+	} {
+		cluster.Spec.Env[0].Value = manifestURL
+		pxVersion, err = ExtractPxVersion(pxImageList, cluster)
+		require.Error(t, err, manifestURL)
+		require.Contains(t, err.Error(), "malformed "+PxReleaseManifestURLEnvVarName)
+		require.Empty(t, pxVersion)
+	}
+
+	// TestCase: Version with underscore and no release manifest URL
+	cluster.Spec.Env = nil
+	pxVersion, err = ExtractPxVersion(pxImageList, cluster)
+	require.Error(t, err)
+	require.Empty(t, pxVersion)
+
+	// TestCase: Malformed images without a tag
+	for _, image := range []string{"portworx/oci-monitor", "portworx/oci-monitor:", ""} {
+		pxVersion, err = ExtractPxVersion(map[string]string{"version": image}, cluster)
+		require.Error(t, err, image)
+		require.Empty(t, pxVersion)
+	}
+}
+
+func TestValidateStorageNodesVersion(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+	}
+	node1 := &corev1.StorageNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "node1",
+			Namespace: cluster.Namespace,
+		},
+		Spec: corev1.StorageNodeSpec{
+			Version: "2.10.1.0-abcdef",
+		},
+	}
+	node2 := node1.DeepCopy()
+	node2.Name = "node2"
+	node2.Spec.Version = "2.10.0.0-123456"
+
+	// TestCase: No StorageNodes present
+	operatorops.SetInstance(operatorops.New(fakeoperatorclient.NewSimpleClientset()))
+	err := ValidateStorageNodesVersion(cluster, "2.10.1", time.Second, 100*time.Millisecond)
+	require.Error(t, err)
+
+	// TestCase: Not all StorageNodes are on the expected version
+	operatorops.SetInstance(operatorops.New(fakeoperatorclient.NewSimpleClientset(node1, node2)))
+	err = ValidateStorageNodesVersion(cluster, "2.10.1", time.Second, 100*time.Millisecond)
+	require.Error(t, err)
+
+	// TestCase: All StorageNodes are on the expected version
+	node2.Spec.Version = "2.10.1.0-123456"
+	operatorops.SetInstance(operatorops.New(fakeoperatorclient.NewSimpleClientset(node1, node2)))
+	err = ValidateStorageNodesVersion(cluster, "2.10.1", time.Second, 100*time.Millisecond)
+	require.NoError(t, err)
+}
+
+func TestValidateStorageNodesReportsLaggingNodes(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+	}
+	pxImageList := map[string]string{"version": "portworx/oci-monitor:2.10.1"}
+	newStorageNode := func(name, phase, version string) *corev1.StorageNode {
+		return &corev1.StorageNode{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: cluster.Namespace,
+			},
+			Spec:   corev1.StorageNodeSpec{Version: version},
+			Status: corev1.NodeStatus{Phase: phase},
+		}
+	}
+
+	// TestCase: All StorageNodes are online and on the expected version
+	operatorops.SetInstance(operatorops.New(fakeoperatorclient.NewSimpleClientset(
+		newStorageNode("node1", "Online", "2.10.1.0-abcdef"),
+		newStorageNode("node2", "Online", "2.10.1.0-abcdef"),
+	)))
+	err := validateStorageNodes(context.TODO(), pxImageList, cluster, time.Second, 10*time.Millisecond)
+	require.NoError(t, err)
+
+	// TestCase: Error names the lagging nodes with their phase and version
+	operatorops.SetInstance(operatorops.New(fakeoperatorclient.NewSimpleClientset(
+		newStorageNode("node1", "Online", "2.10.1.0-abcdef"),
+		newStorageNode("node2", "Initializing", "2.10.1.0-abcdef"),
+		newStorageNode("node3", "Online", "2.10.0.0-123456"),
+	)))
+	err = validateStorageNodes(context.TODO(), pxImageList, cluster, 100*time.Millisecond, 10*time.Millisecond)
+	require.Error(t, err)
+	require.Equal(t, "failed to wait for StorageNodes to be ready, Err: waiting for all storagenodes to be ready: 1/3, "+
+		`lagging: node2 (phase "Initializing"); node3 (version "2.10.0.0-123456", expected 2.10.1)`, err.Error())
+
+	// TestCase: Version is not checked with a PX_IMAGE override
+	cluster.Spec.Env = []v1.EnvVar{{Name: PxImageEnvVarName, Value: "portworx/oci-monitor:custom-build"}}
+	err = validateStorageNodes(context.TODO(), pxImageList, cluster, 100*time.Millisecond, 10*time.Millisecond)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `lagging: node2 (phase "Initializing")`)
+	require.NotContains(t, err.Error(), "node3")
+
+	// TestCase: Cancelled context is returned as is
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = validateStorageNodes(ctx, pxImageList, cluster, time.Second, 10*time.Millisecond)
+	require.Equal(t, context.Canceled, err)
+}
+
+func TestValidateStorageNodeStatusDetails(t *testing.T) {
+	node := &corev1.StorageNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "node1",
+			Namespace: "kube-test",
+		},
+	}
+
+	// TestCase: Network details are missing
+	err := ValidateStorageNodeStatusDetails(node, nil)
+	require.Error(t, err)
+	require.Equal(t, "StorageNode kube-test/node1 is missing status.network.dataIP", err.Error())
+
+	// TestCase: Only data IP is populated
+	node.Status.Network.DataIP = "10.0.0.1"
+	err = ValidateStorageNodeStatusDetails(node, nil)
+	require.Error(t, err)
+	require.Equal(t, "StorageNode kube-test/node1 is missing status.network.mgmtIP", err.Error())
+
+	// TestCase: Management IP is not a valid IP
+	node.Status.Network.MgmtIP = "node1"
+	err = ValidateStorageNodeStatusDetails(node, nil)
+	require.Error(t, err)
+	require.Equal(t, "StorageNode kube-test/node1 has invalid status.network.mgmtIP node1", err.Error())
+
+	// TestCase: Storage details are missing
+	node.Status.Network.MgmtIP = "10.0.0.2"
+	err = ValidateStorageNodeStatusDetails(node, nil)
+	require.Error(t, err)
+	require.Equal(t, "StorageNode kube-test/node1 is missing status.storage.totalSize", err.Error())
+
+	// TestCase: Used size is greater than total size
+	node.Status.Storage.TotalSize = resource.MustParse("100Gi")
+	node.Status.Storage.UsedSize = resource.MustParse("200Gi")
+	err = ValidateStorageNodeStatusDetails(node, nil)
+	require.Error(t, err)
+	require.Equal(t, "StorageNode kube-test/node1 has status.storage.usedSize 200Gi greater than totalSize 100Gi", err.Error())
+
+	// TestCase: All details are populated
+	node.Status.Storage.UsedSize = resource.MustParse("10Gi")
+	err = ValidateStorageNodeStatusDetails(node, nil)
+	require.NoError(t, err)
+
+	// TestCase: Details do not match the expected status
+	expected := &corev1.NodeStatus{
+		Network: corev1.NetworkStatus{DataIP: "10.0.0.3"},
+	}
+	err = ValidateStorageNodeStatusDetails(node, expected)
+	require.Error(t, err)
+	require.Equal(t, "StorageNode kube-test/node1 has wrong status.network.dataIP, expected: 10.0.0.3, actual: 10.0.0.1", err.Error())
+
+	expected = &corev1.NodeStatus{
+		Storage: corev1.StorageStatus{TotalSize: resource.MustParse("50Gi")},
+	}
+	err = ValidateStorageNodeStatusDetails(node, expected)
+	require.Error(t, err)
+	require.Equal(t, "StorageNode kube-test/node1 has wrong status.storage.totalSize, expected: 50Gi, actual: 100Gi", err.Error())
+
+	// TestCase: Details match the expected status
+	expected = &corev1.NodeStatus{
+		Network: corev1.NetworkStatus{DataIP: "10.0.0.1", MgmtIP: "10.0.0.2"},
+		Storage: corev1.StorageStatus{TotalSize: resource.MustParse("100Gi")},
+	}
+	err = ValidateStorageNodeStatusDetails(node, expected)
+	require.NoError(t, err)
+}
+
+func TestValidateOciMonitorFullCommand(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+	}
+	debugCommand := []string{"/px-oci-mon", "-c", "px-cluster", "-x", "kubernetes", "--log-level", "debug"}
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-pod",
+			Namespace: cluster.Namespace,
+			Labels:    map[string]string{"name": "portworx"},
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{
+					Name:    "portworx",
+					Command: []string{"/px-oci-mon"},
+					Args:    []string{"-c", "px-cluster", "-x", "kubernetes", "--log-level", "debug"},
+				},
+			},
+		},
+	}
+
+	// TestCase: No Portworx pods present
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset()))
+	err := ValidateOciMonitorFullCommand(cluster, debugCommand, time.Second, 100*time.Millisecond)
+	require.Error(t, err)
+
+	// TestCase: Custom debug command matches the container command and args
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(pod)))
+	err = ValidateOciMonitorFullCommand(cluster, debugCommand, time.Second, 100*time.Millisecond)
+	require.NoError(t, err)
+
+	// TestCase: Container is missing the debug args
+	pod.Spec.Containers[0].Args = []string{"-c", "px-cluster", "-x", "kubernetes"}
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(pod)))
+	err = ValidateOciMonitorFullCommand(cluster, debugCommand, time.Second, 100*time.Millisecond)
+	require.Error(t, err)
+}
+
+func TestDiffStringSlices(t *testing.T) {
+	// TestCase: Equal slices
+	require.Empty(t, diffStringSlices([]string{"a", "b"}, []string{"a", "b"}))
+	require.Empty(t, diffStringSlices(nil, []string{}))
+
+	// TestCase: Different values
+	require.Equal(t, `[1] expected "b", got "c"`,
+		diffStringSlices([]string{"a", "b"}, []string{"a", "c"}))
+
+	// TestCase: Missing and unexpected values
+	require.Equal(t, `[2] missing "--log-level", [3] missing "debug"`,
+		diffStringSlices([]string{"-c", "px", "--log-level", "debug"}, []string{"-c", "px"}))
+	require.Equal(t, `[0] expected "-c", got "-x", [1] unexpected "px"`,
+		diffStringSlices([]string{"-c"}, []string{"-x", "px"}))
+}
+
+func TestGetPullPolicy(t *testing.T) {
+	podSpec := &v1.PodSpec{
+		InitContainers: []v1.Container{
+			{Name: "px-init", ImagePullPolicy: v1.PullIfNotPresent},
+		},
+		Containers: []v1.Container{
+			{Name: "portworx", ImagePullPolicy: v1.PullAlways},
+		},
+	}
+
+	// TestCase: Regular container
+	require.Equal(t, v1.PullAlways, GetPullPolicy(podSpec, "portworx"))
+
+	// TestCase: Init container
+	require.Equal(t, v1.PullIfNotPresent, GetPullPolicy(podSpec, "px-init"))
+
+	// TestCase: Missing container
+	require.Empty(t, GetPullPolicy(podSpec, "other"))
+
+	// TestCase: Init container of a DaemonSet
+	daemonSet := &appsv1.DaemonSet{
+		Spec: appsv1.DaemonSetSpec{
+			Template: v1.PodTemplateSpec{Spec: *podSpec},
+		},
+	}
+	require.Equal(t, v1.PullIfNotPresent, GetPullPolicy(&daemonSet.Spec.Template.Spec, "px-init"))
+
+	// TestCase: Deployment lookup also covers init containers
+	deployment := &appsv1.Deployment{
+		Spec: appsv1.DeploymentSpec{
+			Template: v1.PodTemplateSpec{Spec: *podSpec},
+		},
+	}
+	require.Equal(t, v1.PullAlways, GetPullPolicyForContainer(deployment, "portworx"))
+	require.Equal(t, v1.PullIfNotPresent, GetPullPolicyForContainer(deployment, "px-init"))
+}
+
+func TestValidateComponentMetricsEndpoints(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+	}
+	collector := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-metrics-collector",
+			Namespace: cluster.Namespace,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						PrometheusScrapeAnnotation: "true",
+						PrometheusPortAnnotation:   "80",
+					},
+				},
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{
+						{
+							Name: "collector",
+							Ports: []v1.ContainerPort{
+								{
+									Name:          "collector",
+									ContainerPort: 80,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	componentPorts := map[string]int32{"px-metrics-collector": 80}
+	setInstance := func(objects ...runtime.Object) {
+		k8sClient := fakek8sclient.NewSimpleClientset(objects...)
+		appops.SetInstance(appops.New(k8sClient.AppsV1(), k8sClient.CoreV1()))
+	}
+
+	// TestCase: Collector exposes metrics port and scrape annotation
+	setInstance(collector)
+	err := ValidateComponentMetricsEndpoints(cluster, componentPorts, time.Second, 100*time.Millisecond)
+	require.NoError(t, err)
+
+	// TestCase: Collector deployment is missing
+	setInstance()
+	err = ValidateComponentMetricsEndpoints(cluster, componentPorts, time.Second, 100*time.Millisecond)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "kube-test/px-metrics-collector:80")
+
+	// TestCase: Collector does not expose the metrics port
+	setInstance(collector)
+	err = ValidateComponentMetricsEndpoints(cluster, map[string]int32{"px-metrics-collector": 9090}, time.Second, 100*time.Millisecond)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "kube-test/px-metrics-collector:9090")
+
+	// TestCase: Collector is missing the scrape annotation
+	noScrape := collector.DeepCopy()
+	delete(noScrape.Spec.Template.Annotations, PrometheusScrapeAnnotation)
+	setInstance(noScrape)
+	err = ValidateComponentMetricsEndpoints(cluster, componentPorts, time.Second, 100*time.Millisecond)
+	require.Error(t, err)
+
+	// TestCase: Collector port annotation points to a different port
+	wrongPort := collector.DeepCopy()
+	wrongPort.Spec.Template.Annotations[PrometheusPortAnnotation] = "8080"
+	setInstance(wrongPort)
+	err = ValidateComponentMetricsEndpoints(cluster, componentPorts, time.Second, 100*time.Millisecond)
+	require.Error(t, err)
+}
+
+func TestDiffStorageClusterSpec(t *testing.T) {
+	expected := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+		Spec: corev1.StorageClusterSpec{
+			Kvdb: &corev1.KvdbSpec{
+				Internal: true,
+			},
+			CloudStorage: &corev1.CloudStorageSpec{
+				CloudStorageCommon: corev1.CloudStorageCommon{
+					DeviceSpecs: &[]string{"type=gp2,size=100"},
+				},
+			},
+			Nodes: []corev1.NodeSpec{
+				{
+					Selector: corev1.NodeSelector{NodeName: "node1"},
+				},
+			},
+			CommonConfig: corev1.CommonConfig{
+				Env: []v1.EnvVar{{Name: "TEST_KEY", Value: "test-value"}},
+			},
+		},
+	}
+
+	// TestCase: Specs are equal
+	live := expected.DeepCopy()
+	require.Empty(t, DiffStorageClusterSpec(expected, live))
+	require.NoError(t, validateDeployedSpec(expected, live))
+
+	// TestCase: Defaulted placement and storage are ignored if not set in expected spec
+	live.Spec.Placement = &corev1.PlacementSpec{NodeAffinity: &v1.NodeAffinity{}}
+	live.Spec.Storage = &corev1.StorageSpec{UseAll: BoolPtr(true)}
+	require.Empty(t, DiffStorageClusterSpec(expected, live))
+
+	// TestCase: All mismatched fields are reported at once
+	live.Spec.Kvdb.Internal = false
+	live.Spec.CloudStorage.DeviceSpecs = &[]string{"type=gp2,size=200"}
+	live.Spec.Nodes = append(live.Spec.Nodes, corev1.NodeSpec{
+		Selector: corev1.NodeSelector{NodeName: "node2"},
+	})
+	live.Spec.Env = nil
+	live.Spec.Resources = &v1.ResourceRequirements{}
+	live.Spec.RuntimeOpts = map[string]string{"key": "value"}
+	expected.Spec.Placement = &corev1.PlacementSpec{}
+	diffs := DiffStorageClusterSpec(expected, live)
+	require.ElementsMatch(t, []string{
+		"spec.cloudStorage",
+		"spec.kvdb",
+		"spec.nodes[node2]",
+		"spec.resources",
+		"spec.placement",
+		"spec.env",
+		"spec.runtimeOptions",
+	}, diffs)
+
+	err := validateDeployedSpec(expected, live)
+	require.Error(t, err)
+	for _, diff := range diffs {
+		require.Contains(t, err.Error(), diff)
+	}
+
+	// TestCase: Node level cloud storage mismatch
+	live = expected.DeepCopy()
+	live.Spec.Nodes[0].CloudStorage = &corev1.CloudStorageNodeSpec{}
+	require.Equal(t, []string{"spec.nodes[node1].cloudStorage"}, DiffStorageClusterSpec(expected, live))
+}
+
+func TestValidatePortworxDaemonSet(t *testing.T) {
+	expected := GetExpectedDaemonSet(t, "ociMonitorDaemonSet.yaml")
+	live := expected.DeepCopy()
+	k8sClient := fakek8sclient.NewSimpleClientset(live)
+	appops.SetInstance(appops.New(k8sClient.AppsV1(), k8sClient.CoreV1()))
+
+	// TestCase: Live DaemonSet matches the expected one
+	require.Empty(t, DiffDaemonSetSpec(expected, live))
+	err := ValidatePortworxDaemonSet(expected, "kube-test", time.Second, 100*time.Millisecond)
+	require.NoError(t, err)
+
+	// TestCase: Volume mount at a different path
+	live.Spec.Template.Spec.Containers[0].VolumeMounts[2].MountPath = "/etc/pwx-other"
+	_, err = k8sClient.AppsV1().DaemonSets("kube-test").Update(context.TODO(), live, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	err = ValidatePortworxDaemonSet(expected, "kube-test", time.Second, 100*time.Millisecond)
+	require.Error(t, err)
+	require.Equal(t, "DaemonSet kube-test/portworx doesn't match expected, mismatched fields: "+
+		"spec.template.spec.containers[portworx].volumeMounts[etcpwx:/etc/pwx], "+
+		"spec.template.spec.containers[portworx].volumeMounts[etcpwx:/etc/pwx-other]", err.Error())
+
+	// TestCase: Order of volumes and volume mounts does not matter
+	live = expected.DeepCopy()
+	mounts := live.Spec.Template.Spec.Containers[0].VolumeMounts
+	mounts[0], mounts[2] = mounts[2], mounts[0]
+	volumes := live.Spec.Template.Spec.Volumes
+	volumes[0], volumes[1] = volumes[1], volumes[0]
+	require.Empty(t, DiffDaemonSetSpec(expected, live))
+
+	// TestCase: Same volume mounted more than once with different sub paths
+	expectedWithSubPaths := expected.DeepCopy()
+	expectedWithSubPaths.Spec.Template.Spec.Containers[0].VolumeMounts = append(
+		expectedWithSubPaths.Spec.Template.Spec.Containers[0].VolumeMounts,
+		v1.VolumeMount{Name: "etcpwx", MountPath: "/etc/pwx/config.json", SubPath: "config.json"},
+		v1.VolumeMount{Name: "etcpwx", MountPath: "/etc/pwx/config.json", SubPath: "other.json", ReadOnly: true},
+	)
+	liveWithSubPaths := expectedWithSubPaths.DeepCopy()
+	subPathMounts := liveWithSubPaths.Spec.Template.Spec.Containers[0].VolumeMounts
+	subPathMounts[3], subPathMounts[4] = subPathMounts[4], subPathMounts[3]
+	require.Empty(t, DiffDaemonSetSpec(expectedWithSubPaths, liveWithSubPaths))
+
+	subPathMounts[3].ReadOnly = false
+	require.Equal(t, []string{
+		"spec.template.spec.containers[portworx].volumeMounts[etcpwx:/etc/pwx/config.json:other.json]",
+	}, DiffDaemonSetSpec(expectedWithSubPaths, liveWithSubPaths))
+
+	// TestCase: All mismatched fields are reported at once
+	live.Spec.Template.Spec.Containers[0].Image = "docker.io/portworx/oci-monitor:2.11.0"
+	live.Spec.Template.Spec.Containers[0].Args = append(live.Spec.Template.Spec.Containers[0].Args, "-a")
+	live.Spec.Template.Spec.Containers[0].VolumeMounts = live.Spec.Template.Spec.Containers[0].VolumeMounts[1:]
+	live.Spec.Template.Spec.Volumes[0].HostPath.Path = "/run/docker.sock"
+	live.Spec.Template.Spec.Volumes = append(live.Spec.Template.Spec.Volumes, v1.Volume{Name: "extra"})
+	live.Spec.Template.Spec.Tolerations = nil
+	live.Spec.Template.Spec.Containers = append(live.Spec.Template.Spec.Containers, v1.Container{Name: "csi-node-driver-registrar"})
+	require.Equal(t, []string{
+		"spec.template.spec.containers[portworx].image",
+		"spec.template.spec.containers[portworx].args",
+		"spec.template.spec.containers[portworx].volumeMounts[etcpwx:/etc/pwx]",
+		"spec.template.spec.containers[csi-node-driver-registrar]",
+		"spec.template.spec.volumes[dockersock]",
+		"spec.template.spec.volumes[extra]",
+		"spec.template.spec.tolerations",
+	}, DiffDaemonSetSpec(expected, live))
+
+	// TestCase: DaemonSet does not exist
+	err = ValidatePortworxDaemonSet(expected, "other-namespace", time.Second, 100*time.Millisecond)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to get DaemonSet other-namespace/portworx")
+}
+
+func TestValidateSDKReachableOnMgmtInterface(t *testing.T) {
+	sdkServer, err := NewMockSDKServer()
+	require.NoError(t, err)
+	defer sdkServer.Stop()
+	sdkPort := sdkServer.Port()
+
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+	}
+	service := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "portworx-service",
+			Namespace: cluster.Namespace,
+		},
+		Spec: v1.ServiceSpec{
+			ClusterIP: "127.0.0.1",
+			Ports: []v1.ServicePort{
+				{
+					Name:       "px-sdk",
+					Port:       1,
+					TargetPort: intstr.FromInt(sdkPort),
+				},
+			},
+		},
+	}
+	k8sNode := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+		Status: v1.NodeStatus{
+			Addresses: []v1.NodeAddress{
+				{Type: v1.NodeInternalIP, Address: "127.0.0.2"},
+			},
+		},
+	}
+	storageNode := &corev1.StorageNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "node1",
+			Namespace: cluster.Namespace,
+		},
+		Status: corev1.NodeStatus{
+			Network: corev1.NetworkStatus{MgmtIP: "127.0.0.1"},
+		},
+	}
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(service, k8sNode)))
+	operatorops.SetInstance(operatorops.New(fakeoperatorclient.NewSimpleClientset(storageNode)))
+
+	// TestCase: Management interface is not configured
+	err = ValidateSDKReachableOnMgmtInterface(cluster)
+	require.Error(t, err)
+
+	addresses, err := getSdkNodeAddresses(cluster)
+	require.NoError(t, err)
+	require.Equal(t, []string{"127.0.0.2"}, addresses)
+
+	// TestCase: Management IPs are preferred over node internal IPs
+	mgmtInterface := "eth1"
+	cluster.Spec.Network = &corev1.NetworkSpec{MgmtInterface: &mgmtInterface}
+	addresses, err = getSdkNodeAddresses(cluster)
+	require.NoError(t, err)
+	require.Equal(t, []string{"127.0.0.1", "127.0.0.2"}, addresses)
+
+	// TestCase: SDK server is reachable on the management IP
+	err = ValidateSDKReachableOnMgmtInterface(cluster)
+	require.NoError(t, err)
+
+	// TestCase: Service endpoint is not reachable, so connection falls back to the management IP
+	conn, err := getSdkConnection(cluster)
+	require.NoError(t, err)
+	require.Equal(t, fmt.Sprintf("127.0.0.1:%d", sdkPort), conn.Target())
+	conn.Close()
+
+	// TestCase: SDK server is only reachable on an address other than the management IP
+	k8sNode.Status.Addresses[0].Address = "127.0.0.1"
+	storageNode.Status.Network.MgmtIP = "127.0.0.3"
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(service, k8sNode)))
+	operatorops.SetInstance(operatorops.New(fakeoperatorclient.NewSimpleClientset(storageNode)))
+	err = ValidateSDKReachableOnMgmtInterface(cluster)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "expected one of the management IPs")
+
+	// TestCase: StorageNodes do not report a management IP
+	storageNode.Status.Network.MgmtIP = ""
+	operatorops.SetInstance(operatorops.New(fakeoperatorclient.NewSimpleClientset(storageNode)))
+	err = ValidateSDKReachableOnMgmtInterface(cluster)
+	require.Error(t, err)
+}
+
+func TestValidateSDKReachableOnAllNodes(t *testing.T) {
+	// SDK server is only bound on 127.0.0.1, so it is not reachable on other node IPs
+	sdkServer, err := NewMockSDKServer()
+	require.NoError(t, err)
+	defer sdkServer.Stop()
+	sdkPort := sdkServer.Port()
+
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+	}
+	service := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "portworx-service",
+			Namespace: cluster.Namespace,
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{
+				{
+					Name:       "px-sdk",
+					Port:       1,
+					TargetPort: intstr.FromInt(sdkPort),
+				},
+			},
+		},
+	}
+	newNode := func(name, address string) *v1.Node {
+		return &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: v1.NodeStatus{
+				Addresses: []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: address}},
+			},
+		}
+	}
+	operatorops.SetInstance(operatorops.New(fakeoperatorclient.NewSimpleClientset()))
+
+	// TestCase: Service is missing
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset()))
+	_, err = ValidateSDKReachableOnAllNodes(cluster)
+	require.Error(t, err)
+
+	// TestCase: No nodes to connect to
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(service)))
+	_, err = ValidateSDKReachableOnAllNodes(cluster)
+	require.Error(t, err)
+
+	// TestCase: SDK server is reachable on all node IPs
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(service, newNode("node1", "127.0.0.1"))))
+	results, err := ValidateSDKReachableOnAllNodes(cluster)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.NoError(t, results["127.0.0.1"])
+
+	// TestCase: SDK server is only reachable on some of the node IPs
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(
+		service,
+		newNode("node1", "127.0.0.1"),
+		newNode("node2", "127.0.0.2"),
+	)))
+	results, err = ValidateSDKReachableOnAllNodes(cluster)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "node IPs [127.0.0.2], reachable on 1/2")
+	require.Len(t, results, 2)
+	require.NoError(t, results["127.0.0.1"])
+	require.Error(t, results["127.0.0.2"])
+}
+
+func TestValidateCloudStorageProvisioned(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockNodeClient := mock.NewMockOpenStorageNodeClient(mockCtrl)
+
+	clusterDeviceSpecs := []string{"type=gp2,size=150", "type=gp2,size=100"}
+	nodeDeviceSpecs := []string{"type=io1,size=200"}
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+		Spec: corev1.StorageClusterSpec{
+			CloudStorage: &corev1.CloudStorageSpec{
+				CloudStorageCommon: corev1.CloudStorageCommon{
+					DeviceSpecs: &clusterDeviceSpecs,
+				},
+			},
+			Nodes: []corev1.NodeSpec{
+				{
+					Selector: corev1.NodeSelector{NodeName: "node2"},
+					CloudStorage: &corev1.CloudStorageNodeSpec{
+						CloudStorageCommon: corev1.CloudStorageCommon{
+							DeviceSpecs: &nodeDeviceSpecs,
+						},
+					},
+				},
+			},
+		},
+	}
+	gib := uint64(1024 * 1024 * 1024)
+	newNode := func(name string, sizesInGiB ...uint64) *api.StorageNode {
+		node := &api.StorageNode{
+			Id:                name + "-id",
+			SchedulerNodeName: name,
+			Disks: map[string]*api.StorageResource{
+				"/dev/sdz": {Size: 32 * gib, Metadata: true},
+			},
+		}
+		for i, size := range sizesInGiB {
+			node.Disks[fmt.Sprintf("/dev/sd%c", 'a'+i)] = &api.StorageResource{Size: size * gib}
+		}
+		return node
+	}
+	expectNodes := func(nodes ...*api.StorageNode) {
+		var nodeIDs []string
+		for _, node := range nodes {
+			nodeIDs = append(nodeIDs, node.Id)
+			mockNodeClient.EXPECT().
+				Inspect(gomock.Any(), &api.SdkNodeInspectRequest{NodeId: node.Id}).
+				Return(&api.SdkNodeInspectResponse{Node: node}, nil)
+		}
+		mockNodeClient.EXPECT().
+			Enumerate(gomock.Any(), gomock.Any()).
+			Return(&api.SdkNodeEnumerateResponse{NodeIds: nodeIDs}, nil)
+	}
+
+	inZone := func(node *api.StorageNode, zone string) *api.StorageNode {
+		node.NodeLabels = map[string]string{v1.LabelTopologyZone: zone}
+		return node
+	}
+
+	// TestCase: Drives match the cluster and node specific device specs
+	expectNodes(
+		newNode("node1", 100, 150),
+		newNode("node2", 200),
+	)
+	err := validateCloudStorageProvisioned(cluster, mockNodeClient)
+	require.NoError(t, err)
+
+	// TestCase: Node without drives is reported when it should have storage
+	expectNodes(
+		newNode("node1", 100, 150),
+		newNode("node2", 200),
+		newNode("node3"),
+	)
+	err = validateCloudStorageProvisioned(cluster, mockNodeClient)
+	require.Error(t, err)
+	require.Equal(t, "failed to validate cloud storage drives: node [node3]: expected drives: 2, actual: 0", err.Error())
+
+	// TestCase: Node without drives is storageless once maxStorageNodes is reached
+	maxStorageNodes := uint32(2)
+	cluster.Spec.CloudStorage.MaxStorageNodes = &maxStorageNodes
+	expectNodes(
+		newNode("node1", 100, 150),
+		newNode("node2", 200),
+		newNode("node3"),
+	)
+	err = validateCloudStorageProvisioned(cluster, mockNodeClient)
+	require.NoError(t, err)
+
+	// TestCase: Node without drives is storageless only in zones that reach maxStorageNodesPerZone
+	cluster.Spec.CloudStorage.MaxStorageNodes = nil
+	maxStorageNodesPerZone := uint32(1)
+	cluster.Spec.CloudStorage.MaxStorageNodesPerZone = &maxStorageNodesPerZone
+	expectNodes(
+		inZone(newNode("node1", 100, 150), "zone-a"),
+		inZone(newNode("node3"), "zone-a"),
+		inZone(newNode("node4"), "zone-b"),
+	)
+	err = validateCloudStorageProvisioned(cluster, mockNodeClient)
+	require.Error(t, err)
+	require.Equal(t, "failed to validate cloud storage drives: node [node4]: expected drives: 2, actual: 0", err.Error())
+	cluster.Spec.CloudStorage.MaxStorageNodesPerZone = nil
+
+	// TestCase: Mismatched drive count and size
+	expectNodes(
+		newNode("node1", 150),
+		newNode("node2", 100),
+	)
+	err = validateCloudStorageProvisioned(cluster, mockNodeClient)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "node [node1]: expected drives: 2, actual: 1")
+	require.Contains(t, err.Error(), "node [node2]: expected drive sizes (GiB): [200], actual: [100]")
+
+	// TestCase: Node specific spec takes precedence over the cluster spec
+	expectNodes(newNode("node2", 100, 150))
+	err = validateCloudStorageProvisioned(cluster, mockNodeClient)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "node [node2]: expected drives: 1, actual: 2")
+
+	// TestCase: Device specs without a size only validate the drive count
+	clusterDeviceSpecs = []string{"type=gp2", "type=gp2"}
+	expectNodes(newNode("node1", 100, 150))
+	err = validateCloudStorageProvisioned(cluster, mockNodeClient)
+	require.NoError(t, err)
+}
+
+func TestValidateNodeStorageOverride(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockNodeClient := mock.NewMockOpenStorageNodeClient(mockCtrl)
+
+	clusterDeviceSpecs := []string{"type=gp2,size=150"}
+	nodeDeviceSpecs := []string{"type=io1,size=200", "type=io1,size=200"}
+	clusterSpec := &corev1.CloudStorageNodeSpec{
+		CloudStorageCommon: corev1.CloudStorageCommon{DeviceSpecs: &clusterDeviceSpecs},
+	}
+	overrideSpec := &corev1.CloudStorageNodeSpec{
+		CloudStorageCommon: corev1.CloudStorageCommon{DeviceSpecs: &nodeDeviceSpecs},
+	}
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+		Spec: corev1.StorageClusterSpec{
+			CloudStorage: &corev1.CloudStorageSpec{
+				CloudStorageCommon: clusterSpec.CloudStorageCommon,
+			},
+			Nodes: []corev1.NodeSpec{
+				{
+					Selector:     corev1.NodeSelector{NodeName: "node2"},
+					CloudStorage: overrideSpec,
+				},
+				{
+					Selector: corev1.NodeSelector{NodeName: "node3"},
+				},
+			},
+		},
+	}
+	gib := uint64(1024 * 1024 * 1024)
+	expectNode := func(name string, sizesInGiB ...uint64) {
+		node := &api.StorageNode{
+			Id:                name + "-id",
+			SchedulerNodeName: name,
+			Disks:             map[string]*api.StorageResource{},
+		}
+		for i, size := range sizesInGiB {
+			node.Disks[fmt.Sprintf("/dev/sd%c", 'a'+i)] = &api.StorageResource{Size: size * gib}
+		}
+		mockNodeClient.EXPECT().
+			Enumerate(gomock.Any(), gomock.Any()).
+			Return(&api.SdkNodeEnumerateResponse{NodeIds: []string{"other-id", node.Id}}, nil)
+		mockNodeClient.EXPECT().
+			Inspect(gomock.Any(), &api.SdkNodeInspectRequest{NodeId: "other-id"}).
+			Return(&api.SdkNodeInspectResponse{Node: &api.StorageNode{Id: "other-id", SchedulerNodeName: "other"}}, nil)
+		mockNodeClient.EXPECT().
+			Inspect(gomock.Any(), &api.SdkNodeInspectRequest{NodeId: node.Id}).
+			Return(&api.SdkNodeInspectResponse{Node: node}, nil)
+	}
+
+	// TestCase: Node with an override gets the override
+	expectNode("node2", 200, 200)
+	err := validateNodeStorageOverride(cluster, "node2", overrideSpec, mockNodeClient)
+	require.NoError(t, err)
+
+	err = validateNodeStorageOverride(cluster, "node2", clusterSpec, mockNodeClient)
+	require.Error(t, err)
+	require.Equal(t, "effective cloud storage spec of node node2 doesn't match expected, "+
+		"expected: deviceSpecs: [type=gp2,size=150], actual: deviceSpecs: [type=io1,size=200 type=io1,size=200]", err.Error())
+
+	// TestCase: Node without an override falls back to the cluster spec
+	expectNode("node1", 150)
+	err = validateNodeStorageOverride(cluster, "node1", clusterSpec, mockNodeClient)
+	require.NoError(t, err)
+
+	// TestCase: Node listed without cloud storage falls back to the cluster spec
+	expectNode("node3", 150)
+	err = validateNodeStorageOverride(cluster, "node3", clusterSpec, mockNodeClient)
+	require.NoError(t, err)
+
+	// TestCase: Live node does not match the override
+	expectNode("node2", 150)
+	err = validateNodeStorageOverride(cluster, "node2", overrideSpec, mockNodeClient)
+	require.Error(t, err)
+	require.Equal(t, "node [node2]: expected drives: 2, actual: 1", err.Error())
+
+	// TestCase: Live node has no drives although the override asks for storage
+	expectNode("node2")
+	err = validateNodeStorageOverride(cluster, "node2", overrideSpec, mockNodeClient)
+	require.Error(t, err)
+	require.Equal(t, "node [node2]: expected drives: 2, actual: 0", err.Error())
+
+	// TestCase: Live node is not found
+	mockNodeClient.EXPECT().
+		Enumerate(gomock.Any(), gomock.Any()).
+		Return(&api.SdkNodeEnumerateResponse{}, nil)
+	err = validateNodeStorageOverride(cluster, "node1", clusterSpec, mockNodeClient)
+	require.Error(t, err)
+	require.Equal(t, "failed to find Portworx node node1", err.Error())
+}
+
+func TestValidateStorageNodePoolStatus(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockNodeClient := mock.NewMockOpenStorageNodeClient(mockCtrl)
+
+	newPool := func(uuid string, status api.SdkStoragePool_OperationStatus) *api.StoragePool {
+		return &api.StoragePool{
+			Uuid: uuid,
+			LastOperation: &api.StoragePoolOperation{
+				Type:   api.SdkStoragePool_OPERATION_RESIZE,
+				Status: status,
+			},
+		}
+	}
+	newNodesResp := func(node2PoolStatus api.SdkStoragePool_OperationStatus) *api.SdkNodeEnumerateWithFiltersResponse {
+		return &api.SdkNodeEnumerateWithFiltersResponse{
+			Nodes: []*api.StorageNode{
+				{
+					SchedulerNodeName: "node1",
+					Pools: []*api.StoragePool{
+						newPool("pool-1", api.SdkStoragePool_OPERATION_SUCCESSFUL),
+						// Pool without any operation
+						{Uuid: "pool-2"},
+					},
+				},
+				{
+					SchedulerNodeName: "node2",
+					Pools:             []*api.StoragePool{newPool("pool-3", node2PoolStatus)},
+				},
+			},
+		}
+	}
+
+	// TestCase: Pool statuses converge to the expected status
+	gomock.InOrder(
+		mockNodeClient.EXPECT().
+			EnumerateWithFilters(gomock.Any(), gomock.Any()).
+			Return(newNodesResp(api.SdkStoragePool_OPERATION_PENDING), nil),
+		mockNodeClient.EXPECT().
+			EnumerateWithFilters(gomock.Any(), gomock.Any()).
+			Return(newNodesResp(api.SdkStoragePool_OPERATION_IN_PROGRESS), nil),
+		mockNodeClient.EXPECT().
+			EnumerateWithFilters(gomock.Any(), gomock.Any()).
+			Return(newNodesResp(api.SdkStoragePool_OPERATION_SUCCESSFUL), nil),
+	)
+	err := validateStorageNodePoolStatus(api.SdkStoragePool_OPERATION_SUCCESSFUL, mockNodeClient, time.Second, time.Millisecond)
+	require.NoError(t, err)
+
+	// TestCase: Pool is stuck in a different status, error names the lagging pool
+	mockNodeClient.EXPECT().
+		EnumerateWithFilters(gomock.Any(), gomock.Any()).
+		Return(newNodesResp(api.SdkStoragePool_OPERATION_FAILED), nil).
+		MinTimes(1)
+	err = validateStorageNodePoolStatus(api.SdkStoragePool_OPERATION_SUCCESSFUL, mockNodeClient, 50*time.Millisecond, 10*time.Millisecond)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "node [node2] pool [pool-3]: OPERATION_FAILED")
+	require.NotContains(t, err.Error(), "pool-1")
+	require.NotContains(t, err.Error(), "pool-2")
+}
+
+func TestValidatePortworxService(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+	}
+	service := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "portworx-service",
+			Namespace: cluster.Namespace,
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{
+				{Name: "px-api", Port: 9001, TargetPort: intstr.FromInt(9001)},
+				{Name: "px-sdk", Port: 9020, TargetPort: intstr.FromInt(9020)},
+				{Name: "px-rest-gateway", Port: 9021, TargetPort: intstr.FromInt(9021)},
+			},
+		},
+	}
+
+	// TestCase: Service is not present
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset()))
+	err := ValidatePortworxService(cluster)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to get service")
+
+	// TestCase: Service exposes all the expected ports
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(service)))
+	err = ValidatePortworxService(cluster)
+	require.NoError(t, err)
+
+	// TestCase: Service is missing the px-sdk port
+	noSdkService := service.DeepCopy()
+	noSdkService.Spec.Ports = append(noSdkService.Spec.Ports[:1], noSdkService.Spec.Ports[2:]...)
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(noSdkService)))
+	err = ValidatePortworxService(cluster)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "missing ports [px-sdk]")
+
+	// TestCase: Target ports are derived from a custom start port
+	startPort := uint32(10001)
+	cluster.Spec.StartPort = &startPort
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(service)))
+	err = ValidatePortworxService(cluster)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "px-api (target port 9001, expected 10001)")
+	require.Contains(t, err.Error(), "px-sdk (target port 9020, expected 10017)")
+	require.Contains(t, err.Error(), "px-rest-gateway (target port 9021, expected 10018)")
+
+	customPortService := service.DeepCopy()
+	customPortService.Spec.Ports[0].TargetPort = intstr.FromInt(10001)
+	customPortService.Spec.Ports[1].TargetPort = intstr.FromInt(10017)
+	customPortService.Spec.Ports[2].TargetPort = intstr.FromInt(10018)
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(customPortService)))
+	err = ValidatePortworxService(cluster)
+	require.NoError(t, err)
+}
+
+func TestValidatePortworxServiceType(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+	}
+	newService := func(name string, serviceType v1.ServiceType, annotations map[string]string) *v1.Service {
+		return &v1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   cluster.Namespace,
+				Annotations: annotations,
+			},
+			Spec: v1.ServiceSpec{Type: serviceType},
+		}
+	}
+	internalLBAnnotations := map[string]string{
+		"service.beta.kubernetes.io/aws-load-balancer-internal": "true",
+	}
+
+	// TestCase: Services are missing
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset()))
+	err := ValidatePortworxServiceType(cluster, v1.ServiceTypeClusterIP, nil)
+	require.Error(t, err)
+
+	// TestCase: Services are of type ClusterIP
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(
+		newService("portworx-service", v1.ServiceTypeClusterIP, nil),
+		newService("portworx-api", v1.ServiceTypeClusterIP, nil),
+	)))
+	err = ValidatePortworxServiceType(cluster, v1.ServiceTypeClusterIP, nil)
+	require.NoError(t, err)
+
+	err = ValidatePortworxServiceType(cluster, v1.ServiceTypeLoadBalancer, internalLBAnnotations)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "service [portworx-service] has type ClusterIP, expected: LoadBalancer")
+	require.Contains(t, err.Error(), "service [portworx-api] is missing annotations [service.beta.kubernetes.io/aws-load-balancer-internal=true]")
+
+	// TestCase: Services are internal load balancers
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(
+		newService("portworx-service", v1.ServiceTypeLoadBalancer, internalLBAnnotations),
+		newService("portworx-api", v1.ServiceTypeLoadBalancer, internalLBAnnotations),
+	)))
+	err = ValidatePortworxServiceType(cluster, v1.ServiceTypeLoadBalancer, internalLBAnnotations)
+	require.NoError(t, err)
+
+	// TestCase: Only one service is a load balancer without the internal annotation
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(
+		newService("portworx-service", v1.ServiceTypeLoadBalancer, internalLBAnnotations),
+		newService("portworx-api", v1.ServiceTypeClusterIP, nil),
+	)))
+	err = ValidatePortworxServiceType(cluster, v1.ServiceTypeLoadBalancer, internalLBAnnotations)
+	require.Error(t, err)
+	require.NotContains(t, err.Error(), "portworx-service")
+	require.Contains(t, err.Error(), "service [portworx-api] has type ClusterIP, expected: LoadBalancer")
+	require.Contains(t, err.Error(), "service [portworx-api] is missing annotations")
+}
+
+func TestValidateStorageClasses(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+	}
+	newStorageClass := func(name string, params map[string]string) *storagev1.StorageClass {
+		return &storagev1.StorageClass{
+			ObjectMeta:           metav1.ObjectMeta{Name: name},
+			Provisioner:          "kubernetes.io/portworx-volume",
+			Parameters:           params,
+			AllowVolumeExpansion: BoolPtr(true),
+		}
+	}
+	expected := []*storagev1.StorageClass{
+		newStorageClass("px-db", map[string]string{"repl": "3", "io_profile": "db_remote"}),
+		newStorageClass("px-replicated", map[string]string{"repl": "2"}),
+	}
+
+	// TestCase: StorageClasses are missing
+	storageops.SetInstance(storageops.New(fakek8sclient.NewSimpleClientset().StorageV1()))
+	err := ValidateStorageClasses(cluster, expected)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to get StorageClass px-db")
+	require.Contains(t, err.Error(), "failed to get StorageClass px-replicated")
+
+	// TestCase: StorageClasses match the expected ones
+	storageops.SetInstance(storageops.New(fakek8sclient.NewSimpleClientset(
+		expected[0].DeepCopy(), expected[1].DeepCopy(),
+	).StorageV1()))
+	err = ValidateStorageClasses(cluster, expected)
+	require.NoError(t, err)
+
+	// TestCase: StorageClass is missing a parameter
+	pxDB := newStorageClass("px-db", map[string]string{"repl": "3"})
+	storageops.SetInstance(storageops.New(fakek8sclient.NewSimpleClientset(pxDB, expected[1].DeepCopy()).StorageV1()))
+	err = ValidateStorageClasses(cluster, expected)
+	require.Error(t, err)
+	require.Equal(t, "failed to validate StorageClasses: StorageClass [px-db] is missing parameter io_profile", err.Error())
+
+	// TestCase: StorageClass has a different parameter value or an unexpected parameter
+	err = validateStorageClass(expected[1], newStorageClass("px-replicated", map[string]string{"repl": "3"}))
+	require.Error(t, err)
+	require.Equal(t, "StorageClass [px-replicated] has wrong parameter repl, expected: 2, actual: 3", err.Error())
+
+	err = validateStorageClass(expected[1], newStorageClass("px-replicated", map[string]string{"repl": "2", "secure": "true"}))
+	require.Error(t, err)
+	require.Equal(t, "StorageClass [px-replicated] has unexpected parameter secure=true", err.Error())
+
+	// TestCase: StorageClass has a different provisioner or volume expansion
+	csiSC := expected[1].DeepCopy()
+	csiSC.Provisioner = "pxd.portworx.com"
+	err = validateStorageClass(expected[1], csiSC)
+	require.Error(t, err)
+	require.Equal(t, "StorageClass [px-replicated] has wrong provisioner, expected: kubernetes.io/portworx-volume, actual: pxd.portworx.com", err.Error())
+
+	noExpansionSC := expected[1].DeepCopy()
+	noExpansionSC.AllowVolumeExpansion = nil
+	err = validateStorageClass(expected[1], noExpansionSC)
+	require.Error(t, err)
+	require.Equal(t, "StorageClass [px-replicated] has wrong allowVolumeExpansion, expected: true, actual: <nil>", err.Error())
+
+	// TestCase: Default StorageClasses are disabled
+	cluster.Annotations = map[string]string{"portworx.io/disable-storage-class": "true"}
+	err = ValidateStorageClasses(cluster, expected)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "default StorageClasses are disabled")
+}
+
+func TestValidateComponentDisabledByAnnotation(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+	}
+	name := "portworx-pvc-controller"
+	k8sClient := fakek8sclient.NewSimpleClientset(
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: cluster.Namespace}},
+		&v1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: cluster.Namespace}},
+		&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: name}},
+		&rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: name}},
+		&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "px-db"}},
+	)
+	coreops.SetInstance(coreops.New(k8sClient))
+	appops.SetInstance(appops.New(k8sClient.AppsV1(), k8sClient.CoreV1()))
+	rbacops.SetInstance(rbacops.New(k8sClient.RbacV1()))
+	storageops.SetInstance(storageops.New(k8sClient.StorageV1()))
+
+	// TestCase: Unknown component
+	err := ValidateComponentDisabledByAnnotation(cluster, "unknown", time.Second, 100*time.Millisecond)
+	require.Error(t, err)
+
+	// TestCase: Disable annotation is not set
+	err = ValidateComponentDisabledByAnnotation(cluster, ComponentPVCController, time.Second, 100*time.Millisecond)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is not disabled")
+
+	// TestCase: Annotation enables the component
+	cluster.Annotations = map[string]string{"portworx.io/pvc-controller": "true"}
+	err = ValidateComponentDisabledByAnnotation(cluster, ComponentPVCController, time.Second, 100*time.Millisecond)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is not disabled")
+
+	// TestCase: Component is disabled, but its resources are still present
+	cluster.Annotations["portworx.io/pvc-controller"] = "false"
+	err = ValidateComponentDisabledByAnnotation(cluster, ComponentPVCController, time.Second, 100*time.Millisecond)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Deployment kube-test/portworx-pvc-controller is still present")
+
+	// TestCase: Only cluster scoped resources are still present
+	err = k8sClient.AppsV1().Deployments(cluster.Namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+	require.NoError(t, err)
+	err = k8sClient.CoreV1().ServiceAccounts(cluster.Namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+	require.NoError(t, err)
+	err = ValidateComponentDisabledByAnnotation(cluster, ComponentPVCController, time.Second, 100*time.Millisecond)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "ClusterRole portworx-pvc-controller is still present")
+
+	// TestCase: All component resources are removed
+	err = k8sClient.RbacV1().ClusterRoles().Delete(context.TODO(), name, metav1.DeleteOptions{})
+	require.NoError(t, err)
+	err = k8sClient.RbacV1().ClusterRoleBindings().Delete(context.TODO(), name, metav1.DeleteOptions{})
+	require.NoError(t, err)
+	err = ValidateComponentDisabledByAnnotation(cluster, ComponentPVCController, time.Second, 100*time.Millisecond)
+	require.NoError(t, err)
+
+	// TestCase: StorageClasses are disabled, but one is still present
+	cluster.Annotations["portworx.io/disable-storage-class"] = "true"
+	err = ValidateComponentDisabledByAnnotation(cluster, ComponentStorageClass, time.Second, 100*time.Millisecond)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "StorageClass px-db is still present")
+
+	// TestCase: All StorageClasses are removed
+	err = k8sClient.StorageV1().StorageClasses().Delete(context.TODO(), "px-db", metav1.DeleteOptions{})
+	require.NoError(t, err)
+	err = ValidateComponentDisabledByAnnotation(cluster, ComponentStorageClass, time.Second, 100*time.Millisecond)
+	require.NoError(t, err)
+}
+
+func TestValidateStorageClusterPodsWithThreshold(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+			UID:       "px-cluster-uid",
+		},
+	}
+	var objects []runtime.Object
+	var expectedNodes []string
+	for i := 1; i <= 10; i++ {
+		nodeName := fmt.Sprintf("node%d", i)
+		expectedNodes = append(expectedNodes, nodeName)
+		objects = append(objects,
+			&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeName}},
+			&v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "px-pod-" + nodeName,
+					Namespace: cluster.Namespace,
+					OwnerReferences: []metav1.OwnerReference{
+						{UID: cluster.UID},
+					},
+				},
+				Spec: v1.PodSpec{NodeName: nodeName},
+				Status: v1.PodStatus{
+					Phase: v1.PodRunning,
+					ContainerStatuses: []v1.ContainerStatus{
+						{
+							Name:  "portworx",
+							Ready: true,
+							State: v1.ContainerState{Running: &v1.ContainerStateRunning{}},
+						},
+					},
+				},
+			},
+		)
+	}
+	// One pod is intentionally not ready
+	notReadyPod := objects[len(objects)-1].(*v1.Pod)
+	notReadyPod.Status.ContainerStatuses[0].Ready = false
+
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(objects...)))
+	operatorops.SetInstance(operatorops.New(fakeoperatorclient.NewSimpleClientset(cluster)))
+	podTestFn := func(pod v1.Pod) bool {
+		return coreops.Instance().IsPodReady(pod)
+	}
+
+	// TestCase: Default exact match fails when one pod is not ready
+	err := validateStorageClusterPods(cluster, expectedNodes, time.Second, 100*time.Millisecond, podTestFn)
+	require.Error(t, err)
+
+	// TestCase: 90% threshold passes when one of ten pods is not ready
+	err = validateStorageClusterPodsWithThreshold(context.TODO(), cluster, expectedNodes, time.Second, 100*time.Millisecond, podTestFn, 90)
+	require.NoError(t, err)
+
+	err = ValidateStorageClusterPodsWithThreshold(cluster, 90, time.Second, 100*time.Millisecond)
+	require.NoError(t, err)
+
+	// TestCase: 95% threshold fails when one of ten pods is not ready
+	err = ValidateStorageClusterPodsWithThreshold(cluster, 95, time.Second, 100*time.Millisecond)
+	require.Error(t, err)
+
+	// TestCase: Threshold passes but a pod runs on an unexpected node
+	err = validateStorageClusterPodsWithThreshold(context.TODO(), cluster, expectedNodes[1:], time.Second, 100*time.Millisecond, podTestFn, 50)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "found Portworx pod on node node1")
+
+	// TestCase: Invalid threshold
+	err = ValidateStorageClusterPodsWithThreshold(cluster, 0, time.Second, 100*time.Millisecond)
+	require.Error(t, err)
+	err = ValidateStorageClusterPodsWithThreshold(cluster, 101, time.Second, 100*time.Millisecond)
+	require.Error(t, err)
+
+	// TestCase: Default exact match passes when all pods are ready
+	notReadyPod.Status.ContainerStatuses[0].Ready = true
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(objects...)))
+	err = validateStorageClusterPods(cluster, expectedNodes, time.Second, 100*time.Millisecond, podTestFn)
+	require.NoError(t, err)
+}
+
+func TestValidatePortworxRollingUpdateComplete(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+			UID:       "px-cluster-uid",
+		},
+	}
+	ownerRef := metav1.NewControllerRef(cluster, corev1.SchemeGroupVersion.WithKind("StorageCluster"))
+	newRevision := func(hash string, revision int64) *appsv1.ControllerRevision {
+		return &appsv1.ControllerRevision{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "px-cluster-" + hash,
+				Namespace:       cluster.Namespace,
+				Labels:          map[string]string{appsv1.ControllerRevisionHashLabelKey: hash},
+				OwnerReferences: []metav1.OwnerReference{*ownerRef},
+			},
+			Revision: revision,
+		}
+	}
+	newPod := func(name, hash string, ready bool) *v1.Pod {
+		readyStatus := v1.ConditionFalse
+		if ready {
+			readyStatus = v1.ConditionTrue
+		}
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       cluster.Namespace,
+				Labels:          map[string]string{appsv1.ControllerRevisionHashLabelKey: hash},
+				OwnerReferences: []metav1.OwnerReference{*ownerRef},
+			},
+			Status: v1.PodStatus{
+				Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: readyStatus}},
+			},
+		}
+	}
+
+	// TestCase: No revision created for the cluster yet
+	k8sClient := FakeK8sClient(newPod("px-pod-1", "old", true))
+	err := ValidatePortworxRollingUpdateComplete(k8sClient, cluster, time.Second, 100*time.Millisecond)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no controller revision found for StorageCluster kube-test/px-cluster")
+
+	// TestCase: Rolling update in progress, one pod updated, one pod still on the old
+	// revision and one updated pod not ready yet
+	otherPod := newPod("other-pod", "old", true)
+	otherPod.OwnerReferences = nil
+	k8sClient = FakeK8sClient(
+		newRevision("old", 1),
+		newRevision("new", 2),
+		newPod("px-pod-1", "new", true),
+		newPod("px-pod-2", "old", true),
+		newPod("px-pod-3", "new", false),
+		otherPod,
+	)
+	err = ValidatePortworxRollingUpdateComplete(k8sClient, cluster, time.Second, 100*time.Millisecond)
+	require.Error(t, err)
+	require.Equal(t, "failed to validate rolling update of StorageCluster kube-test/px-cluster, updated: 1/3", err.Error())
+
+	// TestCase: Rolling update complete
+	k8sClient = FakeK8sClient(
+		newRevision("old", 1),
+		newRevision("new", 2),
+		newPod("px-pod-1", "new", true),
+		newPod("px-pod-2", "new", true),
+		newPod("px-pod-3", "new", true),
+		otherPod,
+	)
+	err = ValidatePortworxRollingUpdateComplete(k8sClient, cluster, time.Second, 100*time.Millisecond)
+	require.NoError(t, err)
+}
+
+func TestValidateStorageClusterImages(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+	}
+	pxImageList := map[string]string{"version": "portworx/oci-monitor:2.10.1"}
+	newPod := func(image string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "px-pod",
+				Namespace: cluster.Namespace,
+				Labels:    map[string]string{"name": "portworx"},
+			},
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{
+					{Name: "portworx", Image: image},
+					{Name: "csi-node-driver-registrar", Image: "quay.io/k8scsi/csi-node-driver-registrar:v1.1.0"},
+				},
+			},
+		}
+	}
+
+	// TestCase: No Portworx pods present
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset()))
+	err := ValidateStorageClusterImages(pxImageList, cluster)
+	require.Error(t, err)
+
+	// TestCase: Portworx pods run the image from the image list
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(newPod("portworx/oci-monitor:2.10.1"))))
+	err = ValidateStorageClusterImages(pxImageList, cluster)
+	require.NoError(t, err)
+
+	// TestCase: Portworx pods run a different image
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(newPod("portworx/oci-monitor:2.10.0"))))
+	err = ValidateStorageClusterImages(pxImageList, cluster)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "expected: portworx/oci-monitor:2.10.1, actual: portworx/oci-monitor:2.10.0")
+
+	// TestCase: PX_IMAGE env variable overrides the image from the image list
+	cluster.Spec.Env = []v1.EnvVar{{Name: PxImageEnvVarName, Value: "portworx/oci-monitor:2.10.0"}}
+	err = ValidateStorageClusterImages(pxImageList, cluster)
+	require.NoError(t, err)
+
+	// TestCase: Custom image registry is prefixed to the image
+	cluster.Spec.Env = nil
+	cluster.Spec.CustomImageRegistry = "registry.airgapped.io:5000"
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(newPod("portworx/oci-monitor:2.10.1"))))
+	err = ValidateStorageClusterImages(pxImageList, cluster)
+	require.Error(t, err)
+
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(newPod("registry.airgapped.io:5000/portworx/oci-monitor:2.10.1"))))
+	err = ValidateStorageClusterImages(pxImageList, cluster)
+	require.NoError(t, err)
+
+	// TestCase: Custom image registry is prefixed to the PX_IMAGE override
+	cluster.Spec.Env = []v1.EnvVar{{Name: PxImageEnvVarName, Value: "docker.io/portworx/oci-monitor:2.10.0"}}
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(newPod("registry.airgapped.io:5000/portworx/oci-monitor:2.10.0"))))
+	err = ValidateStorageClusterImages(pxImageList, cluster)
+	require.NoError(t, err)
+}
+
+func TestValidatePxImageOverride(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+	}
+	newPod := func(image string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "px-pod",
+				Namespace: cluster.Namespace,
+				Labels:    map[string]string{"name": "portworx"},
+			},
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{{Name: "portworx", Image: image}},
+			},
+		}
+	}
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(newPod("portworx/oci-monitor:2.10.1"))))
+
+	// TestCase: No PX_IMAGE override, nothing to validate
+	err := ValidatePxImageOverride(cluster)
+	require.NoError(t, err)
+
+	// TestCase: Portworx pod runs a different image than the override
+	cluster.Spec.Env = []v1.EnvVar{{Name: PxImageEnvVarName, Value: "portworx/oci-monitor:custom-build"}}
+	err = ValidatePxImageOverride(cluster)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "expected: portworx/oci-monitor:custom-build, actual: portworx/oci-monitor:2.10.1")
+
+	// TestCase: Portworx pod runs the override image
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(newPod("portworx/oci-monitor:custom-build"))))
+	err = ValidatePxImageOverride(cluster)
+	require.NoError(t, err)
+
+	// TestCase: Override is set to an empty image
+	cluster.Spec.Env = []v1.EnvVar{{Name: PxImageEnvVarName}}
+	err = ValidatePxImageOverride(cluster)
+	require.Error(t, err)
+}
+
+func TestValidatePortworxHostPorts(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+	}
+	hostNetworkPod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-pod-1",
+			Namespace: cluster.Namespace,
+			Labels:    map[string]string{"name": "portworx"},
+		},
+		Spec: v1.PodSpec{
+			HostNetwork: true,
+			Containers: []v1.Container{
+				{
+					Name: "portworx",
+					Ports: []v1.ContainerPort{
+						{ContainerPort: 9001},
+						{ContainerPort: 9020, HostPort: 9020},
+					},
+				},
+			},
+		},
+	}
+	podNetworkPod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-pod-2",
+			Namespace: cluster.Namespace,
+			Labels:    map[string]string{"name": "portworx"},
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{
+					Name: "portworx",
+					Ports: []v1.ContainerPort{
+						{ContainerPort: 9001, HostPort: 9001},
+						{ContainerPort: 9020, HostPort: 9020},
+					},
+				},
+			},
+		},
+	}
+
+	// TestCase: No Portworx pods are present
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset()))
+	err := ValidatePortworxHostPorts(cluster, []int32{9001})
+	require.Error(t, err)
+
+	// TestCase: All pods bind the expected host ports
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(hostNetworkPod, podNetworkPod)))
+	err = ValidatePortworxHostPorts(cluster, []int32{9001, 9020})
+	require.NoError(t, err)
+
+	// TestCase: Pods are missing an expected host port
+	err = ValidatePortworxHostPorts(cluster, []int32{9001, 9021})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "pod [px-pod-1] (hostNetwork: true) is missing host ports [9021]")
+	require.Contains(t, err.Error(), "pod [px-pod-2] (hostNetwork: false) is missing host ports [9021]")
+
+	// TestCase: Container port without a host port is not bound outside the host network
+	podNetworkPod.Spec.Containers[0].Ports[0].HostPort = 0
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(hostNetworkPod, podNetworkPod)))
+	err = ValidatePortworxHostPorts(cluster, []int32{9001, 9020})
+	require.Error(t, err)
+	require.NotContains(t, err.Error(), "px-pod-1")
+	require.Contains(t, err.Error(), "pod [px-pod-2] (hostNetwork: false) is missing host ports [9001]")
+}
+
+func TestValidateStorageClusterAnnotationsPropagated(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+	}
+	expectedAnnotations := map[string]string{"portworx.io/pvc-controller": "true", "custom-annotation": "value"}
+	expectedLabels := map[string]string{"custom-label": "value"}
+	pod1 := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "px-pod-1",
+			Namespace:   cluster.Namespace,
+			Labels:      map[string]string{"name": "portworx", "custom-label": "value"},
+			Annotations: map[string]string{"portworx.io/pvc-controller": "true", "custom-annotation": "value"},
+		},
+	}
+	pod2 := pod1.DeepCopy()
+	pod2.Name = "px-pod-2"
+
+	// TestCase: No Portworx pods are present
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset()))
+	err := ValidateStorageClusterAnnotationsPropagated(cluster, expectedAnnotations, expectedLabels)
+	require.Error(t, err)
+
+	// TestCase: All pods have the expected annotations and labels
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(pod1, pod2)))
+	err = ValidateStorageClusterAnnotationsPropagated(cluster, expectedAnnotations, expectedLabels)
+	require.NoError(t, err)
+
+	// TestCase: Pods are missing an annotation or have a different value
+	delete(pod1.Annotations, "custom-annotation")
+	pod2.Annotations["portworx.io/pvc-controller"] = "false"
+	delete(pod2.Labels, "custom-label")
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(pod1, pod2)))
+	err = ValidateStorageClusterAnnotationsPropagated(cluster, expectedAnnotations, expectedLabels)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "pod [px-pod-1] is missing annotations [custom-annotation=value]")
+	require.NotContains(t, err.Error(), "pod [px-pod-1] is missing labels")
+	require.Contains(t, err.Error(), "pod [px-pod-2] is missing annotations [portworx.io/pvc-controller=true]")
+	require.Contains(t, err.Error(), "pod [px-pod-2] is missing labels [custom-label=value]")
+}
+
+func TestValidateMetadataPropagation(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+			UID:       "cluster-uid",
+		},
+		Spec: corev1.StorageClusterSpec{
+			Metadata: &corev1.Metadata{
+				Labels: map[string]map[string]string{
+					"deployment/stork": {"cost-center": "storage"},
+					"pod/storage":      {"cost-center": "storage"},
+				},
+				Annotations: map[string]map[string]string{
+					"service/portworx-api": {"custom-annotation": "value"},
+				},
+			},
+		},
+	}
+	ownerRefs := []metav1.OwnerReference{{Name: cluster.Name, UID: cluster.UID}}
+	storkDeployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "stork",
+			Namespace:       cluster.Namespace,
+			OwnerReferences: ownerRefs,
+			Labels:          map[string]string{"cost-center": "storage"},
+		},
+	}
+	apiService := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "portworx-api",
+			Namespace:       cluster.Namespace,
+			OwnerReferences: ownerRefs,
+			Annotations:     map[string]string{"custom-annotation": "value"},
+		},
+	}
+	pxPod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "px-pod-1",
+			Namespace:       cluster.Namespace,
+			OwnerReferences: ownerRefs,
+			Labels:          map[string]string{"name": "portworx", "cost-center": "storage"},
+		},
+	}
+	// Not owned by the cluster, so the custom metadata is not expected
+	unmanagedDeployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "stork",
+			Namespace: "other-ns",
+		},
+	}
+	kinds := func() []client.ObjectList {
+		return []client.ObjectList{&appsv1.DeploymentList{}, &v1.ServiceList{}, &v1.PodList{}}
+	}
+
+	// TestCase: Metadata is propagated to all managed objects
+	k8sClient := FakeK8sClient(storkDeployment, apiService, pxPod, unmanagedDeployment)
+	err := ValidateMetadataPropagation(k8sClient, cluster, kinds())
+	require.NoError(t, err)
+
+	// TestCase: Deployment is missing a propagated label
+	storkDeployment.Labels = nil
+	k8sClient = FakeK8sClient(storkDeployment, apiService, pxPod)
+	err = ValidateMetadataPropagation(k8sClient, cluster, kinds())
+	require.Error(t, err)
+	require.Equal(t, "failed to validate metadata propagation: "+
+		"deployment/stork [stork] is missing labels [cost-center=storage]", err.Error())
+
+	// TestCase: Service and Portworx pod are missing the propagated metadata
+	apiService.Annotations = nil
+	pxPod.Labels = map[string]string{"name": "portworx"}
+	k8sClient = FakeK8sClient(storkDeployment, apiService, pxPod)
+	err = ValidateMetadataPropagation(k8sClient, cluster, kinds())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "service/portworx-api [portworx-api] is missing annotations [custom-annotation=value]")
+	require.Contains(t, err.Error(), "pod/storage [px-pod-1] is missing labels [cost-center=storage]")
+}
+
+func TestValidateReleaseManifestURL(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+	}
+	manifestURL := "https://install.portworx.com/2.10/version"
+	newPod := func(name string, env []v1.EnvVar) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: cluster.Namespace,
+				Labels:    map[string]string{"name": "portworx"},
+			},
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{
+					{Name: "portworx", Env: env},
+					{Name: "csi-node-driver-registrar"},
+				},
+			},
+		}
+	}
+	manifestEnv := []v1.EnvVar{{Name: PxReleaseManifestURLEnvVarName, Value: manifestURL}}
+
+	// TestCase: No Portworx pods are present
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset()))
+	err := ValidateReleaseManifestURL(cluster, manifestURL)
+	require.Error(t, err)
+
+	// TestCase: All pods have the expected env var
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(
+		newPod("px-pod-1", manifestEnv),
+		newPod("px-pod-2", manifestEnv),
+	)))
+	err = ValidateReleaseManifestURL(cluster, manifestURL)
+	require.NoError(t, err)
+
+	// TestCase: Pod is missing the env var or has a different value
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(
+		newPod("px-pod-1", nil),
+		newPod("px-pod-2", []v1.EnvVar{{Name: PxReleaseManifestURLEnvVarName, Value: "https://edge-install.portworx.com/version"}}),
+	)))
+	err = ValidateReleaseManifestURL(cluster, manifestURL)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "pod [px-pod-1]: env var is missing in container portworx")
+	require.Contains(t, err.Error(), "pod [px-pod-2]: expected: "+manifestURL+", actual: https://edge-install.portworx.com/version")
+
+	// TestCase: Env var is only set on a different container
+	pod := newPod("px-pod-1", nil)
+	pod.Spec.Containers[1].Env = manifestEnv
+	err = validatePodReleaseManifestURL(*pod, manifestURL)
+	require.Error(t, err)
+	require.Equal(t, "pod [px-pod-1]: env var is missing in container portworx", err.Error())
+}
+
+func TestValidatePortworxEnv(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+	}
+	nodeNameEnv := v1.EnvVar{
+		Name: "NODE_NAME",
+		ValueFrom: &v1.EnvVarSource{
+			FieldRef: &v1.ObjectFieldSelector{
+				APIVersion: "v1",
+				FieldPath:  "spec.nodeName",
+			},
+		},
+	}
+	operatorEnv := []v1.EnvVar{
+		{Name: "PX_NAMESPACE", Value: cluster.Namespace},
+		{Name: "PX_SECRETS_NAMESPACE", Value: cluster.Namespace},
+		nodeNameEnv,
+		{Name: PxReleaseManifestURLEnvVarName, Value: "https://install.portworx.com/2.10/version"},
+	}
+	userEnv := v1.EnvVar{Name: "CUSTOM_ENV", Value: "custom-value"}
+	newPod := func(name string, env []v1.EnvVar) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: cluster.Namespace,
+				Labels:    map[string]string{"name": "portworx"},
+			},
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{{Name: "portworx", Env: env}},
+			},
+		}
+	}
+	expectedEnv := []v1.EnvVar{userEnv, operatorEnv[3]}
+
+	// TestCase: No Portworx pods are present
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset()))
+	err := ValidatePortworxEnv(cluster, expectedEnv)
+	require.Error(t, err)
+
+	// TestCase: User and operator env vars are both present
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(
+		newPod("px-pod-1", append([]v1.EnvVar{userEnv}, operatorEnv...)),
+	)))
+	err = ValidatePortworxEnv(cluster, expectedEnv)
+	require.NoError(t, err)
+
+	// TestCase: User env var replaced the operator env vars
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(
+		newPod("px-pod-1", []v1.EnvVar{userEnv}),
+	)))
+	err = ValidatePortworxEnv(cluster, expectedEnv)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "NODE_NAME is missing")
+	require.Contains(t, err.Error(), "PX_NAMESPACE is missing")
+	require.Contains(t, err.Error(), PxReleaseManifestURLEnvVarName+" is missing")
+	require.NotContains(t, err.Error(), "CUSTOM_ENV")
+
+	// TestCase: Operator env vars dropped the user env var
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(
+		newPod("px-pod-1", operatorEnv),
+	)))
+	err = ValidatePortworxEnv(cluster, expectedEnv)
+	require.Error(t, err)
+	require.Equal(t, "failed to validate env vars on Portworx pods: pod [px-pod-1]: CUSTOM_ENV is missing", err.Error())
+
+	// TestCase: User env var overrides an operator default
+	override := v1.EnvVar{Name: "PX_SECRETS_NAMESPACE", Value: "secrets-ns"}
+	err = ValidatePortworxEnv(cluster, append(expectedEnv, override))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "PX_SECRETS_NAMESPACE expected")
+
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(
+		newPod("px-pod-1", append([]v1.EnvVar{userEnv, override}, operatorEnv[0], operatorEnv[2], operatorEnv[3])),
+	)))
+	err = ValidatePortworxEnv(cluster, append(expectedEnv, override))
+	require.NoError(t, err)
+}
+
+func TestValidatePxNodeAffinity(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+	}
+	newPod := func(name string, nodeAffinity *v1.NodeAffinity) *v1.Pod {
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: cluster.Namespace,
+				Labels:    map[string]string{"name": "portworx"},
+			},
+		}
+		if nodeAffinity != nil {
+			pod.Spec.Affinity = &v1.Affinity{NodeAffinity: nodeAffinity}
+		}
+		return pod
+	}
+	fakeClient := fakek8sclient.NewSimpleClientset()
+	fakeClient.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &k8sversion.Info{
+		GitVersion: "v1.21.0",
+	}
+
+	// TestCase: No Portworx pods are present
+	coreops.SetInstance(coreops.New(fakeClient))
+	err := ValidatePxNodeAffinity(cluster)
+	require.Error(t, err)
+
+	// TestCase: Pods carry the default node affinity along with additional terms
+	operatorDefault := &v1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{
+			NodeSelectorTerms: []v1.NodeSelectorTerm{
+				{
+					MatchExpressions: []v1.NodeSelectorRequirement{
+						{Key: "px/enabled", Operator: v1.NodeSelectorOpNotIn, Values: []string{"false"}},
+						{Key: "node-role.kubernetes.io/master", Operator: v1.NodeSelectorOpDoesNotExist},
+					},
+				},
+				{
+					MatchExpressions: []v1.NodeSelectorRequirement{
+						{Key: "px/enabled", Operator: v1.NodeSelectorOpNotIn, Values: []string{"false"}},
+						{Key: "node-role.kubernetes.io/master", Operator: v1.NodeSelectorOpExists},
+						{Key: "node-role.kubernetes.io/worker", Operator: v1.NodeSelectorOpExists},
+					},
+				},
+			},
+		},
+	}
+	_, err = coreops.Instance().CreatePod(newPod("px-pod-1", operatorDefault))
+	require.NoError(t, err)
+	err = ValidatePxNodeAffinity(cluster)
+	require.NoError(t, err)
+
+	// TestCase: Pod is missing the default node affinity
+	_, err = coreops.Instance().CreatePod(newPod("px-pod-2", nil))
+	require.NoError(t, err)
+	err = ValidatePxNodeAffinity(cluster)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "pod [px-pod-2]: default node affinity term")
+	require.NotContains(t, err.Error(), "px-pod-1")
+
+	// TestCase: Pods carry the custom node affinity from the cluster spec
+	customAffinity := &v1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{
+			NodeSelectorTerms: []v1.NodeSelectorTerm{
+				{
+					MatchExpressions: []v1.NodeSelectorRequirement{
+						{Key: "px/storage", Operator: v1.NodeSelectorOpIn, Values: []string{"true"}},
+					},
+				},
+			},
+		},
+	}
+	cluster.Spec.Placement = &corev1.PlacementSpec{NodeAffinity: customAffinity}
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(
+		newPod("px-pod-1", customAffinity.DeepCopy()),
+		newPod("px-pod-2", customAffinity.DeepCopy()),
+	)))
+	err = ValidatePxNodeAffinity(cluster)
+	require.NoError(t, err)
+
+	// TestCase: Pod has a different node selector term than the cluster spec
+	differentAffinity := customAffinity.DeepCopy()
+	differentAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions[0].Values = []string{"false"}
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(
+		newPod("px-pod-1", customAffinity.DeepCopy()),
+		newPod("px-pod-2", differentAffinity),
+	)))
+	err = ValidatePxNodeAffinity(cluster)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "pod [px-pod-2]: expected node selector term")
+	require.Contains(t, err.Error(), "[false]")
+	require.NotContains(t, err.Error(), "px-pod-1")
+
+	// TestCase: Pod has the default node affinity instead of the custom one
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(
+		newPod("px-pod-1", operatorDefault),
+	)))
+	err = ValidatePxNodeAffinity(cluster)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "pod [px-pod-1]: expected node selector terms")
+
+	// TestCase: Pod is missing the preferred scheduling terms from the cluster spec
+	customAffinity.PreferredDuringSchedulingIgnoredDuringExecution = []v1.PreferredSchedulingTerm{
+		{
+			Weight: 10,
+			Preference: v1.NodeSelectorTerm{
+				MatchExpressions: []v1.NodeSelectorRequirement{
+					{Key: "px/fast", Operator: v1.NodeSelectorOpExists},
+				},
+			},
+		},
+	}
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(
+		newPod("px-pod-1", &v1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: customAffinity.RequiredDuringSchedulingIgnoredDuringExecution.DeepCopy(),
+		}),
+	)))
+	err = ValidatePxNodeAffinity(cluster)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "pod [px-pod-1]: expected preferred scheduling terms")
+}
+
+func TestGetExpectedPxNodeNameListWithTolerations(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+	}
+	newNode := func(name string, taints ...v1.Taint) *v1.Node {
+		return &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       v1.NodeSpec{Taints: taints},
+		}
+	}
+	dedicatedTaint := v1.Taint{Key: "dedicated", Value: "storage", Effect: v1.TaintEffectNoSchedule}
+	fakeClient := fakek8sclient.NewSimpleClientset(
+		newNode("node1"),
+		newNode("node2", dedicatedTaint),
+		newNode("node3", v1.Taint{Key: v1.TaintNodeUnschedulable, Effect: v1.TaintEffectNoSchedule}),
+		newNode("node4", v1.Taint{Key: "dedicated", Value: "storage", Effect: v1.TaintEffectPreferNoSchedule}),
+	)
+	fakeClient.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &k8sversion.Info{
+		GitVersion: "v1.21.0",
+	}
+	coreops.SetInstance(coreops.New(fakeClient))
+
+	// TestCase: Tainted node is excluded without a matching toleration
+	nodeNames, err := GetExpectedPxNodeNameList(cluster)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"node1", "node3", "node4"}, nodeNames)
+
+	// TestCase: Tainted node is included with a matching toleration
+	cluster.Spec.Placement = &corev1.PlacementSpec{
+		Tolerations: []v1.Toleration{
+			{
+				Key:      "dedicated",
+				Operator: v1.TolerationOpEqual,
+				Value:    "storage",
+				Effect:   v1.TaintEffectNoSchedule,
+			},
+		},
+	}
+	nodeNames, err = GetExpectedPxNodeNameList(cluster)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"node1", "node2", "node3", "node4"}, nodeNames)
+
+	// TestCase: Tainted node is excluded if the toleration value does not match
+	cluster.Spec.Placement.Tolerations[0].Value = "compute"
+	nodeNames, err = GetExpectedPxNodeNameList(cluster)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"node1", "node3", "node4"}, nodeNames)
+}
+
+func TestGetExpectedPxNodeNameListWithPxDisabled(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+	}
+	newNode := func(name string, labels map[string]string) *v1.Node {
+		return &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		}
+	}
+	fakeClient := fakek8sclient.NewSimpleClientset(
+		newNode("node1", nil),
+		newNode("node2", map[string]string{"px/enabled": "false"}),
+		newNode("node3", map[string]string{"px/enabled": "true", "px/storage": "true"}),
+	)
+	fakeClient.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &k8sversion.Info{
+		GitVersion: "v1.21.0",
+	}
+	coreops.SetInstance(coreops.New(fakeClient))
+
+	// TestCase: Node with Portworx disabled is excluded with the default node affinity
+	nodeNames, err := GetExpectedPxNodeNameList(cluster)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"node1", "node3"}, nodeNames)
+
+	// TestCase: Node with Portworx disabled is included with a custom node affinity
+	cluster.Spec.Placement = &corev1.PlacementSpec{
+		NodeAffinity: &v1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{
+				NodeSelectorTerms: []v1.NodeSelectorTerm{
+					{
+						MatchExpressions: []v1.NodeSelectorRequirement{
+							{Key: "px/storage", Operator: v1.NodeSelectorOpDoesNotExist},
+						},
+					},
+				},
+			},
+		},
+	}
+	nodeNames, err = GetExpectedPxNodeNameList(cluster)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"node1", "node2"}, nodeNames)
+
+	// TestCase: No nodes are expected if storage is disabled on the cluster
+	cluster.Annotations = map[string]string{constants.AnnotationDisableStorage: "true"}
+	nodeNames, err = GetExpectedPxNodeNameList(cluster)
+	require.NoError(t, err)
+	require.Empty(t, nodeNames)
+
+	// TestCase: Invalid disable storage annotation is ignored
+	cluster.Annotations[constants.AnnotationDisableStorage] = "invalid"
+	nodeNames, err = GetExpectedPxNodeNameList(cluster)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"node1", "node2"}, nodeNames)
+}
+
+func TestValidateKvdbBackend(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+		Spec: corev1.StorageClusterSpec{
+			Kvdb: &corev1.KvdbSpec{
+				Endpoints: []string{"etcd:http://etcd-1.com:1111", "etcd:http://etcd-2.com:1111"},
+			},
+		},
+	}
+	newPod := func(name string, args ...string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: cluster.Namespace,
+				Labels:    map[string]string{"name": "portworx"},
+			},
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{
+					{Name: "portworx", Args: append([]string{"-c", "px-cluster", "-x", "kubernetes"}, args...)},
+				},
+			},
+		}
+	}
+	newStorageNode := func(name string, kvdbStatus corev1.NodeConditionStatus) *corev1.StorageNode {
+		storageNode := &corev1.StorageNode{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: cluster.Namespace,
+			},
+		}
+		if kvdbStatus != "" {
+			storageNode.Status.Conditions = []corev1.NodeCondition{
+				{Type: corev1.NodeKVDBCondition, Status: kvdbStatus},
+			}
+		}
+		return storageNode
+	}
+	externalEndpoints := "etcd:http://etcd-1.com:1111,etcd:http://etcd-2.com:1111"
+
+	// TestCase: External kvdb endpoints match the spec
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(
+		newPod("px-pod-1", "-k", externalEndpoints),
+		newPod("px-pod-2", "-k", externalEndpoints),
+	)))
+	err := ValidateKvdbBackend(cluster)
+	require.NoError(t, err)
+
+	// TestCase: External kvdb endpoints do not match the spec
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(
+		newPod("px-pod-1", "-k", "etcd:http://etcd-1.com:1111"),
+		newPod("px-pod-2", "-b"),
+	)))
+	err = ValidateKvdbBackend(cluster)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "pod [px-pod-1]: expected kvdb endpoints: ["+externalEndpoints+"], actual: [etcd:http://etcd-1.com:1111]")
+	require.Contains(t, err.Error(), "pod [px-pod-2]: expected internal kvdb: false, actual: true")
+
+	// TestCase: Internal kvdb with all members online
+	cluster.Spec.Kvdb = &corev1.KvdbSpec{Internal: true}
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(
+		newPod("px-pod-1", "-b"),
+		newPod("px-pod-2", "-b"),
+	)))
+	operatorops.SetInstance(operatorops.New(fakeoperatorclient.NewSimpleClientset(
+		newStorageNode("node1", corev1.NodeOnlineStatus),
+		newStorageNode("node2", corev1.NodeOnlineStatus),
+		newStorageNode("node3", corev1.NodeOnlineStatus),
+		newStorageNode("node4", ""),
+	)))
+	err = ValidateKvdbBackend(cluster)
+	require.NoError(t, err)
+
+	// TestCase: Internal kvdb with a member that is not online
+	operatorops.SetInstance(operatorops.New(fakeoperatorclient.NewSimpleClientset(
+		newStorageNode("node1", corev1.NodeOnlineStatus),
+		newStorageNode("node2", corev1.NodeOnlineStatus),
+		newStorageNode("node3", corev1.NodeFailedStatus),
+	)))
+	err = ValidateKvdbBackend(cluster)
+	require.Error(t, err)
+	require.Equal(t, "failed to validate internal kvdb members, Err: expected 3 healthy internal kvdb members, "+
+		"healthy: [node1 node2], unhealthy: [node3 (Failed)]", err.Error())
+
+	// TestCase: Internal kvdb is not enabled on the Portworx pods
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(
+		newPod("px-pod-1"),
+	)))
+	err = ValidateKvdbBackend(cluster)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "pod [px-pod-1]: expected internal kvdb: true, actual: false")
+}
+
+func TestValidateClusterID(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+	}
+	newPod := func(name, clusterID string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: cluster.Namespace,
+				Labels:    map[string]string{"name": "portworx"},
+			},
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{
+					{Name: "portworx", Args: []string{"-c", clusterID, "-x", "kubernetes"}},
+				},
+			},
+		}
+	}
+
+	// TestCase: Cluster status is not populated
+	err := ValidateClusterID(cluster)
+	require.Error(t, err)
+	require.Equal(t, `StorageCluster kube-test/px-cluster status has cluster name "", expected: "px-cluster"`, err.Error())
+
+	cluster.Status.ClusterName = "px-cluster"
+	err = ValidateClusterID(cluster)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "missing the cluster UID")
+
+	// TestCase: All pods run with the cluster name as cluster ID
+	cluster.Status.ClusterUID = "cluster-uid"
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(
+		newPod("px-pod-1", "px-cluster"),
+		newPod("px-pod-2", "px-cluster"),
+	)))
+	err = ValidateClusterID(cluster)
+	require.NoError(t, err)
+
+	// TestCase: Pod runs with a mismatched cluster ID
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(
+		newPod("px-pod-1", "px-cluster"),
+		newPod("px-pod-2", "other-cluster"),
+	)))
+	err = ValidateClusterID(cluster)
+	require.Error(t, err)
+	require.Equal(t, `failed to validate cluster ID of Portworx pods: pod [px-pod-2]: expected cluster ID: "px-cluster", actual: "other-cluster"`, err.Error())
+
+	// TestCase: Cluster ID is overwritten using the annotation
+	cluster.Annotations = map[string]string{"portworx.io/cluster-id": "custom-id"}
+	err = ValidateClusterID(cluster)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `status has cluster name "px-cluster", expected: "custom-id"`)
+
+	cluster.Status.ClusterName = "custom-id"
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(
+		newPod("px-pod-1", "custom-id"),
+	)))
+	err = ValidateClusterID(cluster)
+	require.NoError(t, err)
+}
+
+func TestValidateInternalKvdbMembers(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+	}
+	newStorageNode := func(name string, kvdbStatus corev1.NodeConditionStatus) *corev1.StorageNode {
+		storageNode := &corev1.StorageNode{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: cluster.Namespace,
+			},
+		}
+		if kvdbStatus != "" {
+			storageNode.Status.Conditions = []corev1.NodeCondition{
+				{Type: corev1.NodeKVDBCondition, Status: kvdbStatus},
+			}
+		}
+		return storageNode
+	}
+
+	// TestCase: External kvdb is configured
+	cluster.Spec.Kvdb = &corev1.KvdbSpec{Endpoints: []string{"etcd:http://etcd-1.com:1111"}}
+	err := ValidateInternalKvdbMembers(cluster, 0, time.Second, 10*time.Millisecond)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "internal kvdb is not enabled")
+
+	// TestCase: Fewer than the default 3 members are healthy
+	cluster.Spec.Kvdb = &corev1.KvdbSpec{Internal: true}
+	operatorops.SetInstance(operatorops.New(fakeoperatorclient.NewSimpleClientset(
+		newStorageNode("node1", corev1.NodeOnlineStatus),
+		newStorageNode("node2", corev1.NodeOnlineStatus),
+		newStorageNode("node3", corev1.NodeOfflineStatus),
+		newStorageNode("node4", ""),
+	)))
+	err = ValidateInternalKvdbMembers(cluster, 0, 100*time.Millisecond, 10*time.Millisecond)
+	require.Error(t, err)
+	require.Equal(t, "failed to validate internal kvdb members, Err: expected 3 healthy internal kvdb members, "+
+		"healthy: [node1 node2], unhealthy: [node3 (Offline)]", err.Error())
+
+	// TestCase: A custom number of healthy members is expected
+	err = ValidateInternalKvdbMembers(cluster, 2, time.Second, 10*time.Millisecond)
+	require.NoError(t, err)
+
+	// TestCase: Members become healthy while waiting
+	operatorClient := fakeoperatorclient.NewSimpleClientset()
+	listCalls := 0
+	operatorClient.PrependReactor("list", "storagenodes", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		listCalls++
+		thirdStatus := corev1.NodeInitStatus
+		if listCalls >= 3 {
+			thirdStatus = corev1.NodeOnlineStatus
+		}
+		return true, &corev1.StorageNodeList{
+			Items: []corev1.StorageNode{
+				*newStorageNode("node1", corev1.NodeOnlineStatus),
+				*newStorageNode("node2", corev1.NodeOnlineStatus),
+				*newStorageNode("node3", thirdStatus),
+			},
+		}, nil
+	})
+	operatorops.SetInstance(operatorops.New(operatorClient))
+	err = ValidateInternalKvdbMembers(cluster, 0, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, listCalls, 3)
+}
+
+func TestValidateImagePullSecrets(t *testing.T) {
+	secretName := "registry-secret"
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+		Spec: corev1.StorageClusterSpec{
+			ImagePullSecret: &secretName,
+			Stork: &corev1.StorkSpec{
+				Enabled: true,
+			},
+		},
+	}
+	pullSecrets := []v1.LocalObjectReference{{Name: secretName}}
+	pxPod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-pod-1",
+			Namespace: cluster.Namespace,
+			Labels:    map[string]string{"name": "portworx"},
+		},
+		Spec: v1.PodSpec{ImagePullSecrets: pullSecrets},
+	}
+	newDeploymentObjects := func(name string, secrets []v1.LocalObjectReference) []runtime.Object {
+		return []runtime.Object{
+			&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: cluster.Namespace,
+				},
+			},
+			&appsv1.ReplicaSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            name + "-123",
+					Namespace:       cluster.Namespace,
+					UID:             types.UID(name + "-rs-uid"),
+					OwnerReferences: []metav1.OwnerReference{{Name: name}},
+				},
+			},
+			&v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            name + "-123-abc",
+					Namespace:       cluster.Namespace,
+					OwnerReferences: []metav1.OwnerReference{{UID: types.UID(name + "-rs-uid")}},
+				},
+				Spec: v1.PodSpec{ImagePullSecrets: secrets},
+			},
+		}
+	}
+	setClients := func(objs ...runtime.Object) {
+		c := fakek8sclient.NewSimpleClientset(objs...)
+		coreops.SetInstance(coreops.New(c))
+		appops.SetInstance(appops.New(c.AppsV1(), c.CoreV1()))
+	}
+
+	// TestCase: No image pull secret given
+	err := ValidateImagePullSecrets(cluster, "")
+	require.Error(t, err)
+
+	// TestCase: Stork pod is missing the image pull secret
+	objs := []runtime.Object{pxPod}
+	objs = append(objs, newDeploymentObjects("stork", nil)...)
+	objs = append(objs, newDeploymentObjects("stork-scheduler", pullSecrets)...)
+	setClients(objs...)
+	err = ValidateImagePullSecrets(cluster, secretName)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "pods missing the secret: [stork-123-abc]")
+
+	// TestCase: All pods have the image pull secret
+	objs = []runtime.Object{pxPod}
+	objs = append(objs, newDeploymentObjects("stork", pullSecrets)...)
+	objs = append(objs, newDeploymentObjects("stork-scheduler", pullSecrets)...)
+	setClients(objs...)
+	err = ValidateImagePullSecrets(cluster, secretName)
+	require.NoError(t, err)
+
+	// TestCase: Deployment of an enabled component is missing
+	cluster.Spec.Autopilot = &corev1.AutopilotSpec{Enabled: true}
+	err = ValidateImagePullSecrets(cluster, secretName)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to get deployment kube-test/autopilot")
+}
+
+func TestValidateOwnerReference(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+			UID:       "px-cluster-uid",
+		},
+	}
+
+	// TestCase: Deployment owned by the StorageCluster
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "stork",
+			Namespace: cluster.Namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(cluster, corev1.SchemeGroupVersion.WithKind("StorageCluster")),
+			},
+		},
+	}
+	err := ValidateOwnerReference(deployment, cluster)
+	require.NoError(t, err)
+
+	// TestCase: Orphaned deployment
+	deployment.OwnerReferences = nil
+	err = ValidateOwnerReference(deployment, cluster)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to find owner reference of StorageCluster kube-test/px-cluster")
+
+	// TestCase: Deployment owned by a different object
+	deployment.OwnerReferences = []metav1.OwnerReference{{Name: "px-cluster", UID: "other-uid"}}
+	err = ValidateOwnerReference(deployment, cluster)
+	require.Error(t, err)
+}
+
+func TestValidateSelfHeal(t *testing.T) {
+	newConfigMap := func(uid types.UID) *v1.ConfigMap {
+		return &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "px-config",
+				Namespace: "kube-test",
+				UID:       uid,
+			},
+		}
+	}
+
+	// TestCase: Nothing recreates the deleted object
+	k8sClient := FakeK8sClient(newConfigMap("uid-1"))
+	err := ValidateSelfHeal(k8sClient, newConfigMap("uid-1"), 200*time.Millisecond, 20*time.Millisecond)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to wait for kube-test/px-config to be recreated")
+
+	// TestCase: Controller recreates the deleted object
+	k8sClient = FakeK8sClient(newConfigMap("uid-1"))
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go func() {
+		for {
+			select {
+			case <-stopCh:
+				return
+			case <-time.After(10 * time.Millisecond):
+			}
+			cm := &v1.ConfigMap{}
+			if err := Get(k8sClient, cm, "px-config", "kube-test"); errors.IsNotFound(err) {
+				_ = k8sClient.Create(context.TODO(), newConfigMap("uid-2"))
+				return
+			}
+		}
+	}()
+
+	cm := newConfigMap("uid-1")
+	err = ValidateSelfHeal(k8sClient, cm, 5*time.Second, 20*time.Millisecond)
+	require.NoError(t, err)
+	require.Equal(t, types.UID("uid-2"), cm.UID)
+}
+
+func TestValidateReconcilePaused(t *testing.T) {
+	newCluster := func() *corev1.StorageCluster {
+		return &corev1.StorageCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "px-cluster",
+				Namespace:   "kube-test",
+				Annotations: map[string]string{constants.AnnotationMigrationApproved: "true"},
+			},
+		}
+	}
+	newConfigMap := func() *v1.ConfigMap {
+		return &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "px-config",
+				Namespace: "kube-test",
+			},
+			Data: map[string]string{"key": "operator-value"},
+		}
+	}
+	mutate := func(obj client.Object) {
+		obj.(*v1.ConfigMap).Data["key"] = "user-value"
+	}
+	// startController runs a fake controller that reverts the config map, unless
+	// respectPause is set and the components of the cluster are paused
+	startController := func(k8sClient client.Client, respectPause bool) chan struct{} {
+		stopCh := make(chan struct{})
+		go func() {
+			for {
+				select {
+				case <-stopCh:
+					return
+				case <-time.After(5 * time.Millisecond):
+				}
+				cluster := &corev1.StorageCluster{}
+				if err := Get(k8sClient, cluster, "px-cluster", "kube-test"); err != nil {
+					continue
+				}
+				if respectPause && util.ComponentsPausedForMigration(cluster) {
+					continue
+				}
+				cm := &v1.ConfigMap{}
+				if err := Get(k8sClient, cm, "px-config", "kube-test"); err == nil && cm.Data["key"] != "operator-value" {
+					cm.Data["key"] = "operator-value"
+					_ = Update(k8sClient, cm)
+				}
+			}
+		}()
+		return stopCh
+	}
+
+	// TestCase: Cluster is not migrating, so it cannot be paused
+	cluster := newCluster()
+	cluster.Annotations = nil
+	k8sClient := FakeK8sClient(cluster, newConfigMap())
+	err := ValidateReconcilePaused(k8sClient, cluster, newConfigMap(), mutate, 50*time.Millisecond, 10*time.Millisecond)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is not migrating")
+
+	// TestCase: Controller respects the pause annotation
+	cluster = newCluster()
+	k8sClient = FakeK8sClient(cluster, newConfigMap())
+	stopCh := startController(k8sClient, true)
+	cm := newConfigMap()
+	err = ValidateReconcilePaused(k8sClient, cluster, cm, mutate, 100*time.Millisecond, 10*time.Millisecond)
+	close(stopCh)
+	require.NoError(t, err)
+	require.Equal(t, "user-value", cm.Data["key"])
+	require.Equal(t, "true", cluster.Annotations[constants.AnnotationPauseComponentMigration])
+
+	// TestCase: Controller ignores the pause annotation and reverts the change
+	cluster = newCluster()
+	k8sClient = FakeK8sClient(cluster, newConfigMap())
+	stopCh = startController(k8sClient, false)
+	err = ValidateReconcilePaused(k8sClient, cluster, newConfigMap(), mutate, time.Second, 10*time.Millisecond)
+	close(stopCh)
+	require.Error(t, err)
+	require.Equal(t, "kube-test/px-config was reverted while StorageCluster kube-test/px-cluster is paused", err.Error())
+}
+
+// rejectingClient is a Kubernetes client that fails the creation of StorageClusters
+// with the given error
+type rejectingClient struct {
+	client.Client
+	err error
+}
+
+func (c *rejectingClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if _, ok := obj.(*corev1.StorageCluster); ok {
+		return c.err
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func TestValidateSingleClusterPerNamespace(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+		Spec: corev1.StorageClusterSpec{Image: "portworx/oci-monitor:2.10.1"},
+	}
+
+	// TestCase: No StorageCluster to duplicate
+	err := ValidateSingleClusterPerNamespace(FakeK8sClient(), "kube-test", 50*time.Millisecond, 10*time.Millisecond)
+	require.Error(t, err)
+
+	// TestCase: Duplicate StorageCluster is denied by an admission webhook on create
+	webhookErr := &errors.StatusError{ErrStatus: metav1.Status{
+		Status:  metav1.StatusFailure,
+		Code:    403,
+		Message: `admission webhook "storagecluster.core.libopenstorage.org" denied the request: only one StorageCluster is allowed`,
+	}}
+	k8sClient := &rejectingClient{FakeK8sClient(cluster.DeepCopy()), webhookErr}
+	err = ValidateSingleClusterPerNamespace(k8sClient, "kube-test", 50*time.Millisecond, 10*time.Millisecond)
+	require.NoError(t, err)
+
+	// TestCase: Duplicate StorageCluster is rejected as invalid on create
+	invalidErr := errors.NewInvalid(corev1.SchemeGroupVersion.WithKind("StorageCluster").GroupKind(), "px-cluster-duplicate", nil)
+	k8sClient = &rejectingClient{FakeK8sClient(cluster.DeepCopy()), invalidErr}
+	err = ValidateSingleClusterPerNamespace(k8sClient, "kube-test", 50*time.Millisecond, 10*time.Millisecond)
+	require.NoError(t, err)
+
+	// TestCase: RBAC failure on create is not a rejection of the duplicate
+	rbacErr := errors.NewForbidden(corev1.SchemeGroupVersion.WithResource("storageclusters").GroupResource(),
+		"px-cluster-duplicate", fmt.Errorf("User \"test\" cannot create resource \"storageclusters\""))
+	k8sClient = &rejectingClient{FakeK8sClient(cluster.DeepCopy()), rbacErr}
+	err = ValidateSingleClusterPerNamespace(k8sClient, "kube-test", 50*time.Millisecond, 10*time.Millisecond)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to create duplicate StorageCluster kube-test/px-cluster-duplicate")
+
+	// TestCase: Transport error on create is returned
+	k8sClient = &rejectingClient{FakeK8sClient(cluster.DeepCopy()), fmt.Errorf("connection refused")}
+	err = ValidateSingleClusterPerNamespace(k8sClient, "kube-test", 50*time.Millisecond, 10*time.Millisecond)
+	require.Error(t, err)
+	require.Equal(t, "failed to create duplicate StorageCluster kube-test/px-cluster-duplicate, Err: connection refused", err.Error())
+
+	// TestCase: Duplicate StorageCluster is created but never rejected
+	fakeClient := FakeK8sClient(cluster.DeepCopy())
+	err = ValidateSingleClusterPerNamespace(fakeClient, "kube-test", 50*time.Millisecond, 10*time.Millisecond)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "duplicate StorageCluster kube-test/px-cluster-duplicate was not rejected")
+	clusterList := &corev1.StorageClusterList{}
+	require.NoError(t, List(fakeClient, clusterList))
+	require.Len(t, clusterList.Items, 1)
+
+	// TestCase: Operator marks the duplicate StorageCluster as failed
+	fakeClient = FakeK8sClient(cluster.DeepCopy())
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go func() {
+		for {
+			select {
+			case <-stopCh:
+				return
+			case <-time.After(5 * time.Millisecond):
+			}
+			duplicate := &corev1.StorageCluster{}
+			if err := Get(fakeClient, duplicate, "px-cluster-duplicate", "kube-test"); err == nil {
+				duplicate.Status.Phase = string(corev1.ClusterOperationFailed)
+				_ = fakeClient.Status().Update(context.TODO(), duplicate)
+				return
+			}
+		}
+	}()
+	err = ValidateSingleClusterPerNamespace(fakeClient, "kube-test", 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, err)
+	clusterList = &corev1.StorageClusterList{}
+	require.NoError(t, List(fakeClient, clusterList))
+	require.Len(t, clusterList.Items, 1)
+}
+
+func TestValidateSecuritySecrets(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+	}
+	newSecret := func(name, key, value string) *v1.Secret {
+		return &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: cluster.Namespace,
+			},
+			Data: map[string][]byte{key: []byte(value)},
+		}
+	}
+	systemSecret := newSecret("px-system-secrets", "system-secret", "system-key")
+
+	// TestCase: Auth is not enabled
+	err := ValidateSecuritySecrets(cluster)
+	require.Error(t, err)
+
+	cluster.Spec.Security = &corev1.SecuritySpec{
+		Enabled: true,
+		Auth:    &corev1.AuthSpec{Enabled: BoolPtr(false)},
+	}
+	err = ValidateSecuritySecrets(cluster)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "auth is not enabled")
+
+	// TestCase: Shared secret is missing
+	cluster.Spec.Security.Auth = nil
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(systemSecret)))
+	err = ValidateSecuritySecrets(cluster)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to find secret kube-test/px-shared-secret")
+
+	// TestCase: All secrets exist with non-empty keys
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(
+		newSecret("px-shared-secret", "shared-secret", "shared-key"),
+		systemSecret,
+	)))
+	err = ValidateSecuritySecrets(cluster)
+	require.NoError(t, err)
+
+	// TestCase: Custom shared secret name with an empty key
+	sharedSecretName := "custom-shared-secret"
+	cluster.Spec.Security.Auth = &corev1.AuthSpec{
+		SelfSigned: &corev1.SelfSignedSpec{SharedSecret: &sharedSecretName},
+	}
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(
+		newSecret(sharedSecretName, "shared-secret", ""),
+		systemSecret,
+	)))
+	err = ValidateSecuritySecrets(cluster)
+	require.Error(t, err)
+	require.Equal(t, "secret kube-test/custom-shared-secret has an empty or missing key shared-secret", err.Error())
+
+	// TestCase: System secret is missing the system key
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(
+		newSecret(sharedSecretName, "shared-secret", "shared-key"),
+		newSecret("px-system-secrets", "apps-secret", "apps-key"),
+	)))
+	err = ValidateSecuritySecrets(cluster)
+	require.Error(t, err)
+	require.Equal(t, "secret kube-test/px-system-secrets has an empty or missing key system-secret", err.Error())
+}
+
+func TestGenerateAuthToken(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+	}
+	claims := &auth.Claims{
+		Name:   "test-user",
+		Roles:  []string{"system.admin"},
+		Groups: []string{"*"},
+	}
+
+	// TestCase: Auth is not enabled
+	_, err := GenerateAuthToken(cluster, claims)
+	require.Error(t, err)
+
+	// TestCase: Shared secret is missing
+	cluster.Spec.Security = &corev1.SecuritySpec{Enabled: true}
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset()))
+	_, err = GenerateAuthToken(cluster, claims)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to get secret kube-test/px-shared-secret")
+
+	// TestCase: Token is signed with the shared secret and default claims are filled in
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(&v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-shared-secret",
+			Namespace: cluster.Namespace,
+		},
+		Data: map[string][]byte{"shared-secret": []byte("shared-key")},
+	})))
+	token, err := GenerateAuthToken(cluster, claims)
+	require.NoError(t, err)
+
+	authenticator, err := auth.NewJwtAuth(&auth.JwtAuthConfig{SharedSecret: []byte("shared-key")})
+	require.NoError(t, err)
+	parsedClaims, err := authenticator.AuthenticateToken(context.TODO(), token)
+	require.NoError(t, err)
+	require.Equal(t, "operator.portworx.io", parsedClaims.Issuer)
+	require.Equal(t, "test-user@operator.portworx.io", parsedClaims.Subject)
+	require.Equal(t, "test-user@operator.portworx.io", parsedClaims.Email)
+	require.Equal(t, []string{"system.admin"}, parsedClaims.Roles)
+	require.Equal(t, []string{"*"}, parsedClaims.Groups)
+	require.Empty(t, claims.Issuer)
+
+	// Token is not valid for a different shared secret
+	otherAuthenticator, err := auth.NewJwtAuth(&auth.JwtAuthConfig{SharedSecret: []byte("other-key")})
+	require.NoError(t, err)
+	_, err = otherAuthenticator.AuthenticateToken(context.TODO(), token)
+	require.Error(t, err)
+
+	// TestCase: Issuer and token lifetime are taken from the spec
+	issuer := "custom.issuer.io"
+	lifetime := "1h"
+	cluster.Spec.Security.Auth = &corev1.AuthSpec{
+		SelfSigned: &corev1.SelfSignedSpec{Issuer: &issuer, TokenLifetime: &lifetime},
+	}
+	token, err = GenerateAuthToken(cluster, claims)
+	require.NoError(t, err)
+	parsedClaims, err = auth.TokenClaims(token)
+	require.NoError(t, err)
+	require.Equal(t, issuer, parsedClaims.Issuer)
+	require.Equal(t, "test-user@"+issuer, parsedClaims.Subject)
+
+	// TestCase: Invalid token lifetime
+	lifetime = "forever"
+	_, err = GenerateAuthToken(cluster, claims)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to parse token lifetime")
+}
+
+// newTestCertificate creates a PEM encoded certificate and key valid between the given times. The
+// certificate is self-signed if no parent is given, otherwise it is signed by the parent.
+func newTestCertificate(
+	t *testing.T,
+	commonName string,
+	isCA bool,
+	notBefore, notAfter time.Time,
+	parent *x509.Certificate,
+	parentKey *ecdsa.PrivateKey,
+) ([]byte, []byte, *x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+	return certPEM, keyPEM, cert, key
+}
+
+func TestValidateTLSCerts(t *testing.T) {
+	now := time.Now()
+	caPEM, _, caCert, caKey := newTestCertificate(t, "test-ca", true, now.Add(-time.Hour), now.Add(24*time.Hour), nil, nil)
+	serverPEM, serverKeyPEM, _, _ := newTestCertificate(t, "px-server", false, now.Add(-time.Hour), now.Add(24*time.Hour), caCert, caKey)
+
+	newSecret := func(name string, data map[string][]byte) *v1.Secret {
+		return &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "kube-system",
+			},
+			Data: data,
+		}
+	}
+	secretLocation := func(name, key string) *corev1.CertLocation {
+		return &corev1.CertLocation{SecretRef: &corev1.SecretRef{SecretName: name, SecretKey: key}}
+	}
+
+	// TestCase: TLS is not enabled
+	cluster := CreateClusterWithTLS(nil, nil, nil)
+	cluster.Spec.Security.TLS.Enabled = BoolPtr(false)
+	err := ValidateTLSCerts(cluster)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "TLS is not enabled")
+
+	// TestCase: Server certificate is not configured
+	cluster = CreateClusterWithTLS(nil, nil, nil)
+	err = ValidateTLSCerts(cluster)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "server certificate is not configured")
+
+	// TestCase: Valid certificates loaded from files
+	dir := t.TempDir()
+	caFile, serverFile, serverKeyFile := dir+"/ca.crt", dir+"/server.crt", dir+"/server.key"
+	require.NoError(t, ioutil.WriteFile(caFile, caPEM, 0600))
+	require.NoError(t, ioutil.WriteFile(serverFile, serverPEM, 0600))
+	require.NoError(t, ioutil.WriteFile(serverKeyFile, serverKeyPEM, 0600))
+	cluster = CreateClusterWithTLS(&caFile, &serverFile, &serverKeyFile)
+	err = ValidateTLSCerts(cluster)
+	require.NoError(t, err)
+
+	// TestCase: Certificate file is missing
+	missingFile := dir + "/missing.crt"
+	cluster = CreateClusterWithTLS(&caFile, &missingFile, &serverKeyFile)
+	err = ValidateTLSCerts(cluster)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to load server certificate: failed to read local file "+missingFile)
+
+	// TestCase: Valid certificates loaded from secrets
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(
+		newSecret("px-ca", map[string][]byte{"ca.crt": caPEM}),
+		newSecret("px-server", map[string][]byte{"tls.crt": serverPEM, "tls.key": serverKeyPEM}),
+	)))
+	cluster = CreateClusterWithTLS(nil, nil, nil)
+	cluster.Spec.Security.TLS.RootCA = secretLocation("px-ca", "ca.crt")
+	cluster.Spec.Security.TLS.ServerCert = secretLocation("px-server", "tls.crt")
+	cluster.Spec.Security.TLS.ServerKey = secretLocation("px-server", "tls.key")
+	err = ValidateTLSCerts(cluster)
+	require.NoError(t, err)
+
+	// TestCase: Secret reference takes precedence over the file name
+	cluster.Spec.Security.TLS.ServerCert.FileName = &missingFile
+	err = ValidateTLSCerts(cluster)
+	require.NoError(t, err)
+
+	// TestCase: Secret key is missing
+	cluster.Spec.Security.TLS.ServerKey = secretLocation("px-server", "missing.key")
+	err = ValidateTLSCerts(cluster)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "secret kube-system/px-server has an empty or missing key missing.key")
+
+	// TestCase: Server certificate is expired
+	expiredPEM, expiredKeyPEM, _, _ := newTestCertificate(t, "px-expired", false, now.Add(-48*time.Hour), now.Add(-24*time.Hour), caCert, caKey)
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(
+		newSecret("px-ca", map[string][]byte{"ca.crt": caPEM}),
+		newSecret("px-server", map[string][]byte{"tls.crt": expiredPEM, "tls.key": expiredKeyPEM}),
+	)))
+	cluster.Spec.Security.TLS.ServerKey = secretLocation("px-server", "tls.key")
+	err = ValidateTLSCerts(cluster)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "server certificate \"px-expired\" expired on")
+
+	// TestCase: Root CA is expired
+	expiredCAPEM, _, _, _ := newTestCertificate(t, "expired-ca", true, now.Add(-48*time.Hour), now.Add(-24*time.Hour), nil, nil)
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(
+		newSecret("px-ca", map[string][]byte{"ca.crt": expiredCAPEM}),
+		newSecret("px-server", map[string][]byte{"tls.crt": serverPEM, "tls.key": serverKeyPEM}),
+	)))
+	err = ValidateTLSCerts(cluster)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "root CA certificate \"expired-ca\" expired on")
+
+	// TestCase: Server certificate is not signed by the root CA
+	otherCAPEM, _, _, _ := newTestCertificate(t, "other-ca", true, now.Add(-time.Hour), now.Add(24*time.Hour), nil, nil)
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(
+		newSecret("px-ca", map[string][]byte{"ca.crt": otherCAPEM}),
+		newSecret("px-server", map[string][]byte{"tls.crt": serverPEM, "tls.key": serverKeyPEM}),
+	)))
+	err = ValidateTLSCerts(cluster)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "server certificate is not signed by the root CA")
+
+	// TestCase: Server key does not match the server certificate
+	_, otherKeyPEM, _, _ := newTestCertificate(t, "px-other", false, now.Add(-time.Hour), now.Add(24*time.Hour), caCert, caKey)
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(
+		newSecret("px-ca", map[string][]byte{"ca.crt": caPEM}),
+		newSecret("px-server", map[string][]byte{"tls.crt": serverPEM, "tls.key": otherKeyPEM}),
+	)))
+	err = ValidateTLSCerts(cluster)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "server key does not match the server certificate")
+
+	// TestCase: Server certificate is not PEM encoded
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(
+		newSecret("px-ca", map[string][]byte{"ca.crt": caPEM}),
+		newSecret("px-server", map[string][]byte{"tls.crt": []byte("invalid"), "tls.key": serverKeyPEM}),
+	)))
+	err = ValidateTLSCerts(cluster)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid server certificate: no PEM encoded certificate found")
+}
+
+func TestIsCSIEnabled(t *testing.T) {
+	cluster := &corev1.StorageCluster{}
+	require.False(t, isCSIEnabled(cluster))
+
+	// TestCase: CSI enabled through spec.csi
+	cluster.Spec.CSI = &corev1.CSISpec{Enabled: true}
+	require.True(t, isCSIEnabled(cluster))
+
+	cluster.Spec.CSI.Enabled = false
+	require.False(t, isCSIEnabled(cluster))
+
+	// TestCase: spec.csi takes precedence over the legacy feature gate
+	cluster.Spec.FeatureGates = map[string]string{"CSI": "true"}
+	require.False(t, isCSIEnabled(cluster))
+
+	// TestCase: CSI enabled through the legacy feature gate
+	cluster.Spec.CSI = nil
+	require.True(t, isCSIEnabled(cluster))
+
+	cluster.Spec.FeatureGates["CSI"] = "false"
+	require.False(t, isCSIEnabled(cluster))
+
+	cluster.Spec.FeatureGates["CSI"] = "invalid"
+	require.False(t, isCSIEnabled(cluster))
+}
+
+func TestValidateFeatureGate(t *testing.T) {
+	cluster := &corev1.StorageCluster{}
+	var calls []string
+	newValidator := func(name string, err error) func() error {
+		return func() error {
+			calls = append(calls, name)
+			return err
+		}
+	}
+
+	// TestCase: Gate not set is disabled
+	err := ValidateFeatureGate(cluster, "CSI", false, newValidator("terminated", nil))
+	require.NoError(t, err)
+	require.Equal(t, []string{"terminated"}, calls)
+
+	// TestCase: Gate toggled on runs the validators in order
+	calls = nil
+	cluster.Spec.FeatureGates = map[string]string{"CSI": "true"}
+	err = ValidateFeatureGate(cluster, "CSI", true, newValidator("deployment", nil), newValidator("images", nil))
+	require.NoError(t, err)
+	require.Equal(t, []string{"deployment", "images"}, calls)
+
+	// TestCase: Gate does not match the expectation, validators are not run
+	calls = nil
+	err = ValidateFeatureGate(cluster, "CSI", false, newValidator("terminated", nil))
+	require.Error(t, err)
+	require.Equal(t, "failed to validate feature gate CSI, expected enabled: false, actual: true", err.Error())
+	require.Empty(t, calls)
+
+	// TestCase: Gate toggled off, failing validator stops the validation
+	calls = nil
+	cluster.Spec.FeatureGates["CSI"] = "false"
+	validatorErr := fmt.Errorf("px-csi-ext still exists")
+	err = ValidateFeatureGate(cluster, "CSI", false, newValidator("terminated", validatorErr), newValidator("other", nil))
+	require.Error(t, err)
+	require.ErrorIs(t, err, validatorErr)
+	require.Equal(t, []string{"terminated"}, calls)
+
+	// TestCase: Invalid gate value
+	cluster.Spec.FeatureGates["CSI"] = "invalid"
+	err = ValidateFeatureGate(cluster, "CSI", false)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to parse feature gate CSI value \"invalid\"")
+}
+
+func TestValidateOpenShiftSCC(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "px-cluster",
+			Namespace:   "kube-test",
+			Annotations: map[string]string{"portworx.io/is-openshift": "true"},
+		},
+	}
+	scc := &ocp_secv1.SecurityContextConstraints{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "portworx",
+		},
+		AllowPrivilegedContainer: true,
+		AllowHostDirVolumePlugin: true,
+		AllowHostNetwork:         true,
+	}
+
+	// TestCase: SecurityContextConstraints is absent
+	err := ValidateOpenShiftSCC(FakeK8sClient(), cluster)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to get SecurityContextConstraints portworx")
+
+	// TestCase: SecurityContextConstraints is present
+	err = ValidateOpenShiftSCC(FakeK8sClient(scc), cluster)
+	require.NoError(t, err)
+
+	// TestCase: SecurityContextConstraints is missing privileges
+	scc.AllowHostDirVolumePlugin = false
+	scc.AllowHostNetwork = false
+	err = ValidateOpenShiftSCC(FakeK8sClient(scc), cluster)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "missing privileges: [allowHostDirVolumePlugin allowHostNetwork]")
+
+	// TestCase: SecurityContextConstraints is not validated outside of OpenShift
+	cluster.Annotations = nil
+	err = ValidateOpenShiftSCC(FakeK8sClient(), cluster)
+	require.NoError(t, err)
+}
+
+func TestValidatePSP(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "px-cluster",
+			Namespace:   "kube-test",
+			Annotations: map[string]string{"portworx.io/pod-security-policy": "true"},
+		},
+	}
+	k8sVersion, _ := version.NewVersion("1.24.0")
+	privilegedPSP := GetExpectedPSP(t, "privilegedPSP.yaml")
+	restrictedPSP := GetExpectedPSP(t, "restrictedPSP.yaml")
+	clusterRole := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{Name: "portworx"},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups:     []string{"policy"},
+				Resources:     []string{"podsecuritypolicies"},
+				ResourceNames: []string{"px-privileged"},
+				Verbs:         []string{"use"},
+			},
+		},
+	}
+	clusterRoleBinding := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "portworx"},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "ClusterRole",
+			Name:     "portworx",
+		},
+	}
+
+	// TestCase: PSPs and binding are present
+	k8sClient := FakeK8sClient(privilegedPSP.DeepCopy(), restrictedPSP.DeepCopy(), clusterRole, clusterRoleBinding)
+	err := ValidatePSP(k8sClient, cluster, k8sVersion, privilegedPSP, restrictedPSP)
+	require.NoError(t, err)
+
+	// TestCase: PSP does not match the fixture
+	modifiedPSP := privilegedPSP.DeepCopy()
+	modifiedPSP.Spec.HostNetwork = false
+	k8sClient = FakeK8sClient(modifiedPSP, restrictedPSP.DeepCopy(), clusterRole, clusterRoleBinding)
+	err = ValidatePSP(k8sClient, cluster, k8sVersion, privilegedPSP, restrictedPSP)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "PodSecurityPolicy px-privileged doesn't match expected")
+
+	// TestCase: PSP is missing
+	k8sClient = FakeK8sClient(restrictedPSP.DeepCopy(), clusterRole, clusterRoleBinding)
+	err = ValidatePSP(k8sClient, cluster, k8sVersion, privilegedPSP, restrictedPSP)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to get PodSecurityPolicy px-privileged")
+
+	// TestCase: PSP is not bound to Portworx
+	k8sClient = FakeK8sClient(privilegedPSP.DeepCopy(), restrictedPSP.DeepCopy(), &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{Name: "portworx"},
+	}, clusterRoleBinding)
+	err = ValidatePSP(k8sClient, cluster, k8sVersion, privilegedPSP, restrictedPSP)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "ClusterRole portworx doesn't grant use of PodSecurityPolicy px-privileged")
+
+	k8sClient = FakeK8sClient(privilegedPSP.DeepCopy(), restrictedPSP.DeepCopy(), clusterRole)
+	err = ValidatePSP(k8sClient, cluster, k8sVersion, privilegedPSP, restrictedPSP)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to get ClusterRoleBinding portworx")
+
+	// TestCase: PSPs are skipped on Kubernetes 1.25 and up
+	k8sVersion, _ = version.NewVersion("1.25.0")
+	err = ValidatePSP(FakeK8sClient(), cluster, k8sVersion, privilegedPSP, restrictedPSP)
+	require.NoError(t, err)
+
+	// TestCase: PSPs are skipped when not enabled in the StorageCluster
+	k8sVersion, _ = version.NewVersion("1.24.0")
+	cluster.Annotations = nil
+	err = ValidatePSP(FakeK8sClient(), cluster, k8sVersion, privilegedPSP, restrictedPSP)
+	require.NoError(t, err)
+}
+
+func TestValidatePodSecurity(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "px-cluster",
+			Namespace:   "kube-test",
+			Annotations: map[string]string{"portworx.io/pod-security-policy": "true"},
+		},
+	}
+	namespace := &v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   cluster.Namespace,
+			Labels: map[string]string{"pod-security.kubernetes.io/enforce": "privileged"},
+		},
+	}
+	privilegedPSP := GetExpectedPSP(t, "privilegedPSP.yaml")
+
+	// TestCase: PSPs are validated before k8s 1.25
+	k8sVersion, _ := version.NewVersion("1.24.9")
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(namespace)))
+	err := ValidatePodSecurity(FakeK8sClient(), cluster, k8sVersion, privilegedPSP)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to get PodSecurityPolicy px-privileged")
+
+	// TestCase: Namespace labels are validated from k8s 1.25
+	k8sVersion, _ = version.NewVersion("1.25.0")
+	err = ValidatePodSecurity(FakeK8sClient(), cluster, k8sVersion, privilegedPSP)
+	require.NoError(t, err)
+
+	namespace.Labels["pod-security.kubernetes.io/enforce"] = "baseline"
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(namespace)))
+	err = ValidatePodSecurity(FakeK8sClient(), cluster, k8sVersion, privilegedPSP)
+	require.Error(t, err)
+	require.Equal(t, "namespace kube-test enforces PodSecurity level baseline, expected: privileged", err.Error())
+
+	// TestCase: Namespace without an enforced level
+	namespace.Labels = nil
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(namespace)))
+	err = ValidatePodSecurity(FakeK8sClient(), cluster, k8sVersion, privilegedPSP)
+	require.NoError(t, err)
+}
+
+func TestValidateCSIExtReplicas(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+	}
+	newDeployment := func(replicas *int32, readyReplicas int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "px-csi-ext",
+				Namespace: cluster.Namespace,
+			},
+			Spec:   appsv1.DeploymentSpec{Replicas: replicas},
+			Status: appsv1.DeploymentStatus{ReadyReplicas: readyReplicas},
+		}
+	}
+	setClients := func(objs ...runtime.Object) {
+		c := fakek8sclient.NewSimpleClientset(objs...)
+		appops.SetInstance(appops.New(c.AppsV1(), c.CoreV1()))
+	}
+	replicas := int32(3)
+
+	// TestCase: All desired replicas are ready, independent of the cluster size
+	setClients(newDeployment(&replicas, 3))
+	err := ValidateCSIExtReplicas(cluster, cluster.Namespace)
+	require.NoError(t, err)
+
+	// TestCase: Not all desired replicas are ready, e.g. on a single node cluster
+	setClients(newDeployment(&replicas, 1))
+	err = ValidateCSIExtReplicas(cluster, cluster.Namespace)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "expected ready replicas: 3, actual: 1")
+
+	// TestCase: Deployment scaled down to a single replica
+	replicas = 1
+	setClients(newDeployment(&replicas, 1))
+	err = ValidateCSIExtReplicas(cluster, cluster.Namespace)
+	require.NoError(t, err)
+
+	// TestCase: Replicas default to 1 when not set
+	setClients(newDeployment(nil, 1))
+	err = ValidateCSIExtReplicas(cluster, cluster.Namespace)
+	require.NoError(t, err)
+
+	setClients(newDeployment(nil, 0))
+	err = ValidateCSIExtReplicas(cluster, cluster.Namespace)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "expected ready replicas: 1, actual: 0")
+
+	// TestCase: Deployment is missing
+	setClients()
+	err = ValidateCSIExtReplicas(cluster, cluster.Namespace)
+	require.Error(t, err)
+}
+
+func TestValidateCSIInCustomNamespace(t *testing.T) {
+	csiNamespace := "csi-test"
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+		Spec: corev1.StorageClusterSpec{
+			CSI: &corev1.CSISpec{
+				Enabled:  true,
+				Topology: &corev1.CSITopologySpec{Enabled: true},
+			},
+		},
+	}
+	pxImageList := map[string]string{
+		"version":        "portworx/oci-monitor:2.9.1",
+		"csiProvisioner": "k8s.gcr.io/sig-storage/csi-provisioner:v3.0.0",
+		"csiSnapshotter": "k8s.gcr.io/sig-storage/csi-snapshotter:v4.2.1",
+		"csiResizer":     "k8s.gcr.io/sig-storage/csi-resizer:v1.3.0",
+	}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-csi-ext",
+			Namespace: csiNamespace,
+		},
+	}
+	replicaSet := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "px-csi-ext-123",
+			Namespace:       csiNamespace,
+			UID:             "px-csi-ext-rs-uid",
+			OwnerReferences: []metav1.OwnerReference{{Name: "px-csi-ext"}},
+		},
+	}
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "px-csi-ext-123-abc",
+			Namespace:       csiNamespace,
+			OwnerReferences: []metav1.OwnerReference{{UID: "px-csi-ext-rs-uid"}},
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{
+					Name:  "csi-external-provisioner",
+					Image: pxImageList["csiProvisioner"],
+					Args:  []string{"--feature-gates=Topology=true"},
+				},
+				{Name: "csi-snapshotter", Image: pxImageList["csiSnapshotter"]},
+				{Name: "csi-resizer", Image: pxImageList["csiResizer"]},
+			},
+		},
+	}
+	c := fakek8sclient.NewSimpleClientset(deployment, replicaSet, pod)
+	coreops.SetInstance(coreops.New(c))
+	appops.SetInstance(appops.New(c.AppsV1(), c.CoreV1()))
+
+	// TestCase: px-csi-ext pods are found in the custom namespace
+	err := validateCsiExtImages(cluster, csiNamespace, pxImageList)
+	require.NoError(t, err)
+	err = validateCSITopologySpecs(csiNamespace, cluster.Spec.CSI.Topology, time.Second, 100*time.Millisecond)
+	require.NoError(t, err)
+
+	// TestCase: px-csi-ext is not found in the cluster namespace
+	err = validateCsiExtImages(cluster, cluster.Namespace, pxImageList)
+	require.Error(t, err)
+
+	// TestCase: CSI is disabled but px-csi-ext still exists in the custom namespace
+	cluster.Spec.CSI.Enabled = false
+	err = validateCSI(pxImageList, cluster, "", time.Second, 100*time.Millisecond)
+	require.NoError(t, err)
+	err = validateCSI(pxImageList, cluster, csiNamespace, time.Second, 100*time.Millisecond)
+	require.Error(t, err)
+}
+
+func TestValidateCSIDriver(t *testing.T) {
+	k8sVersion, _ := version.NewVersion("1.21.0")
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+		Spec: corev1.StorageClusterSpec{
+			Image: "portworx/oci-monitor:2.10.0",
+			CSI: &corev1.CSISpec{
+				Enabled: true,
+			},
+		},
+	}
+	attachRequired := false
+	podInfoOnMount := true
+	fsGroupPolicy := storagev1.FileFSGroupPolicy
+	csiDriver := &storagev1.CSIDriver{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "pxd.portworx.com",
+		},
+		Spec: storagev1.CSIDriverSpec{
+			AttachRequired: &attachRequired,
+			PodInfoOnMount: &podInfoOnMount,
+			VolumeLifecycleModes: []storagev1.VolumeLifecycleMode{
+				storagev1.VolumeLifecyclePersistent,
+				storagev1.VolumeLifecycleEphemeral,
+			},
+			FSGroupPolicy: &fsGroupPolicy,
+		},
+	}
+
+	// TestCase: CSIDriver matches the StorageCluster
+	k8sClient := FakeK8sClient(csiDriver.DeepCopy())
+	err := ValidateCSIDriver(k8sClient, cluster, k8sVersion)
+	require.NoError(t, err)
+
+	// TestCase: CSIDriver is not registered
+	k8sClient = FakeK8sClient()
+	err = ValidateCSIDriver(k8sClient, cluster, k8sVersion)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to get CSIDriver pxd.portworx.com")
+
+	// TestCase: CSIDriver spec does not match
+	mismatchedDriver := csiDriver.DeepCopy()
+	mismatchedAttachRequired := true
+	mismatchedDriver.Spec.AttachRequired = &mismatchedAttachRequired
+	mismatchedDriver.Spec.VolumeLifecycleModes = []storagev1.VolumeLifecycleMode{storagev1.VolumeLifecyclePersistent}
+	k8sClient = FakeK8sClient(mismatchedDriver)
+	err = ValidateCSIDriver(k8sClient, cluster, k8sVersion)
+	require.Error(t, err)
+	require.Equal(t, "failed to validate CSIDriver pxd.portworx.com: attachRequired: expected: false, actual: true, "+
+		"volumeLifecycleModes: expected: [Persistent Ephemeral], actual: [Persistent]", err.Error())
+
+	// TestCase: Deprecated driver name without ephemeral support on older Portworx
+	cluster.Spec.Image = "portworx/oci-monitor:2.1.0"
+	deprecatedDriver := csiDriver.DeepCopy()
+	deprecatedDriver.Name = "com.openstorage.pxd"
+	deprecatedDriver.Spec.VolumeLifecycleModes = []storagev1.VolumeLifecycleMode{storagev1.VolumeLifecyclePersistent}
+	k8sClient = FakeK8sClient(deprecatedDriver)
+	err = ValidateCSIDriver(k8sClient, cluster, k8sVersion)
+	require.NoError(t, err)
+
+	// TestCase: No fsGroupPolicy before k8s 1.20
+	k8sVersion, _ = version.NewVersion("1.19.0")
+	err = ValidateCSIDriver(k8sClient, cluster, k8sVersion)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "fsGroupPolicy: expected: nil, actual: File")
+}
+
+func TestValidateCSISnapshotController(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+		Spec: corev1.StorageClusterSpec{
+			CSI: &corev1.CSISpec{
+				Enabled: true,
+			},
+		},
+	}
+	pxImageList := map[string]string{"csiSnapshotController": "k8s.gcr.io/sig-storage/snapshot-controller:v4.2.1"}
+	csiDeployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-csi-ext",
+			Namespace: cluster.Namespace,
+		},
+	}
+	replicaSet := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "px-csi-ext-123",
+			Namespace:       cluster.Namespace,
+			UID:             "px-csi-ext-rs-uid",
+			OwnerReferences: []metav1.OwnerReference{{Name: "px-csi-ext"}},
+		},
+	}
+	newCSIPod := func(containers ...v1.Container) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "px-csi-ext-123-abc",
+				Namespace:       cluster.Namespace,
+				OwnerReferences: []metav1.OwnerReference{{UID: "px-csi-ext-rs-uid"}},
+			},
+			Spec: v1.PodSpec{
+				Containers: append([]v1.Container{
+					{Name: "csi-external-provisioner", Image: "quay.io/openstorage/csi-provisioner:v2.2.2-1"},
+				}, containers...),
+			},
+		}
+	}
+	snapshotClassCRD := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "volumesnapshotclasses.snapshot.storage.k8s.io",
+		},
+	}
+	setup := func(crds []runtime.Object, objs ...runtime.Object) {
+		objs = append(objs, csiDeployment, replicaSet)
+		k8sClient := fakek8sclient.NewSimpleClientset(objs...)
+		coreops.SetInstance(coreops.New(k8sClient))
+		appops.SetInstance(appops.New(k8sClient.AppsV1(), k8sClient.CoreV1()))
+		apiextensionsops.SetInstance(apiextensionsops.New(fakeextclient.NewSimpleClientset(crds...)))
+	}
+	snapshotControllerContainer := v1.Container{
+		Name:  "csi-snapshot-controller",
+		Image: "k8s.gcr.io/sig-storage/snapshot-controller:v4.2.1",
+	}
+
+	// TestCase: Snapshot controller is not enabled, nothing is validated
+	setup(nil, newCSIPod())
+	err := validateCSISnapshotController(cluster, pxImageList)
+	require.NoError(t, err)
+
+	cluster.Spec.CSI.InstallSnapshotController = BoolPtr(false)
+	err = validateCSISnapshotController(cluster, pxImageList)
+	require.NoError(t, err)
+
+	// TestCase: Snapshot controller is running in px-csi-ext pods
+	cluster.Spec.CSI.InstallSnapshotController = BoolPtr(true)
+	setup([]runtime.Object{snapshotClassCRD}, newCSIPod(snapshotControllerContainer))
+	err = validateCSISnapshotController(cluster, pxImageList)
+	require.NoError(t, err)
+
+	// TestCase: VolumeSnapshotClass CRD is missing
+	setup(nil, newCSIPod(snapshotControllerContainer))
+	err = validateCSISnapshotController(cluster, pxImageList)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to get CRD volumesnapshotclasses.snapshot.storage.k8s.io")
+
+	// TestCase: Snapshot controller has the wrong image
+	wrongImageContainer := snapshotControllerContainer
+	wrongImageContainer.Image = "k8s.gcr.io/sig-storage/snapshot-controller:v3.0.3"
+	setup([]runtime.Object{snapshotClassCRD}, newCSIPod(wrongImageContainer))
+	err = validateCSISnapshotController(cluster, pxImageList)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "actual image: k8s.gcr.io/sig-storage/snapshot-controller:v3.0.3")
+
+	// TestCase: Snapshot controller is absent
+	setup([]runtime.Object{snapshotClassCRD}, newCSIPod())
+	err = validateCSISnapshotController(cluster, pxImageList)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to find CSI snapshot controller")
+
+	// TestCase: Snapshot controller was pre-installed outside px-csi-ext
+	preinstalledPod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "snapshot-controller-0",
+			Namespace: "kube-system",
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{Name: "snapshot-controller", Image: "k8s.gcr.io/sig-storage/snapshot-controller:v4.0.0"},
+			},
+		},
+	}
+	setup([]runtime.Object{snapshotClassCRD}, newCSIPod(), preinstalledPod)
+	err = validateCSISnapshotController(cluster, pxImageList)
+	require.NoError(t, err)
+}
+
+func TestValidateImageTag(t *testing.T) {
+	newPod := func(name, image string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "kube-test",
+				Labels:    map[string]string{"name": "stork"},
+			},
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{{Name: "stork", Image: image}},
+			},
+		}
+	}
+	labels := map[string]string{"name": "stork"}
+
+	require.Equal(t, "2.9", getImageTag("openstorage/stork:2.9"))
+	require.Equal(t, "2.9", getImageTag("registry.local:5000/px/stork:2.9"))
+	require.Equal(t, "2.9", getImageTag("registry.local:5000/px/stork:2.9@sha256:abc123"))
+	require.Equal(t, "sha256:abc123", getImageTag("registry.local:5000/px/stork@sha256:abc123"))
+	require.Equal(t, "", getImageTag("registry.local:5000/px/stork"))
+	require.Equal(t, "", getImageTag("stork"))
+
+	// TestCase: Plain, registry with port and digest forms with the expected tag
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(
+		newPod("stork-1", "openstorage/stork:2.9"),
+		newPod("stork-2", "registry.local:5000/px/stork:2.9"),
+		newPod("stork-3", "registry.local:5000/px/stork:2.9@sha256:abc123"),
+	)))
+	err := validateImageTag("2.9", "kube-test", labels)
+	require.NoError(t, err)
+
+	// TestCase: Registry with port and a different tag
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(
+		newPod("stork-1", "registry.local:5000/px/stork:2.8"),
+	)))
+	err = validateImageTag("2.9", "kube-test", labels)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Expected: 2.9 Got: 2.8")
+
+	// TestCase: Image pinned only by digest
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(
+		newPod("stork-1", "registry.local:5000/px/stork@sha256:abc123"),
+	)))
+	err = validateImageTag("sha256:abc123", "kube-test", labels)
+	require.NoError(t, err)
+}
+
+func TestValidateImageOnPods(t *testing.T) {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-pod",
+			Namespace: "kube-test",
+			Labels:    map[string]string{"name": "portworx"},
+		},
+		Spec: v1.PodSpec{
+			InitContainers: []v1.Container{{Name: "px-init", Image: "portworx/px-init:1.0.0"}},
+			Containers: []v1.Container{
+				{Name: "portworx", Image: "portworx/oci-monitor:2.10.0"},
+				{Name: "csi-node-driver-registrar", Image: "quay.io/k8scsi/csi-node-driver-registrar:v2.0.0"},
+			},
+		},
+	}
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(pod)))
+	labels := map[string]string{"name": "portworx"}
+
+	// TestCase: Image on any container
+	err := validateImageOnPods("portworx/oci-monitor:2.10.0", pod.Namespace, labels)
+	require.NoError(t, err)
+
+	// TestCase: Image on an init container
+	err = validateImageOnPods("portworx/px-init:1.0.0", pod.Namespace, labels)
+	require.NoError(t, err)
+
+	err = validateImageOnPods("portworx/px-init:1.0.0", pod.Namespace, labels, "px-init")
+	require.NoError(t, err)
+
+	// TestCase: Image not present on any container
+	err = validateImageOnPods("portworx/oci-monitor:2.11.0", pod.Namespace, labels)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "none of the containers or init containers has the image")
+
+	// TestCase: Image on the named container
+	err = validateImageOnPods("portworx/oci-monitor:2.10.0", pod.Namespace, labels, "portworx")
+	require.NoError(t, err)
+
+	// TestCase: Image present on the pod but not on the named container
+	err = validateImageOnPods("portworx/oci-monitor:2.10.0", pod.Namespace, labels, "csi-node-driver-registrar")
+	require.Error(t, err)
+	require.Equal(t, "failed to validate image on pod px-pod, expected container csi-node-driver-registrar "+
+		"to have image portworx/oci-monitor:2.10.0, actual image: quay.io/k8scsi/csi-node-driver-registrar:v2.0.0",
+		err.Error())
+
+	// TestCase: Named container not present on the pod
+	err = validateImageOnPods("portworx/oci-monitor:2.10.0", pod.Namespace, labels, "stork")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "expected container stork was not found")
+}
+
+func TestValidateStorkSchedulerImage(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+	}
+	newSchedulerPod := func(image string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "stork-scheduler-123",
+				Namespace: cluster.Namespace,
+				Labels:    map[string]string{"name": "stork-scheduler"},
+			},
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{{Name: "stork-scheduler", Image: image}},
+			},
+		}
+	}
+	setupSchedulerPod := func(image, k8sVersion string) {
+		k8sClient := fakek8sclient.NewSimpleClientset(newSchedulerPod(image))
+		k8sClient.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &k8sversion.Info{
+			GitVersion: k8sVersion,
+		}
+		coreops.SetInstance(coreops.New(k8sClient))
+	}
+
+	// TestCase: Scheduler image is pinned to v1.21.4 on k8s 1.22 and up
+	setupSchedulerPod("k8s.gcr.io/kube-scheduler-amd64:v1.21.4", "v1.26.4")
+	err := validateStorkSchedulerImage(cluster)
+	require.NoError(t, err)
+
+	setupSchedulerPod("k8s.gcr.io/kube-scheduler-amd64:v1.26.4", "v1.26.4")
+	err = validateStorkSchedulerImage(cluster)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "k8s.gcr.io/kube-scheduler-amd64:v1.21.4")
+
+	// TestCase: Scheduler image uses the k8s version on k8s older than 1.22
+	setupSchedulerPod("k8s.gcr.io/kube-scheduler-amd64:v1.20.4", "v1.20.4")
+	err = validateStorkSchedulerImage(cluster)
+	require.NoError(t, err)
+
+	setupSchedulerPod("k8s.gcr.io/kube-scheduler-amd64:v1.21.4", "v1.20.4")
+	err = validateStorkSchedulerImage(cluster)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "k8s.gcr.io/kube-scheduler-amd64:v1.20.4")
+
+	// TestCase: Scheduler image uses the old registry on older k8s versions
+	setupSchedulerPod("gcr.io/google_containers/kube-scheduler-amd64:v1.16.2", "v1.16.2")
+	err = validateStorkSchedulerImage(cluster)
+	require.NoError(t, err)
+
+	// TestCase: Scheduler image uses the custom registry of the cluster
+	cluster.Spec.CustomImageRegistry = "registry.local:5000"
+	setupSchedulerPod("registry.local:5000/k8s.gcr.io/kube-scheduler-amd64:v1.21.4", "v1.26.4")
+	err = validateStorkSchedulerImage(cluster)
+	require.NoError(t, err)
+}
+
+func TestValidateStorkResourcesAndTolerations(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+		Spec: corev1.StorageClusterSpec{
+			Placement: &corev1.PlacementSpec{
+				Tolerations: []v1.Toleration{
+					{
+						Key:      "dedicated",
+						Operator: v1.TolerationOpEqual,
+						Value:    "stork",
+						Effect:   v1.TaintEffectNoSchedule,
+					},
+				},
+			},
+		},
+	}
+	storkDeployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "stork",
+			Namespace: cluster.Namespace,
+		},
+	}
+	replicaSet := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "stork-123",
+			Namespace:       cluster.Namespace,
+			UID:             "stork-rs-uid",
+			OwnerReferences: []metav1.OwnerReference{{Name: "stork"}},
+		},
+	}
+	storkPod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "stork-123-abc",
+			Namespace:       cluster.Namespace,
+			OwnerReferences: []metav1.OwnerReference{{UID: "stork-rs-uid"}},
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{
+					Name: "stork",
+					Resources: v1.ResourceRequirements{
+						Requests: v1.ResourceList{
+							v1.ResourceCPU: resource.MustParse("100m"),
+						},
+					},
+				},
+			},
+			Tolerations: []v1.Toleration{
+				{
+					Key:      "node.kubernetes.io/not-ready",
+					Operator: v1.TolerationOpExists,
+					Effect:   v1.TaintEffectNoExecute,
+				},
+				cluster.Spec.Placement.Tolerations[0],
+			},
+		},
+	}
+	setupStorkPod := func(pod *v1.Pod) {
+		k8sClient := fakek8sclient.NewSimpleClientset(storkDeployment, replicaSet, pod)
+		appops.SetInstance(appops.New(k8sClient.AppsV1(), k8sClient.CoreV1()))
+	}
+
+	// TestCase: Pods carry the default resources and the configured tolerations
+	setupStorkPod(storkPod)
+	err := validateStorkResources(cluster, storkDeployment, time.Second, 100*time.Millisecond)
+	require.NoError(t, err)
+	err = validateStorkTolerations(cluster, storkDeployment, time.Second, 100*time.Millisecond)
+	require.NoError(t, err)
+
+	// TestCase: Pod cpu request does not match the cpu annotation
+	cluster.Annotations = map[string]string{"operator.libopenstorage.org/stork-cpu": "250m"}
+	err = validateStorkResources(cluster, storkDeployment, time.Second, 100*time.Millisecond)
+	require.Error(t, err)
+	err = validateStorkPodResources(*storkPod, resource.MustParse("250m"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Stork pod [stork-123-abc]: expected cpu request: 250m, actual: 100m")
+
+	pod := storkPod.DeepCopy()
+	pod.Spec.Containers[0].Resources.Requests[v1.ResourceCPU] = resource.MustParse("0.25")
+	setupStorkPod(pod)
+	err = validateStorkResources(cluster, storkDeployment, time.Second, 100*time.Millisecond)
+	require.NoError(t, err)
+
+	// TestCase: Pod is missing the configured toleration
+	pod = storkPod.DeepCopy()
+	pod.Spec.Tolerations = pod.Spec.Tolerations[:1]
+	setupStorkPod(pod)
+	err = validateStorkTolerations(cluster, storkDeployment, time.Second, 100*time.Millisecond)
+	require.Error(t, err)
+	err = validateStorkPodTolerations(*pod, cluster.Spec.Placement.Tolerations)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to validate Stork tolerations inside Stork pod [stork-123-abc]")
+
+	// TestCase: Pod toleration differs from the configured toleration
+	pod = storkPod.DeepCopy()
+	pod.Spec.Tolerations[1].Value = "other"
+	setupStorkPod(pod)
+	err = validateStorkTolerations(cluster, storkDeployment, time.Second, 100*time.Millisecond)
+	require.Error(t, err)
+
+	// TestCase: No tolerations configured in the cluster
+	cluster.Spec.Placement = nil
+	err = validateStorkTolerations(cluster, storkDeployment, time.Second, 100*time.Millisecond)
+	require.NoError(t, err)
+}
+
+func TestValidateStorkWebhookController(t *testing.T) {
+	storkDeployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "stork",
+			Namespace: "kube-test",
+		},
+	}
+	replicaSet := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "stork-123",
+			Namespace:       "kube-test",
+			UID:             "stork-rs-uid",
+			OwnerReferences: []metav1.OwnerReference{{Name: "stork"}},
+		},
+	}
+	storkPod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "stork-123-abc",
+			Namespace:       "kube-test",
+			OwnerReferences: []metav1.OwnerReference{{UID: "stork-rs-uid"}},
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{
+					Name:    "stork",
+					Command: []string{"/stork", "--verbose", "--webhook-controller=true"},
+				},
+			},
+		},
+	}
+	k8sClient := fakek8sclient.NewSimpleClientset(storkDeployment, replicaSet, storkPod)
+	appops.SetInstance(appops.New(k8sClient.AppsV1(), k8sClient.CoreV1()))
+	webhookControllerArgs := map[string]string{"webhook-controller": "true"}
+
+	// TestCase: Flag passed in the command with an "=" separator
+	err := validateStorkWebhookController(webhookControllerArgs, storkDeployment, time.Second, 100*time.Millisecond)
+	require.NoError(t, err)
+
+	// TestCase: Flag passed in the args with an "=" separator
+	pod := storkPod.DeepCopy()
+	pod.Spec.Containers[0].Command = []string{"/stork"}
+	pod.Spec.Containers[0].Args = []string{"--verbose", "--webhook-controller=true"}
+	err = validateStorkPodWebhookController(*pod, "true")
+	require.NoError(t, err)
+
+	// TestCase: Flag passed in the args with a space separator
+	pod.Spec.Containers[0].Args = []string{"--webhook-controller", "true", "--verbose"}
+	err = validateStorkPodWebhookController(*pod, "true")
+	require.NoError(t, err)
+
+	// TestCase: Flag passed in a single command string with a space separator
+	pod.Spec.Containers[0].Command = []string{"/stork --webhook-controller false"}
+	pod.Spec.Containers[0].Args = nil
+	err = validateStorkPodWebhookController(*pod, "false")
+	require.NoError(t, err)
+
+	// TestCase: Flag passed without a value is treated as true
+	pod.Spec.Containers[0].Command = []string{"/stork"}
+	pod.Spec.Containers[0].Args = []string{"--webhook-controller", "--verbose"}
+	err = validateStorkPodWebhookController(*pod, "true")
+	require.NoError(t, err)
+
+	// TestCase: Wrong flag value in the args
+	pod.Spec.Containers[0].Args = []string{"--webhook-controller", "false"}
+	err = validateStorkPodWebhookController(*pod, "true")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "wrong --webhook-controller value in the command in Stork pod [stork-123-abc]: expected: true, got: false")
+
+	// TestCase: Flag set in the pod but not in the StorageCluster
+	err = validateStorkPodWebhookController(*pod, "")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "webhook-controller is missing from Stork args in the StorageCluster")
+
+	// TestCase: Flag set in the StorageCluster but missing from the pod
+	pod.Spec.Containers[0].Args = []string{"--verbose"}
+	err = validateStorkPodWebhookController(*pod, "true")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "webhook-controller is found in Stork args in the StorageCluster, but missing from Stork pod [stork-123-abc]")
+
+	// TestCase: Flag missing from both the pod and the StorageCluster
+	err = validateStorkPodWebhookController(*pod, "")
+	require.NoError(t, err)
+
+	// TestCase: Only stork containers are validated
+	pod.Spec.Containers = append(pod.Spec.Containers, v1.Container{
+		Name: "sidecar",
+		Args: []string{"--webhook-controller=false"},
+	})
+	err = validateStorkPodWebhookController(*pod, "")
+	require.NoError(t, err)
+
+	// TestCase: Wrong flag value in the command of a running pod
+	err = validateStorkWebhookController(map[string]string{"webhook-controller": "false"}, storkDeployment, time.Second, 100*time.Millisecond)
+	require.Error(t, err)
+}
+
+func TestValidateStorkSchedulerConfig(t *testing.T) {
+	configMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "stork-config",
+			Namespace: "kube-test",
+		},
+		Data: map[string]string{
+			"policy.cfg": `{"kind":"Policy","apiVersion":"v1","extenders":[{"urlPrefix":"http://stork-service.kube-test:8099",` +
+				`"filterVerb":"filter","prioritizeVerb":"prioritize","weight":5,"enableHttps":false,"nodeCacheCapable":false,"httpTimeout":300000000000}]}`,
+		},
+	}
+	k8sClient := fakek8sclient.NewSimpleClientset(configMap)
+	coreops.SetInstance(coreops.New(k8sClient))
+
+	// TestCase: Scheduler policy with the Stork extender
+	err := ValidateStorkSchedulerConfig("kube-test")
+	require.NoError(t, err)
+
+	// TestCase: Scheduler policy encoded by the legacy encoder
+	err = validateStorkSchedulerExtender(`{"kind":"Policy","apiVersion":"v1","Extenders":[{"URLPrefix":"http://stork-service.kube-test:8099",`+
+		`"FilterVerb":"filter","PrioritizeVerb":"prioritize","Weight":5}]}`, "Policy", "kube-test")
+	require.NoError(t, err)
+
+	// TestCase: Scheduler policy with the wrong filterVerb
+	configMap.Data["policy.cfg"] = `{"kind":"Policy","apiVersion":"v1","extenders":[{"urlPrefix":"http://stork-service.kube-test:8099"}]}`
+	_, err = k8sClient.CoreV1().ConfigMaps("kube-test").Update(context.TODO(), configMap, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	err = ValidateStorkSchedulerConfig("kube-test")
+	require.Error(t, err)
+	require.Equal(t, "failed to validate policy.cfg in config map kube-test/stork-config, "+
+		"Err: stork extender filterVerb: expected: filter, actual: ", err.Error())
+
+	// TestCase: Scheduler policy pointing to Stork in another namespace
+	configMap.Data["policy.cfg"] = `{"kind":"Policy","apiVersion":"v1","extenders":[{"urlPrefix":"http://stork-service.kube-system:8099","filterVerb":"filter"}]}`
+	_, err = k8sClient.CoreV1().ConfigMaps("kube-test").Update(context.TODO(), configMap, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	err = ValidateStorkSchedulerConfig("kube-test")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no extender found with urlPrefix http://stork-service.kube-test:8099")
+
+	// TestCase: KubeSchedulerConfiguration with the Stork extender
+	configMap.Data = map[string]string{
+		"stork-config.yaml": `apiVersion: kubescheduler.config.k8s.io/v1beta3
+kind: KubeSchedulerConfiguration
+leaderElection:
+  leaderElect: true
+profiles:
+- schedulerName: stork
+extenders:
+- urlPrefix: http://stork-service.kube-test:8099
+  filterVerb: filter
+  prioritizeVerb: prioritize
+  weight: 5
+  enableHTTPS: false
+  nodeCacheCapable: false
+`,
+	}
+	_, err = k8sClient.CoreV1().ConfigMaps("kube-test").Update(context.TODO(), configMap, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	err = ValidateStorkSchedulerConfig("kube-test")
+	require.NoError(t, err)
+
+	// TestCase: KubeSchedulerConfiguration without any extenders
+	configMap.Data["stork-config.yaml"] = `apiVersion: kubescheduler.config.k8s.io/v1beta3
+kind: KubeSchedulerConfiguration
+profiles:
+- schedulerName: stork
+`
+	_, err = k8sClient.CoreV1().ConfigMaps("kube-test").Update(context.TODO(), configMap, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	err = ValidateStorkSchedulerConfig("kube-test")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to validate stork-config.yaml in config map kube-test/stork-config, Err: no extender found")
+
+	// TestCase: Config with an unexpected kind
+	err = validateStorkSchedulerExtender("kind: Policy\n", "KubeSchedulerConfiguration", "kube-test")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "kind: expected: KubeSchedulerConfiguration, actual: Policy")
+
+	// TestCase: Config map without a scheduler config
+	configMap.Data = map[string]string{"other": ""}
+	_, err = k8sClient.CoreV1().ConfigMaps("kube-test").Update(context.TODO(), configMap, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	err = ValidateStorkSchedulerConfig("kube-test")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "missing policy.cfg or stork-config.yaml in config map kube-test/stork-config")
+
+	// TestCase: Config map is missing
+	err = ValidateStorkSchedulerConfig("other-namespace")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to get config map other-namespace/stork-config")
+}
+
+func TestValidateTelemetryTrustBundle(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+	}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-metrics-collector",
+			Namespace: "kube-test",
+		},
+		Spec: appsv1.DeploymentSpec{
+			Template: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{
+						{Name: "collector"},
+						{
+							Name: "envoy",
+							VolumeMounts: []v1.VolumeMount{
+								{Name: "px-collector-proxy-config", MountPath: "/config"},
+								{Name: "pure-telemetry-certs", MountPath: TelemetryCertMountPath},
+							},
+						},
+					},
+					Volumes: []v1.Volume{
+						{
+							Name: "pure-telemetry-certs",
+							VolumeSource: v1.VolumeSource{
+								Secret: &v1.SecretVolumeSource{SecretName: TelemetryCertSecretName},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	configMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-collector-proxy-config",
+			Namespace: "kube-test",
+		},
+		Data: map[string]string{
+			"envoy-config.yaml": "certificate_chain:\n  filename: /appliance-cert/cert\n" +
+				"private_key:\n  filename: /appliance-cert/private_key\n",
+		},
+	}
+	k8sClient := fakek8sclient.NewSimpleClientset(deployment, configMap)
+	appops.SetInstance(appops.New(k8sClient.AppsV1(), k8sClient.CoreV1()))
+	coreops.SetInstance(coreops.New(k8sClient))
+
+	// TestCase: Telemetry certificate is mounted and referenced by the envoy config
+	err := ValidateTelemetryTrustBundle(cluster, time.Second, 100*time.Millisecond)
+	require.NoError(t, err)
+
+	// TestCase: Envoy config does not reference the private key
+	configMap.Data["envoy-config.yaml"] = "certificate_chain:\n  filename: /appliance-cert/cert\n"
+	_, err = k8sClient.CoreV1().ConfigMaps("kube-test").Update(context.TODO(), configMap, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	err = ValidateTelemetryTrustBundle(cluster, time.Second, 100*time.Millisecond)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "does not reference /appliance-cert/private_key")
+
+	// TestCase: Telemetry certificate mounted at a different path
+	deployment.Spec.Template.Spec.Containers[1].VolumeMounts[1].MountPath = "/etc/ssl/certs"
+	_, err = k8sClient.AppsV1().Deployments("kube-test").Update(context.TODO(), deployment, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	err = ValidateTelemetryTrustBundle(cluster, time.Second, 100*time.Millisecond)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "missing mount of volume pure-telemetry-certs (secret pure-telemetry-certs) at "+TelemetryCertMountPath)
+
+	// TestCase: Telemetry certificate secret is not mounted
+	deployment.Spec.Template.Spec.Volumes = nil
+	_, err = k8sClient.AppsV1().Deployments("kube-test").Update(context.TODO(), deployment, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	err = ValidateTelemetryTrustBundle(cluster, time.Second, 100*time.Millisecond)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "missing volume for secret pure-telemetry-certs")
+}
+
+func TestValidateStorageClusterWithReport(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+		Spec: corev1.StorageClusterSpec{
+			Image: "portworx/oci-monitor:2.10.1",
+		},
+		Status: corev1.StorageClusterStatus{
+			Phase: string(corev1.ClusterInit),
+		},
+	}
+	operatorClient := fakeoperatorclient.NewSimpleClientset(cluster.DeepCopy())
+	operatorops.SetInstance(operatorops.New(operatorClient))
+
+	// Bring the cluster online after a few attempts
+	updateErr := make(chan error, 1)
+	go func() {
+		time.Sleep(350 * time.Millisecond)
+		liveCluster := cluster.DeepCopy()
+		liveCluster.Status.Phase = string(corev1.ClusterOnline)
+		liveCluster.Spec.Env = []v1.EnvVar{{Name: "UNEXPECTED", Value: "true"}}
+		_, err := operatorClient.CoreV1().StorageClusters(cluster.Namespace).Update(context.TODO(), liveCluster, metav1.UpdateOptions{})
+		updateErr <- err
+	}()
+
+	// TestCase: Report captures the retries and duration of every check up to the failed one
+	report, err := ValidateStorageClusterWithReport(nil, cluster, 2*time.Second, 100*time.Millisecond, true)
+	require.NoError(t, <-updateErr)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "spec.env")
+	require.Len(t, report.Checks, 2)
+
+	require.Equal(t, "StorageClusterIsOnline", report.Checks[0].Name)
+	require.NoError(t, report.Checks[0].Err)
+	require.GreaterOrEqual(t, report.Checks[0].Attempts, 3)
+	require.GreaterOrEqual(t, report.Checks[0].Duration, 300*time.Millisecond)
+
+	require.Equal(t, "DeployedSpec", report.Checks[1].Name)
+	require.Equal(t, err, report.Checks[1].Err)
+	require.Equal(t, 0, report.Checks[1].Attempts)
+
+	require.Equal(t, report.Checks[0].Duration+report.Checks[1].Duration, report.Duration)
+
+	// TestCase: Report captures the attempts of a check that timed out
+	_, err = operatorClient.CoreV1().StorageNodes(cluster.Namespace).Create(context.TODO(), &corev1.StorageNode{
+		ObjectMeta: metav1.ObjectMeta{Name: "node1", Namespace: cluster.Namespace},
+		Status:     corev1.NodeStatus{Phase: string(corev1.NodeOnlineStatus)},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	report, err = ValidateStorageClusterWithReport(nil, cluster, time.Second, 100*time.Millisecond, false)
+	require.Error(t, err)
+	require.Len(t, report.Checks, 1)
+	require.Equal(t, "StorageClusterIsFailed", report.Checks[0].Name)
+	require.Equal(t, err, report.Checks[0].Err)
+	require.Greater(t, report.Checks[0].Attempts, 1)
+	require.GreaterOrEqual(t, report.Checks[0].Duration, time.Second)
+
+	// TestCase: Attempts of concurrent checks are counted separately
+	var wg sync.WaitGroup
+	retryErrs := make(chan error, 2)
+	counters := make([]*int64, 2)
+	for i, succeedOn := range []int{2, 4} {
+		ctx, attempts := withRetryAttempts(context.Background())
+		counters[i] = attempts
+		wg.Add(1)
+		go func(ctx context.Context, succeedOn int) {
+			defer wg.Done()
+			calls := 0
+			_, err := doRetryWithContext(ctx, func() (interface{}, bool, error) {
+				if calls++; calls < succeedOn {
+					return nil, true, fmt.Errorf("attempt %d", calls)
+				}
+				return nil, false, nil
+			}, time.Second, 10*time.Millisecond)
+			retryErrs <- err
+		}(ctx, succeedOn)
+	}
+	wg.Wait()
+	close(retryErrs)
+	for err := range retryErrs {
+		require.NoError(t, err)
+	}
+	require.Equal(t, int64(2), atomic.LoadInt64(counters[0]))
+	require.Equal(t, int64(4), atomic.LoadInt64(counters[1]))
+}
+
+func TestValidateStorageClusterWithOptions(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+			UID:       "px-cluster-uid",
+		},
+		Spec: corev1.StorageClusterSpec{
+			Image: "portworx/oci-monitor:2.10.1",
+		},
+		Status: corev1.StorageClusterStatus{
+			Phase: string(corev1.ClusterOnline),
+		},
+	}
+	storageNode := &corev1.StorageNode{
+		ObjectMeta: metav1.ObjectMeta{Name: "node1", Namespace: cluster.Namespace},
+		Spec:       corev1.StorageNodeSpec{Version: "2.10.1"},
+		Status:     corev1.NodeStatus{Phase: string(corev1.NodeOnlineStatus)},
+	}
+	pxPod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "px-pod-node1",
+			Namespace:       cluster.Namespace,
+			OwnerReferences: []metav1.OwnerReference{{UID: cluster.UID}},
+		},
+		Spec: v1.PodSpec{NodeName: "node1"},
+		Status: v1.PodStatus{
+			Phase:      v1.PodRunning,
+			Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}},
+			ContainerStatuses: []v1.ContainerStatus{
+				{
+					Name:  "portworx",
+					Ready: true,
+					State: v1.ContainerState{Running: &v1.ContainerStateRunning{}},
+				},
+			},
+		},
+	}
+	operatorops.SetInstance(operatorops.New(fakeoperatorclient.NewSimpleClientset(cluster, storageNode)))
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}},
+		pxPod,
+	)))
+	pxImageList := map[string]string{"version": "portworx/oci-monitor:2.10.1"}
+	getCheckNames := func(report *ValidationReport) []string {
+		var names []string
+		for _, check := range report.Checks {
+			names = append(names, check.Name)
+		}
+		return names
+	}
+
+	opts := &ValidateOptions{
+		PxImageList:             pxImageList,
+		ClusterSpec:             cluster,
+		Timeout:                 time.Second,
+		Interval:                100 * time.Millisecond,
+		ShouldStartSuccessfully: true,
+	}
+
+	// TestCase: Portworx nodes are validated through the SDK by default. The validation
+	// fails later as there is no portworx-service in the cluster.
+	report := &ValidationReport{}
+	err := validateStorageCluster(context.TODO(), opts, report)
+	require.Error(t, err)
+	require.Equal(t, []string{
+		"StorageClusterIsOnline",
+		"DeployedSpec",
+		"StorageNodes",
+		"StorageClusterPods",
+		"PortworxNodes",
+		"PortworxService",
+	}, getCheckNames(report))
+
+	// TestCase: Options form fails the same way as the positional form
+	err = ValidateStorageClusterWithOptions(opts)
+	require.Error(t, err)
+	require.Equal(t, report.Checks[len(report.Checks)-1].Err.Error(), err.Error())
+	positionalErr := ValidateStorageCluster(pxImageList, cluster, time.Second, 100*time.Millisecond, true)
+	require.Error(t, positionalErr)
+	require.Equal(t, positionalErr.Error(), err.Error())
+
+	// TestCase: SDK node validation is skipped while the pods and services are still validated
+	opts.SkipSDKNodeValidation = true
+	report = &ValidationReport{}
+	err = validateStorageCluster(context.TODO(), opts, report)
+	require.Error(t, err)
+	require.Equal(t, []string{
+		"StorageClusterIsOnline",
+		"DeployedSpec",
+		"StorageNodes",
+		"StorageClusterPods",
+		"PortworxService",
+	}, getCheckNames(report))
+
+	err = ValidateStorageClusterWithOptions(opts)
+	require.Error(t, err)
+	require.Equal(t, report.Checks[len(report.Checks)-1].Err.Error(), err.Error())
+
+	// TestCase: Cluster expected to fail only checks the cluster phase
+	opts = &ValidateOptions{
+		ClusterSpec: cluster,
+		Timeout:     time.Second,
+		Interval:    100 * time.Millisecond,
+	}
+	report = &ValidationReport{}
+	err = validateStorageCluster(context.TODO(), opts, report)
+	require.Error(t, err)
+	require.Equal(t, []string{"StorageClusterIsFailed"}, getCheckNames(report))
+
+	// TestCase: Options without a cluster
+	err = ValidateStorageClusterWithOptions(&ValidateOptions{})
+	require.EqualError(t, err, "no StorageCluster given to validate")
+	err = ValidateStorageClusterWithOptions(nil)
+	require.EqualError(t, err, "no StorageCluster given to validate")
+}
+
+func TestNewValidateOptions(t *testing.T) {
+	cluster := &corev1.StorageCluster{ObjectMeta: metav1.ObjectMeta{Name: "px-cluster"}}
+	pxImageList := map[string]string{"version": "portworx/oci-monitor:2.10.1"}
+
+	opts := newValidateOptions(pxImageList, cluster, time.Minute, time.Second, true, "/tmp/kubeconfig")
+	require.Equal(t, &ValidateOptions{
+		PxImageList:             pxImageList,
+		ClusterSpec:             cluster,
+		Timeout:                 time.Minute,
+		Interval:                time.Second,
+		ShouldStartSuccessfully: true,
+		Kubeconfig:              "/tmp/kubeconfig",
+	}, opts)
+
+	opts = newValidateOptions(nil, cluster, time.Minute, time.Second, false)
+	require.Empty(t, opts.Kubeconfig)
+	require.False(t, opts.ShouldStartSuccessfully)
+	require.False(t, opts.SkipSDKNodeValidation)
+}
+
+func TestValidateStorageClusterCtx(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+		Status: corev1.StorageClusterStatus{
+			Phase: string(corev1.ClusterInit),
+		},
+	}
+	operatorops.SetInstance(operatorops.New(fakeoperatorclient.NewSimpleClientset(cluster)))
+
+	// TestCase: Validation is aborted promptly when the context is cancelled, long
+	// before the retry timeout of the cluster phase check is reached
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(300 * time.Millisecond)
+		cancel()
+	}()
+	start := time.Now()
+	err := ValidateStorageClusterCtx(ctx, nil, cluster, time.Minute, 100*time.Millisecond, true)
+	require.Error(t, err)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Less(t, int64(time.Since(start)), int64(5*time.Second))
+
+	// TestCase: No check is started with an already cancelled context
+	report := &ValidationReport{}
+	err = validateStorageCluster(ctx, &ValidateOptions{ClusterSpec: cluster, Timeout: time.Minute, Interval: time.Second}, report)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Contains(t, err.Error(), "cancelled before StorageClusterIsFailed check")
+	require.Empty(t, report.Checks)
+
+	// TestCase: Deadline of the context is reported same as a cancellation
+	ctx, cancel = context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	err = ValidateStorageClusterWithOptionsCtx(ctx, &ValidateOptions{
+		ClusterSpec:             cluster,
+		Timeout:                 time.Minute,
+		Interval:                100 * time.Millisecond,
+		ShouldStartSuccessfully: true,
+	})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestDoRetryWithContext(t *testing.T) {
+	// TestCase: Retry timeout is reported as a task timeout. The counter is atomic,
+	// as the task may still be running when the timeout is returned.
+	var timedOutAttempts int64
+	_, err := doRetryWithContext(context.Background(), func() (interface{}, bool, error) {
+		atomic.AddInt64(&timedOutAttempts, 1)
+		return nil, true, fmt.Errorf("not ready")
+	}, 300*time.Millisecond, 100*time.Millisecond)
+	require.Error(t, err)
+	require.IsType(t, &task.ErrTimedOut{}, err)
+	require.Greater(t, atomic.LoadInt64(&timedOutAttempts), int64(1))
+
+	// TestCase: Cancelled context stops the retries
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	cancelledAttempts := 0
+	_, err = doRetryWithContext(ctx, func() (interface{}, bool, error) {
+		cancelledAttempts++
+		return nil, true, fmt.Errorf("not ready")
+	}, time.Minute, 100*time.Millisecond)
+	require.Equal(t, context.Canceled, err)
+	require.Zero(t, cancelledAttempts)
+
+	// TestCase: Task that should not be retried
+	_, err = doRetryWithContext(context.Background(), func() (interface{}, bool, error) {
+		return nil, false, fmt.Errorf("fatal")
+	}, time.Minute, 100*time.Millisecond)
+	require.EqualError(t, err, "fatal")
+
+	// TestCase: Task that succeeds after a few attempts
+	succeedingAttempts := 0
+	out, err := doRetryWithContext(context.Background(), func() (interface{}, bool, error) {
+		succeedingAttempts++
+		if succeedingAttempts < 3 {
+			return nil, true, fmt.Errorf("not ready")
+		}
+		return "done", false, nil
+	}, time.Minute, 10*time.Millisecond)
+	require.NoError(t, err)
+	require.Equal(t, "done", out)
+	require.Equal(t, 3, succeedingAttempts)
+}
+
+func TestFakeK8sClientRegistersSchemes(t *testing.T) {
+	k8sClient := FakeK8sClient()
+
+	// TestCase: CustomResourceDefinition can be created and read back
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "storageclusters.core.libopenstorage.org",
+		},
+	}
+	err := k8sClient.Create(context.TODO(), crd)
+	require.NoError(t, err)
+	err = Get(k8sClient, &apiextensionsv1.CustomResourceDefinition{}, crd.Name, "")
+	require.NoError(t, err)
+
+	// TestCase: PodSecurityPolicy can be created and read back
+	psp := &policyv1beta1.PodSecurityPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "px-restricted",
+		},
+	}
+	err = k8sClient.Create(context.TODO(), psp)
+	require.NoError(t, err)
+	err = Get(k8sClient, &policyv1beta1.PodSecurityPolicy{}, psp.Name, "")
+	require.NoError(t, err)
+
+	// TestCase: Creating another client does not fail on the shared scheme
+	k8sClient = FakeK8sClient(crd.DeepCopy())
+	crdList := &apiextensionsv1.CustomResourceDefinitionList{}
+	err = List(k8sClient, crdList)
+	require.NoError(t, err)
+	require.Len(t, crdList.Items, 1)
+}
+
+func TestFakeK8sClientStatusUpdate(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+	}
+	node := &corev1.StorageNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "node1",
+			Namespace: "kube-test",
+		},
+	}
+	k8sClient := FakeK8sClient(cluster, node)
+
+	// TestCase: StorageCluster status update is persisted
+	cluster = &corev1.StorageCluster{}
+	err := Get(k8sClient, cluster, "px-cluster", "kube-test")
+	require.NoError(t, err)
+	cluster.Status.Phase = string(corev1.ClusterOnline)
+	cluster.Status.ClusterUID = "test-clusteruid"
+	err = k8sClient.Status().Update(context.TODO(), cluster)
+	require.NoError(t, err)
+
+	cluster = &corev1.StorageCluster{}
+	err = Get(k8sClient, cluster, "px-cluster", "kube-test")
+	require.NoError(t, err)
+	require.Equal(t, string(corev1.ClusterOnline), cluster.Status.Phase)
+	require.Equal(t, "test-clusteruid", cluster.Status.ClusterUID)
+
+	// TestCase: StorageNode status update is persisted
+	node = &corev1.StorageNode{}
+	err = Get(k8sClient, node, "node1", "kube-test")
+	require.NoError(t, err)
+	node.Status.Phase = string(corev1.NodeOnlineStatus)
+	err = k8sClient.Status().Update(context.TODO(), node)
+	require.NoError(t, err)
+
+	node = &corev1.StorageNode{}
+	err = Get(k8sClient, node, "node1", "kube-test")
+	require.NoError(t, err)
+	require.Equal(t, string(corev1.NodeOnlineStatus), node.Status.Phase)
+}
+
+func TestNewResourceVersion(t *testing.T) {
+	// TestCase: Versions strictly increase and keep the same length
+	prev := NewResourceVersion()
+	require.Len(t, prev, 16)
+	for i := 0; i < 100; i++ {
+		next := NewResourceVersion()
+		require.Len(t, next, 16)
+		require.Greater(t, next, prev)
+		prev = next
+	}
+
+	// TestCase: Version after a given version is greater than it
+	next := NewResourceVersionAfter(prev)
+	require.Greater(t, next, prev)
+
+	// TestCase: Version after a version far ahead of the counter
+	next = NewResourceVersionAfter("0000001000000000")
+	require.Equal(t, "0000001000000001", next)
+	require.Greater(t, NewResourceVersion(), next)
+
+	// TestCase: Version after a non-numeric version
+	prev = NewResourceVersion()
+	next = NewResourceVersionAfter("not-a-number")
+	require.Greater(t, next, prev)
+}
+
+func TestGetExpectedObject(t *testing.T) {
+	// TestCase: Object is decoded into the given type
+	deployment := &appsv1.Deployment{}
+	ok := GetExpectedObject(t, "deployment.yaml", deployment)
+	require.True(t, ok)
+	require.Equal(t, "test-deployment", deployment.Name)
+	require.Equal(t, "kube-test", deployment.Namespace)
+	require.Equal(t, "docker.io/test/image:1.0.0", deployment.Spec.Template.Spec.Containers[0].Image)
+
+	// TestCase: Typed helper returns the same object
+	require.Equal(t, deployment, GetExpectedDeployment(t, "deployment.yaml"))
+
+	// TestCase: Object of a different type fails the test
+	mockT := &testing.T{}
+	ok = GetExpectedObject(mockT, "deployment.yaml", &appsv1.StatefulSet{})
+	require.False(t, ok)
+	require.True(t, mockT.Failed())
+}
+
+func TestGetExpectedObjects(t *testing.T) {
+	// TestCase: All documents are decoded and the empty trailing document is skipped
+	objects := GetExpectedObjects(t, "deploymentAndService.yaml")
+	require.Len(t, objects, 2)
+
+	deployment, ok := objects[0].(*appsv1.Deployment)
+	require.True(t, ok)
+	require.Equal(t, "test-deployment", deployment.Name)
+
+	service, ok := objects[1].(*v1.Service)
+	require.True(t, ok)
+	require.Equal(t, "test-service", service.Name)
+	require.Equal(t, int32(9001), service.Spec.Ports[0].Port)
+
+	// TestCase: Single document file returns one object
+	objects = GetExpectedObjects(t, "deployment.yaml")
+	require.Len(t, objects, 1)
+	require.Equal(t, GetExpectedDeployment(t, "deployment.yaml"), objects[0])
+}
+
+func TestGetExpectedServiceAccount(t *testing.T) {
+	serviceAccount := GetExpectedServiceAccount(t, "serviceAccount.yaml")
+	require.Equal(t, "test-service-account", serviceAccount.Name)
+	require.Equal(t, "kube-test", serviceAccount.Namespace)
+	require.Equal(t, "test", serviceAccount.Labels["name"])
+	require.Len(t, serviceAccount.ImagePullSecrets, 1)
+	require.Equal(t, "test-pull-secret", serviceAccount.ImagePullSecrets[0].Name)
+}
+
+func TestValidateStorageClusterCondition(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+		Status: corev1.StorageClusterStatus{
+			Conditions: []corev1.ClusterCondition{
+				{Type: corev1.ClusterConditionTypeInstall, Status: corev1.ClusterOperationInProgress},
+			},
+		},
+	}
+	operatorClient := fakeoperatorclient.NewSimpleClientset(cluster.DeepCopy())
+	operatorops.SetInstance(operatorops.New(operatorClient))
+
+	// Complete the install and start an upgrade after a few attempts
+	updateErr := make(chan error, 1)
+	go func() {
+		time.Sleep(300 * time.Millisecond)
+		liveCluster := cluster.DeepCopy()
+		liveCluster.Status.Conditions = []corev1.ClusterCondition{
+			{Type: corev1.ClusterConditionTypeInstall, Status: corev1.ClusterOperationCompleted},
+			{Type: corev1.ClusterConditionTypeUpgrade, Status: corev1.ClusterOperationInProgress},
+		}
+		_, err := operatorClient.CoreV1().StorageClusters(cluster.Namespace).UpdateStatus(context.TODO(), liveCluster, metav1.UpdateOptions{})
+		updateErr <- err
+	}()
+
+	// TestCase: Condition reaches the expected status
+	err := ValidateStorageClusterCondition(cluster, corev1.ClusterConditionTypeInstall, corev1.ClusterOperationCompleted,
+		2*time.Second, 100*time.Millisecond)
+	require.NoError(t, <-updateErr)
+	require.NoError(t, err)
+
+	// TestCase: Condition never reaches the expected status
+	err = ValidateStorageClusterCondition(cluster, corev1.ClusterConditionTypeUpgrade, corev1.ClusterOperationCompleted,
+		time.Second, 100*time.Millisecond)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "condition Upgrade to be Completed, current conditions: [Install=Completed, Upgrade=InProgress]")
+
+	// TestCase: Condition is not present
+	err = ValidateStorageClusterCondition(cluster, corev1.ClusterConditionTypeDelete, corev1.ClusterOperationCompleted,
+		time.Second, 100*time.Millisecond)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "condition Delete to be Completed, current conditions: [Install=Completed, Upgrade=InProgress]")
+}
+
+func TestWaitForStorageClusterPhase(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+		Status: corev1.StorageClusterStatus{
+			Phase: string(corev1.ClusterInit),
+		},
+	}
+	operatorClient := fakeoperatorclient.NewSimpleClientset(cluster.DeepCopy())
+	operatorops.SetInstance(operatorops.New(operatorClient))
+
+	// Move the cluster through a few phases before it settles as degraded
+	updateErr := make(chan error, 1)
+	go func() {
+		for _, phase := range []string{"Updating", string(corev1.ClusterOffline), "Degraded"} {
+			time.Sleep(200 * time.Millisecond)
+			liveCluster := cluster.DeepCopy()
+			liveCluster.Status.Phase = phase
+			_, err := operatorClient.CoreV1().StorageClusters(cluster.Namespace).UpdateStatus(context.TODO(), liveCluster, metav1.UpdateOptions{})
+			if err != nil {
+				updateErr <- err
+				return
+			}
+		}
+		updateErr <- nil
+	}()
+
+	// TestCase: Wait for any of the acceptable phases
+	liveCluster, err := WaitForStorageClusterPhase(cluster, 2*time.Second, 50*time.Millisecond,
+		corev1.ClusterOnline, corev1.ClusterConditionStatus("Degraded"))
+	require.NoError(t, <-updateErr)
+	require.NoError(t, err)
+	require.Equal(t, "Degraded", liveCluster.Status.Phase)
+
+	// TestCase: Cluster never reaches the phase
+	_, err = WaitForStorageClusterPhase(cluster, 500*time.Millisecond, 100*time.Millisecond, corev1.ClusterOnline)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to wait for StorageCluster to be in phase [Online]")
+
+	// TestCase: No phases given
+	_, err = WaitForStorageClusterPhase(cluster, 500*time.Millisecond, 100*time.Millisecond)
+	require.Error(t, err)
+}
+
+func TestValidateStorageClusterIsDegraded(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+		Status: corev1.StorageClusterStatus{
+			Phase: string(corev1.ClusterInit),
+		},
+	}
+	newStorageNode := func(name string, phase corev1.NodeConditionStatus) *corev1.StorageNode {
+		return &corev1.StorageNode{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: cluster.Namespace},
+			Status:     corev1.NodeStatus{Phase: string(phase)},
+		}
+	}
+	operatorClient := fakeoperatorclient.NewSimpleClientset(
+		cluster.DeepCopy(),
+		newStorageNode("node1", corev1.NodeOnlineStatus),
+		newStorageNode("node2", corev1.NodeOnlineStatus),
+	)
+	operatorops.SetInstance(operatorops.New(operatorClient))
+
+	// TestCase: Cluster is not online
+	_, err := ValidateStorageClusterIsDegraded(cluster, 500*time.Millisecond, 100*time.Millisecond)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "cluster state: Initializing")
+
+	// TestCase: Cluster is online and no StorageNode is degraded
+	liveCluster := cluster.DeepCopy()
+	liveCluster.Status.Phase = string(corev1.ClusterOnline)
+	_, err = operatorClient.CoreV1().StorageClusters(cluster.Namespace).UpdateStatus(context.TODO(), liveCluster, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	_, err = ValidateStorageClusterIsDegraded(cluster, 500*time.Millisecond, 100*time.Millisecond)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no StorageNode is in phase Degraded, StorageNode phases: [node1=Online, node2=Online]")
+
+	// TestCase: Cluster is online with a degraded StorageNode
+	_, err = operatorClient.CoreV1().StorageNodes(cluster.Namespace).UpdateStatus(context.TODO(),
+		newStorageNode("node2", corev1.NodeDegradedStatus), metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	liveCluster, err = ValidateStorageClusterIsDegraded(cluster, time.Second, 100*time.Millisecond)
+	require.NoError(t, err)
+	require.Equal(t, string(corev1.ClusterOnline), liveCluster.Status.Phase)
+}
+
+func TestValidateUninstallStorageClusterTelemetryResources(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+	}
+	operatorops.SetInstance(operatorops.New(fakeoperatorclient.NewSimpleClientset()))
+	k8sClient := fakek8sclient.NewSimpleClientset(
+		&v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "px-collector-proxy-config",
+				Namespace: "kube-test",
+			},
+		},
+		&v1.ServiceAccount{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "px-metrics-collector",
+				Namespace: "kube-test",
+			},
+		},
+	)
+	coreops.SetInstance(coreops.New(k8sClient))
+
+	// TestCase: Leftover telemetry resources fail the uninstall validation
+	err := ValidateUninstallStorageCluster(cluster, time.Second, 100*time.Millisecond)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "still present: ConfigMap kube-test/px-collector-proxy-config, ServiceAccount kube-test/px-metrics-collector")
+
+	// TestCase: Only the leftover config map is reported
+	err = k8sClient.CoreV1().ServiceAccounts("kube-test").Delete(context.TODO(), "px-metrics-collector", metav1.DeleteOptions{})
+	require.NoError(t, err)
+	err = ValidateUninstallStorageCluster(cluster, time.Second, 100*time.Millisecond)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "still present: ConfigMap kube-test/px-collector-proxy-config")
+	require.NotContains(t, err.Error(), "ServiceAccount")
+
+	// TestCase: All telemetry resources are deleted
+	err = k8sClient.CoreV1().ConfigMaps("kube-test").Delete(context.TODO(), "px-collector-proxy-config", metav1.DeleteOptions{})
+	require.NoError(t, err)
+	err = ValidateUninstallStorageCluster(cluster, time.Second, 100*time.Millisecond)
+	require.NoError(t, err)
+}
+
+func TestValidateUninstallStorageClusterWithCRDs(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+	}
+	operatorops.SetInstance(operatorops.New(fakeoperatorclient.NewSimpleClientset()))
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset()))
+	vpsCRDName := "volumeplacementstrategies.portworx.io"
+	extClient := fakeextclient.NewSimpleClientset(&apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: vpsCRDName,
+		},
+	})
+	apiextensionsops.SetInstance(apiextensionsops.New(extClient))
+
+	// TestCase: CRDs are retained and not validated by default
+	err := ValidateUninstallStorageCluster(cluster, time.Second, 100*time.Millisecond)
+	require.NoError(t, err)
+
+	// TestCase: Retained CRD fails the validation when CRD removal is expected
+	err = ValidateUninstallStorageClusterWithCRDs(cluster, []string{vpsCRDName}, time.Second, 100*time.Millisecond)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "still present: "+vpsCRDName)
+
+	// TestCase: CRD is removed
+	err = extClient.ApiextensionsV1().CustomResourceDefinitions().Delete(context.TODO(), vpsCRDName, metav1.DeleteOptions{})
+	require.NoError(t, err)
+	err = ValidateUninstallStorageClusterWithCRDs(cluster, []string{vpsCRDName}, time.Second, 100*time.Millisecond)
+	require.NoError(t, err)
+}
+
+func TestUninstallStorageClusterAndWait(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+	}
+	operatorClient := fakeoperatorclient.NewSimpleClientset(cluster.DeepCopy())
+	operatorops.SetInstance(operatorops.New(operatorClient))
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset()))
+
+	// Simulate the operator removing the StorageCluster some time after the delete request
+	deleteRequested := make(chan struct{})
+	operatorClient.PrependReactor("delete", "storageclusters", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		close(deleteRequested)
+		return true, nil, nil
+	})
+	deleteErr := make(chan error, 1)
+	go func() {
+		<-deleteRequested
+		time.Sleep(300 * time.Millisecond)
+		gvr := corev1.SchemeGroupVersion.WithResource("storageclusters")
+		deleteErr <- operatorClient.Tracker().Delete(gvr, cluster.Namespace, cluster.Name)
+	}()
+
+	// TestCase: Uninstall blocks until the StorageCluster is deleted
+	start := time.Now()
+	err := UninstallStorageClusterAndWait(cluster, 2*time.Second, 50*time.Millisecond)
+	require.NoError(t, err)
+	require.NoError(t, <-deleteErr)
+	require.GreaterOrEqual(t, time.Since(start), 300*time.Millisecond)
+
+	_, err = operatorClient.CoreV1().StorageClusters(cluster.Namespace).Get(context.TODO(), cluster.Name, metav1.GetOptions{})
+	require.True(t, errors.IsNotFound(err))
+
+	// TestCase: Uninstall times out if the StorageCluster is not deleted
+	operatorClient = fakeoperatorclient.NewSimpleClientset(cluster.DeepCopy())
+	operatorClient.PrependReactor("delete", "storageclusters", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, nil
+	})
+	operatorops.SetInstance(operatorops.New(operatorClient))
+	err = UninstallStorageClusterAndWait(cluster, 500*time.Millisecond, 100*time.Millisecond)
+	require.Error(t, err)
+}
+
+func TestCreateClusterWithMonitoring(t *testing.T) {
+	cluster := CreateClusterWithMonitoring(true, true, false)
+	require.Equal(t, "px-cluster", cluster.Name)
+	require.Equal(t, "kube-system", cluster.Namespace)
+	require.True(t, cluster.Spec.Monitoring.Prometheus.Enabled)
+	require.True(t, cluster.Spec.Monitoring.Prometheus.ExportMetrics)
+	require.True(t, cluster.Spec.Monitoring.Prometheus.AlertManager.Enabled)
+	require.False(t, cluster.Spec.Monitoring.Telemetry.Enabled)
+
+	cluster = CreateClusterWithMonitoring(false, false, true)
+	require.False(t, cluster.Spec.Monitoring.Prometheus.Enabled)
+	require.False(t, cluster.Spec.Monitoring.Prometheus.ExportMetrics)
+	require.False(t, cluster.Spec.Monitoring.Prometheus.AlertManager.Enabled)
+	require.True(t, cluster.Spec.Monitoring.Telemetry.Enabled)
+}
+
+func TestCreateClusterWithCustomRegistry(t *testing.T) {
+	cluster := CreateClusterWithCustomRegistry("registry.local:5000")
+	require.Equal(t, "px-cluster", cluster.Name)
+	require.Equal(t, "registry.local:5000", cluster.Spec.CustomImageRegistry)
+	require.Equal(t, "registry.local:5000/openstorage/stork:2.9.0",
+		util.GetImageURN(cluster, "openstorage/stork:2.9.0"))
+	require.Equal(t, "registry.local:5000/portworx/autopilot:1.3.2",
+		util.GetImageURN(cluster, "docker.io/portworx/autopilot:1.3.2"))
+
+	// Registry with a repository replaces the repository of the image
+	cluster = CreateClusterWithCustomRegistry("registry.local:5000/px")
+	require.Equal(t, "registry.local:5000/px/stork:2.9.0",
+		util.GetImageURN(cluster, "openstorage/stork:2.9.0"))
+	require.Equal(t, "registry.local:5000/px/autopilot:1.3.2",
+		util.GetImageURN(cluster, "portworx/autopilot:1.3.2"))
+}
+
+func TestConstructVersionURL(t *testing.T) {
+	// TestCase: Only the default kbver param
+	versionURL, err := ConstructVersionURL("https://install.portworx.com/2.10", "1.21.0", nil)
+	require.NoError(t, err)
+	require.Equal(t, "https://install.portworx.com/2.10/version?kbver=1.21.0", versionURL)
+
+	// TestCase: Trailing slash in the base URL
+	versionURL, err = ConstructVersionURL("https://install.portworx.com/2.10/", "1.21.0", nil)
+	require.NoError(t, err)
+	require.Equal(t, "https://install.portworx.com/2.10/version?kbver=1.21.0", versionURL)
+
+	// TestCase: Multiple query params
+	versionURL, err = ConstructVersionURL("https://install.portworx.com/", "1.21.0",
+		map[string]string{"opver": "1.8.0", "pxver": "2.10.1"})
+	require.NoError(t, err)
+	require.Equal(t, "https://install.portworx.com/version?kbver=1.21.0&opver=1.8.0&pxver=2.10.1", versionURL)
+
+	// TestCase: kbver passed in the query params overrides the default
+	versionURL, err = ConstructVersionURL("https://install.portworx.com", "1.21.0",
+		map[string]string{"kbver": "1.22.0"})
+	require.NoError(t, err)
+	require.Equal(t, "https://install.portworx.com/version?kbver=1.22.0", versionURL)
+
+	// TestCase: Invalid URL
+	_, err = ConstructVersionURL("://install.portworx.com", "1.21.0", nil)
+	require.Error(t, err)
+}
+
+func TestConstructPxReleaseManifestURL(t *testing.T) {
+	manifestURL, err := ConstructPxReleaseManifestURL("https://install.portworx.com/2.10")
+	require.NoError(t, err)
+	require.Equal(t, "https://install.portworx.com/2.10/version", manifestURL)
+
+	manifestURL, err = ConstructPxReleaseManifestURL("https://install.portworx.com")
+	require.NoError(t, err)
+	require.Equal(t, "https://install.portworx.com/version", manifestURL)
+
+	// TestCase: Port and existing query string are kept intact
+	manifestURL, err = ConstructPxReleaseManifestURL("https://spec-gen.local:8443/2.10/?air-gapped=true&c=px-cluster")
+	require.NoError(t, err)
+	require.Equal(t, "https://spec-gen.local:8443/2.10/version?air-gapped=true&c=px-cluster", manifestURL)
+
+	// TestCase: Invalid URL
+	_, err = ConstructPxReleaseManifestURL("://install.portworx.com")
+	require.Error(t, err)
+}
+
+func TestConstructVersionURLWithQueryString(t *testing.T) {
+	versionURL, err := ConstructVersionURL("https://spec-gen.local:8443/2.10?air-gapped=true", "1.21.0",
+		map[string]string{"opver": "1.8.0"})
+	require.NoError(t, err)
+	require.Equal(t, "https://spec-gen.local:8443/2.10/version?air-gapped=true&kbver=1.21.0&opver=1.8.0", versionURL)
+
+	versionURL, err = ConstructVersionURL("http://10.0.0.1:8080", "1.21.0", nil)
+	require.NoError(t, err)
+	require.Equal(t, "http://10.0.0.1:8080/version?kbver=1.21.0", versionURL)
+}
+
+func TestGetK8SVersionFull(t *testing.T) {
+	setK8sVersion := func(gitVersion string) {
+		k8sClient := fakek8sclient.NewSimpleClientset()
+		k8sClient.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &k8sversion.Info{
+			GitVersion: gitVersion,
+		}
+		coreops.SetInstance(coreops.New(k8sClient))
+	}
+
+	// TestCase: GKE version
+	setK8sVersion("v1.24.10-gke.2300")
+	fullVersion, ver, err := GetK8SVersionFull()
+	require.NoError(t, err)
+	require.Equal(t, "v1.24.10-gke.2300", fullVersion)
+	require.Equal(t, []int{1, 24, 10}, ver.Segments())
+	require.Equal(t, "gke.2300", ver.Prerelease())
+	trimmedVersion, err := GetK8SVersion()
+	require.NoError(t, err)
+	require.Equal(t, "v1.24.10", trimmedVersion)
+
+	// TestCase: EKS version
+	setK8sVersion("v1.21.14-eks-fb459a0")
+	fullVersion, ver, err = GetK8SVersionFull()
+	require.NoError(t, err)
+	require.Equal(t, "v1.21.14-eks-fb459a0", fullVersion)
+	require.Equal(t, []int{1, 21, 14}, ver.Segments())
+	require.Equal(t, "eks-fb459a0", ver.Prerelease())
+	trimmedVersion, err = GetK8SVersion()
+	require.NoError(t, err)
+	require.Equal(t, "v1.21.14", trimmedVersion)
+
+	// TestCase: Version without a vendor suffix
+	setK8sVersion("v1.22.3")
+	fullVersion, ver, err = GetK8SVersionFull()
+	require.NoError(t, err)
+	require.Equal(t, "v1.22.3", fullVersion)
+	require.Empty(t, ver.Prerelease())
+
+	// TestCase: Invalid version
+	setK8sVersion("invalid")
+	_, _, err = GetK8SVersionFull()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid kubernetes version received: invalid")
+}
+
+func TestListByLabels(t *testing.T) {
+	newPod := func(name, namespace string, labels map[string]string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    labels,
+			},
+		}
+	}
+	k8sClient := FakeK8sClient(
+		newPod("px-pod-1", "kube-test", map[string]string{"name": "portworx", "role": "storage"}),
+		newPod("px-pod-2", "kube-test", map[string]string{"name": "portworx"}),
+		newPod("stork-pod", "kube-test", map[string]string{"name": "stork"}),
+		newPod("px-pod-3", "other", map[string]string{"name": "portworx"}),
+	)
+	podNames := func(pods *v1.PodList) []string {
+		var names []string
+		for _, pod := range pods.Items {
+			names = append(names, pod.Name)
+		}
+		return names
+	}
+
+	// TestCase: List without options returns all the objects
+	pods := &v1.PodList{}
+	err := List(k8sClient, pods)
+	require.NoError(t, err)
+	require.Len(t, pods.Items, 4)
+
+	// TestCase: List by labels
+	pods = &v1.PodList{}
+	err = ListByLabels(k8sClient, pods, map[string]string{"name": "portworx"})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"px-pod-1", "px-pod-2", "px-pod-3"}, podNames(pods))
+
+	pods = &v1.PodList{}
+	err = ListByLabels(k8sClient, pods, map[string]string{"name": "portworx", "role": "storage"})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"px-pod-1"}, podNames(pods))
+
+	// TestCase: List with label and namespace options
+	pods = &v1.PodList{}
+	err = ListWithOptions(k8sClient, pods,
+		client.InNamespace("kube-test"), client.MatchingLabels{"name": "portworx"})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"px-pod-1", "px-pod-2"}, podNames(pods))
+
+	// TestCase: No objects match the labels
+	pods = &v1.PodList{}
+	err = ListByLabels(k8sClient, pods, map[string]string{"name": "autopilot"})
+	require.NoError(t, err)
+	require.Empty(t, pods.Items)
+}
+
+func TestGetEventually(t *testing.T) {
+	k8sClient := FakeK8sClient()
+
+	// TestCase: Object does not appear before the timeout
+	pod := &v1.Pod{}
+	err := GetEventually(k8sClient, pod, "px-pod", "kube-test", 300*time.Millisecond, 100*time.Millisecond)
+	require.Error(t, err)
+	require.True(t, errors.IsNotFound(err))
+
+	// TestCase: Object appears after a delay
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		_ = k8sClient.Create(context.TODO(), &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "px-pod",
+				Namespace: "kube-test",
+			},
+		})
+	}()
+	pod = &v1.Pod{}
+	err = GetEventually(k8sClient, pod, "px-pod", "kube-test", 5*time.Second, 100*time.Millisecond)
+	require.NoError(t, err)
+	require.Equal(t, "px-pod", pod.Name)
+}
+
+func TestUpdateWithRetry(t *testing.T) {
+	k8sClient := FakeK8sClient(&v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-pod",
+			Namespace: "kube-test",
+		},
+	})
+	stalePod := &v1.Pod{}
+	err := Get(k8sClient, stalePod, "px-pod", "kube-test")
+	require.NoError(t, err)
+
+	// Update the pod concurrently so the resource version of the stale copy is outdated
+	concurrentUpdate := func() {
+		pod := &v1.Pod{}
+		require.NoError(t, Get(k8sClient, pod, "px-pod", "kube-test"))
+		if pod.Annotations == nil {
+			pod.Annotations = make(map[string]string)
+		}
+		pod.Annotations["updates"] += "x"
+		require.NoError(t, Update(k8sClient, pod))
+	}
+	concurrentUpdate()
+
+	// TestCase: Plain update fails with a conflict
+	err = Update(k8sClient, stalePod.DeepCopy())
+	require.True(t, errors.IsConflict(err))
+
+	// TestCase: Update is retried after re-fetching the object
+	mutations := 0
+	err = UpdateWithRetry(k8sClient, stalePod, func(obj client.Object) {
+		mutations++
+		obj.SetLabels(map[string]string{"name": "portworx"})
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, mutations)
+
+	pod := &v1.Pod{}
+	err = Get(k8sClient, pod, "px-pod", "kube-test")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"name": "portworx"}, pod.Labels)
+	require.Equal(t, "x", pod.Annotations["updates"])
+
+	// TestCase: Update keeps conflicting until the retries are exhausted
+	mutations = 0
+	err = UpdateWithRetry(k8sClient, pod, func(obj client.Object) {
+		mutations++
+		concurrentUpdate()
+		obj.SetLabels(map[string]string{"name": "stork"})
+	})
+	require.Error(t, err)
+	require.True(t, errors.IsConflict(err))
+	require.Equal(t, updateConflictRetries+1, mutations)
+}
+
+// fakePrometheusOps returns the given Prometheuses, PrometheusRules and ServiceMonitors, the rest of the prometheus ops are not implemented
+type fakePrometheusOps struct {
+	prometheusops.Ops
+	prometheuses    map[string]*monitoringv1.Prometheus
+	rules           map[string]*monitoringv1.PrometheusRule
+	serviceMonitors map[string]*monitoringv1.ServiceMonitor
+}
+
+func (f *fakePrometheusOps) GetPrometheus(name, namespace string) (*monitoringv1.Prometheus, error) {
+	if prometheus, ok := f.prometheuses[namespace+"/"+name]; ok {
+		return prometheus, nil
+	}
+	return nil, errors.NewNotFound(monitoringv1.Resource("prometheuses"), name)
+}
+
+func (f *fakePrometheusOps) GetPrometheusRule(name, namespace string) (*monitoringv1.PrometheusRule, error) {
+	if rule, ok := f.rules[namespace+"/"+name]; ok {
+		return rule, nil
+	}
+	return nil, errors.NewNotFound(monitoringv1.Resource("prometheusrules"), name)
+}
+
+func (f *fakePrometheusOps) GetServiceMonitor(name, namespace string) (*monitoringv1.ServiceMonitor, error) {
+	if serviceMonitor, ok := f.serviceMonitors[namespace+"/"+name]; ok {
+		return serviceMonitor, nil
+	}
+	return nil, errors.NewNotFound(monitoringv1.Resource("servicemonitors"), name)
+}
+
+func TestValidatePrometheusRuleContains(t *testing.T) {
+	prometheusRule := GetExpectedPrometheusRule(t, "prometheusRule.yaml")
+	prometheusops.SetInstance(&fakePrometheusOps{
+		rules: map[string]*monitoringv1.PrometheusRule{"kube-test/portworx": prometheusRule},
+	})
+
+	// TestCase: All the rules are present, matching alerts, recording rules and groups
+	err := ValidatePrometheusRuleContains("kube-test", []string{
+		"PortworxVolumeUsageCritical",
+		"PortworxStorageUsageCritical",
+		"px:volume_usage_percent",
+		"Portworx PoolResize Alerts",
+	})
+	require.NoError(t, err)
+
+	// TestCase: An expected alert is missing
+	err = ValidatePrometheusRuleContains("kube-test", []string{
+		"PortworxVolumeUsageCritical",
+		"PortworxStorageFull",
+		"PoolExpandFailure",
+	})
+	require.Error(t, err)
+	require.Equal(t, "PrometheusRule kube-test/portworx is missing rules [PortworxStorageFull]", err.Error())
+
+	// TestCase: PrometheusRule does not exist
+	err = ValidatePrometheusRuleContains("other", []string{"PortworxVolumeUsageCritical"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to get PrometheusRule other/portworx")
+}
+
+func TestValidateServiceMonitorEndpoints(t *testing.T) {
+	serviceMonitor := GetExpectedServiceMonitor(t, "serviceMonitor.yaml")
+	fakeOps := &fakePrometheusOps{
+		serviceMonitors: map[string]*monitoringv1.ServiceMonitor{"kube-test/portworx": serviceMonitor},
+	}
+	prometheusops.SetInstance(fakeOps)
+
+	// TestCase: Endpoint without a scheme defaults to http
+	err := ValidateServiceMonitorEndpoints("kube-test", "px-api", "http")
+	require.NoError(t, err)
+	err = ValidateServiceMonitorEndpoints("kube-test", "px-api", "")
+	require.NoError(t, err)
+
+	// TestCase: Endpoint points to the wrong port
+	err = ValidateServiceMonitorEndpoints("kube-test", "px-kvdb", "http")
+	require.Error(t, err)
+	require.Equal(t, "failed to validate ServiceMonitor kube-test/portworx: no endpoint for port px-kvdb, found ports [px-api]",
+		err.Error())
+
+	// TestCase: Endpoint scheme does not match
+	err = ValidateServiceMonitorEndpoints("kube-test", "px-api", "https")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "expected scheme: https, actual: http")
+
+	// TestCase: https endpoint needs a TLS config when security is enabled
+	serviceMonitor.Spec.Endpoints[0].Scheme = "https"
+	err = ValidateServiceMonitorEndpoints("kube-test", "px-api", "https")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "https endpoint has no TLS config")
+
+	serviceMonitor.Spec.Endpoints[0].TLSConfig = &monitoringv1.TLSConfig{
+		CAFile: "/etc/prometheus/secrets/px-tls/ca.crt",
+	}
+	err = ValidateServiceMonitorEndpoints("kube-test", "px-api", "https")
+	require.NoError(t, err)
+
+	// TestCase: ServiceMonitor does not exist
+	err = ValidateServiceMonitorEndpoints("other", "px-api", "http")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to get ServiceMonitor other/portworx")
+}
+
+func TestValidatePrometheusSelectsServiceMonitor(t *testing.T) {
+	prometheus := GetExpectedPrometheus(t, "prometheus.yaml")
+	serviceMonitor := GetExpectedServiceMonitor(t, "serviceMonitor.yaml")
+	prometheusops.SetInstance(&fakePrometheusOps{
+		prometheuses:    map[string]*monitoringv1.Prometheus{"kube-test/px-prometheus": prometheus},
+		serviceMonitors: map[string]*monitoringv1.ServiceMonitor{"kube-test/portworx": serviceMonitor},
+	})
+
+	// TestCase: Selector matches the ServiceMonitor labels
+	err := ValidatePrometheusSelectsServiceMonitor("kube-test")
+	require.NoError(t, err)
+
+	// TestCase: Selector does not match the ServiceMonitor labels
+	prometheus.Spec.ServiceMonitorSelector = &metav1.LabelSelector{
+		MatchLabels: map[string]string{"prometheus": "px-backup"},
+	}
+	err = ValidatePrometheusSelectsServiceMonitor("kube-test")
+	require.Error(t, err)
+	require.Equal(t, "serviceMonitorSelector [prometheus=px-backup] of Prometheus kube-test/px-prometheus does not match "+
+		"labels map[name:portworx prometheus:portworx] of ServiceMonitor kube-test/portworx", err.Error())
+
+	// TestCase: Prometheus without a selector does not select the ServiceMonitor
+	prometheus.Spec.ServiceMonitorSelector = nil
+	err = ValidatePrometheusSelectsServiceMonitor("kube-test")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "has no serviceMonitorSelector")
+
+	// TestCase: Prometheus does not exist
+	err = ValidatePrometheusSelectsServiceMonitor("other")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to get Prometheus other/px-prometheus")
+}
+
+func TestValidatePrometheusConfig(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+	}
+	prometheus := GetExpectedPrometheus(t, "prometheus.yaml")
+	prometheusops.SetInstance(&fakePrometheusOps{
+		prometheuses: map[string]*monitoringv1.Prometheus{"kube-test/px-prometheus": prometheus},
+	})
+
+	// TestCase: Prometheus is not enabled
+	err := ValidatePrometheusConfig(cluster, "kube-test")
+	require.Error(t, err)
+
+	// TestCase: Prometheus matches the spec
+	cluster.Spec.Monitoring = &corev1.MonitoringSpec{
+		Prometheus: &corev1.PrometheusSpec{Enabled: true},
+	}
+	err = ValidatePrometheusConfig(cluster, "kube-test")
+	require.NoError(t, err)
+
+	// TestCase: Prometheus does not exist
+	err = ValidatePrometheusConfig(cluster, "other")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to get Prometheus other/px-prometheus")
+
+	// TestCase: Retention does not match the default
+	prometheus.Spec.Retention = "30d"
+	err = ValidatePrometheusConfig(cluster, "kube-test")
+	require.Error(t, err)
+	require.Equal(t, "Prometheus kube-test/px-prometheus has wrong retention, expected the default retention, actual: 30d", err.Error())
+	prometheus.Spec.Retention = ""
+
+	// TestCase: Replicas and storage do not match
+	prometheus.Spec.Replicas = nil
+	err = ValidatePrometheusConfig(cluster, "kube-test")
+	require.Error(t, err)
+	require.Equal(t, "Prometheus kube-test/px-prometheus has wrong replicas, expected: 1, actual: <nil>", err.Error())
+	replicas := int32(1)
+	prometheus.Spec.Replicas = &replicas
+
+	prometheus.Spec.Storage = &monitoringv1.StorageSpec{}
+	err = ValidatePrometheusConfig(cluster, "kube-test")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "has unexpected storage")
+	prometheus.Spec.Storage = nil
+
+	// TestCase: Remote write endpoint from the spec
+	cluster.Spec.Monitoring.Prometheus.RemoteWriteEndpoint = "http://metrics.example.com"
+	err = ValidatePrometheusConfig(cluster, "kube-test")
+	require.Error(t, err)
+	require.Equal(t, "Prometheus kube-test/px-prometheus has wrong remote write URLs, "+
+		"expected: [http://metrics.example.com/api/prom/push], actual: []", err.Error())
+
+	prometheus.Spec.RemoteWrite = []monitoringv1.RemoteWriteSpec{{URL: "http://metrics.example.com/api/prom/push"}}
+	err = ValidatePrometheusConfig(cluster, "kube-test")
+	require.NoError(t, err)
+
+	// TestCase: Alerting follows the alert manager spec
+	cluster.Spec.Monitoring.Prometheus.AlertManager = &corev1.AlertManagerSpec{Enabled: true}
+	err = ValidatePrometheusConfig(cluster, "kube-test")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "has wrong alerting, expected alertmanagers configured: true, actual: false")
+
+	prometheus.Spec.Alerting = &monitoringv1.AlertingSpec{
+		Alertmanagers: []monitoringv1.AlertmanagerEndpoints{{Namespace: "kube-test", Name: "alertmanager-portworx"}},
+	}
+	err = ValidatePrometheusConfig(cluster, "kube-test")
+	require.NoError(t, err)
+}
+
+func TestValidateTelemetryProxyConfig(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+	}
+	require.Equal(t, TelemetryExternalArcusEndpoint, GetExpectedTelemetryProxyEndpoint(cluster))
+	cluster.Annotations = map[string]string{"portworx.io/arcus-location": "external"}
+	require.Equal(t, TelemetryExternalArcusEndpoint, GetExpectedTelemetryProxyEndpoint(cluster))
+	cluster.Annotations["portworx.io/arcus-location"] = "Internal"
+	require.Equal(t, TelemetryInternalArcusEndpoint, GetExpectedTelemetryProxyEndpoint(cluster))
+	cluster.Annotations["portworx.io/arcus-location"] = "telemetry.proxy.local"
+	require.Equal(t, "telemetry.proxy.local", GetExpectedTelemetryProxyEndpoint(cluster))
+	cluster.Annotations["portworx.io/arcus-location"] = ""
+	require.Equal(t, TelemetryExternalArcusEndpoint, GetExpectedTelemetryProxyEndpoint(cluster))
+
+	configMap := GetExpectedConfigMap(t, "telemetryProxyConfigMap.yaml")
+
+	// TestCase: Config map does not exist
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset()))
+	err := ValidateTelemetryProxyConfig("kube-test", TelemetryExternalArcusEndpoint)
+	require.Error(t, err)
+
+	// TestCase: Proxy routes to the cloud endpoint
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(configMap.DeepCopy())))
+	err = ValidateTelemetryProxyConfig("kube-test", TelemetryExternalArcusEndpoint)
+	require.NoError(t, err)
+
+	// TestCase: Proxy routes to the cloud endpoint while the staging endpoint is expected
+	err = ValidateTelemetryProxyConfig("kube-test", TelemetryInternalArcusEndpoint)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "upstream endpoint: expected: "+TelemetryInternalArcusEndpoint+
+		", actual: "+TelemetryExternalArcusEndpoint)
+
+	// TestCase: Proxy routes to the staging endpoint
+	stagingConfigMap := configMap.DeepCopy()
+	stagingConfigMap.Data["envoy-config.yaml"] = strings.ReplaceAll(
+		stagingConfigMap.Data["envoy-config.yaml"], TelemetryExternalArcusEndpoint, TelemetryInternalArcusEndpoint)
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(stagingConfigMap)))
+	err = ValidateTelemetryProxyConfig("kube-test", TelemetryInternalArcusEndpoint)
+	require.NoError(t, err)
+
+	// TestCase: Host rewrite does not match the upstream endpoint
+	wrongConfigMap := configMap.DeepCopy()
+	wrongConfigMap.Data["envoy-config.yaml"] = strings.Replace(wrongConfigMap.Data["envoy-config.yaml"],
+		"host_rewrite_literal: "+TelemetryExternalArcusEndpoint, "host_rewrite_literal: proxy.corp.local", 1)
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(wrongConfigMap)))
+	err = ValidateTelemetryProxyConfig("kube-test", TelemetryExternalArcusEndpoint)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "route host rewrite: expected: "+TelemetryExternalArcusEndpoint+", actual: proxy.corp.local")
+
+	// TestCase: Config map without the envoy config
+	emptyConfigMap := configMap.DeepCopy()
+	emptyConfigMap.Data = nil
+	coreops.SetInstance(coreops.New(fakek8sclient.NewSimpleClientset(emptyConfigMap)))
+	err = ValidateTelemetryProxyConfig("kube-test", TelemetryExternalArcusEndpoint)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "missing envoy-config.yaml")
+}
+
+func TestValidateTelemetryDisabled(t *testing.T) {
+	cluster := &corev1.StorageCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-cluster",
+			Namespace: "kube-test",
+		},
+		Spec: corev1.StorageClusterSpec{
+			Monitoring: &corev1.MonitoringSpec{
+				Telemetry: &corev1.TelemetrySpec{
+					Enabled: false,
+				},
+			},
+		},
+	}
+	collectorMeta := metav1.ObjectMeta{
+		Name:      "px-metrics-collector",
+		Namespace: cluster.Namespace,
+	}
+	k8sClient := fakek8sclient.NewSimpleClientset(
+		&appsv1.Deployment{ObjectMeta: collectorMeta},
+		&rbacv1.Role{ObjectMeta: collectorMeta},
+		&rbacv1.RoleBinding{ObjectMeta: collectorMeta},
+		&v1.ServiceAccount{ObjectMeta: collectorMeta},
+		&v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "px-telemetry-config",
+				Namespace: cluster.Namespace,
+			},
+		},
+	)
+	coreops.SetInstance(coreops.New(k8sClient))
+	appops.SetInstance(appops.New(k8sClient.AppsV1(), k8sClient.CoreV1()))
+	rbacops.SetInstance(rbacops.New(k8sClient.RbacV1()))
+
+	// TestCase: Leftover collector deployment and RBAC fail the validation
+	err := ValidateTelemetry(nil, cluster, time.Second, 100*time.Millisecond)
+	require.Error(t, err)
+	require.Equal(t, "failed to validate telemetry is disabled, still present: Deployment kube-test/px-metrics-collector, "+
+		"Role kube-test/px-metrics-collector, RoleBinding kube-test/px-metrics-collector", err.Error())
+
+	// TestCase: Leftover config map and service account fail the validation
+	err = k8sClient.AppsV1().Deployments(cluster.Namespace).Delete(context.TODO(), "px-metrics-collector", metav1.DeleteOptions{})
+	require.NoError(t, err)
+	err = k8sClient.RbacV1().Roles(cluster.Namespace).Delete(context.TODO(), "px-metrics-collector", metav1.DeleteOptions{})
+	require.NoError(t, err)
+	err = k8sClient.RbacV1().RoleBindings(cluster.Namespace).Delete(context.TODO(), "px-metrics-collector", metav1.DeleteOptions{})
+	require.NoError(t, err)
+	err = ValidateTelemetry(nil, cluster, time.Second, 100*time.Millisecond)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "still present: ConfigMap kube-test/px-telemetry-config, ServiceAccount kube-test/px-metrics-collector")
+
+	// TestCase: All telemetry resources are removed
+	err = k8sClient.CoreV1().ConfigMaps(cluster.Namespace).Delete(context.TODO(), "px-telemetry-config", metav1.DeleteOptions{})
+	require.NoError(t, err)
+	err = k8sClient.CoreV1().ServiceAccounts(cluster.Namespace).Delete(context.TODO(), "px-metrics-collector", metav1.DeleteOptions{})
+	require.NoError(t, err)
+	err = ValidateTelemetry(nil, cluster, time.Second, 100*time.Millisecond)
+	require.NoError(t, err)
+}
+
+func TestValidateTelemetrySecurityContext(t *testing.T) {
+	runAsUser := int64(TelemetryCollectorRunAsUser)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "px-metrics-collector",
+			Namespace: "kube-test",
+		},
+	}
+	replicaSet := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "px-metrics-collector-123",
+			Namespace:       "kube-test",
+			UID:             "collector-rs-uid",
+			OwnerReferences: []metav1.OwnerReference{{Name: "px-metrics-collector"}},
+		},
+	}
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "px-metrics-collector-123-abc",
+			Namespace:       "kube-test",
+			OwnerReferences: []metav1.OwnerReference{{UID: "collector-rs-uid"}},
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{
+					Name:            "collector",
+					SecurityContext: &v1.SecurityContext{RunAsUser: &runAsUser},
+				},
+				{
+					Name:            "envoy",
+					SecurityContext: &v1.SecurityContext{RunAsUser: &runAsUser},
+				},
+			},
+		},
+	}
+	k8sClient := fakek8sclient.NewSimpleClientset(deployment, replicaSet, pod)
+	appops.SetInstance(appops.New(k8sClient.AppsV1(), k8sClient.CoreV1()))
+
+	// TestCase: Collector containers run as the telemetry user
+	err := ValidateTelemetrySecurityContext("kube-test")
+	require.NoError(t, err)
+
+	// TestCase: Pod level runAsUser is used when the container does not set one
+	podPassing := pod.DeepCopy()
+	podPassing.Spec.SecurityContext = &v1.PodSecurityContext{RunAsUser: &runAsUser, FSGroup: &runAsUser}
+	podPassing.Spec.Containers[1].SecurityContext = nil
+	err = validateTelemetryPodSecurityContext(podPassing)
+	require.NoError(t, err)
+
+	// TestCase: Container running as root fails the validation
+	rootUser := int64(0)
+	pod.Spec.Containers[1].SecurityContext.RunAsUser = &rootUser
+	_, err = k8sClient.CoreV1().Pods("kube-test").Update(context.TODO(), pod, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	err = ValidateTelemetrySecurityContext("kube-test")
+	require.Error(t, err)
+	require.Equal(t, "failed to validate security context of pod kube-test/px-metrics-collector-123-abc, "+
+		"Err: container envoy runAsUser: expected: 1111, actual: 0", err.Error())
+
+	// TestCase: Container without a runAsUser fails the validation
+	podFailing := podPassing.DeepCopy()
+	podFailing.Spec.SecurityContext = nil
+	err = validateTelemetryPodSecurityContext(podFailing)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "container envoy has no runAsUser")
+
+	// TestCase: Container explicitly allowed to run as root fails the validation
+	podFailing = podPassing.DeepCopy()
+	podFailing.Spec.Containers[0].SecurityContext.RunAsNonRoot = BoolPtr(false)
+	err = validateTelemetryPodSecurityContext(podFailing)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "container collector is allowed to run as root")
+
+	// TestCase: Root fsGroup fails the validation
+	podFailing = podPassing.DeepCopy()
+	podFailing.Spec.SecurityContext.FSGroup = &rootUser
+	err = validateTelemetryPodSecurityContext(podFailing)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "pod fsGroup is the root group")
+
+	// TestCase: No collector pods running
+	k8sClient = fakek8sclient.NewSimpleClientset(deployment, replicaSet)
+	appops.SetInstance(appops.New(k8sClient.AppsV1(), k8sClient.CoreV1()))
+	err = ValidateTelemetrySecurityContext("kube-test")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to get pods of deployment kube-test/px-metrics-collector")
+}
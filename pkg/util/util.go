@@ -32,6 +32,8 @@ const (
 	FailedValidationReason = "FailedValidation"
 	// FailedComponentReason is added to an event when setting up or removing a component fails.
 	FailedComponentReason = "FailedComponent"
+	// CRDCreatedReason is added to an event when the operator creates a CustomResourceDefinition.
+	CRDCreatedReason = "CRDCreated"
 	// UpdatePausedReason is added to an event when operator pauses update of the storage cluster.
 	UpdatePausedReason = "UpdatePaused"
 	// ClusterOnlineReason is added to an event when a cluster comes online
@@ -146,6 +148,15 @@ func GetImageMajorVersion(image string) int {
 	return ver.Segments()[0]
 }
 
+// VersionSupportsPSP returns true if PodSecurityPolicies are supported by the given
+// Kubernetes version. PodSecurityPolicies are removed in Kubernetes 1.25, in favor of
+// the PodSecurity admission configured through namespace labels. Only the major and minor
+// versions are compared, as prereleases like 1.25.0-gke.1000 sort before 1.25.0.
+func VersionSupportsPSP(k8sVersion *version.Version) bool {
+	segments := k8sVersion.Segments()
+	return segments[0] < 1 || (segments[0] == 1 && segments[1] < 25)
+}
+
 // HasPullSecretChanged checks if the imagePullSecret in the cluster is the only one
 // in the given list of pull secrets
 func HasPullSecretChanged(
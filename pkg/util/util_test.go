@@ -3,6 +3,7 @@ package util
 import (
 	"testing"
 
+	"github.com/hashicorp/go-version"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	"github.com/stretchr/testify/require"
 	v1 "k8s.io/api/core/v1"
@@ -190,6 +191,26 @@ func TestGetImageMajorVersion(t *testing.T) {
 	require.Equal(t, -1, ver)
 }
 
+func TestVersionSupportsPSP(t *testing.T) {
+	k8sVersion, _ := version.NewVersion("1.24.9")
+	require.True(t, VersionSupportsPSP(k8sVersion))
+
+	k8sVersion, _ = version.NewVersion("1.24.17-gke.2300")
+	require.True(t, VersionSupportsPSP(k8sVersion))
+
+	k8sVersion, _ = version.NewVersion("1.25.0")
+	require.False(t, VersionSupportsPSP(k8sVersion))
+
+	k8sVersion, _ = version.NewVersion("1.25.0-gke.1000")
+	require.False(t, VersionSupportsPSP(k8sVersion))
+
+	k8sVersion, _ = version.NewVersion("1.25.0-rc.1")
+	require.False(t, VersionSupportsPSP(k8sVersion))
+
+	k8sVersion, _ = version.NewVersion("1.26.1")
+	require.False(t, VersionSupportsPSP(k8sVersion))
+}
+
 func TestPartialSecretRef(t *testing.T) {
 	// happy
 	obj := &corev1.SecretRef{